@@ -2,14 +2,26 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/urfave/cli/v3"
 
 	"skli/internal/app"
 	"skli/internal/config"
+	"skli/internal/convert"
+	"skli/internal/editors"
+	"skli/internal/gitrepo"
+	"skli/internal/project"
+	"skli/internal/provenance"
+	"skli/internal/scaffold"
+	"skli/internal/security"
+	"skli/internal/skillvars"
+	"skli/internal/validate"
 )
 
 var (
@@ -26,17 +38,67 @@ func main() {
 	cfg, _ := config.LoadConfig()
 	service := app.NewService(cfg)
 
-	if err := buildCLI(service).Run(context.Background(), os.Args); err != nil {
+	if err := buildCLI(service, configure(cfg)).Run(context.Background(), os.Args); err != nil {
 		fmt.Println(errorStyle.Render(fmt.Sprintf("✘ %v", err)))
 		os.Exit(1)
 	}
 }
 
-func buildCLI(service app.Service) *cli.Command {
+// configure aplica las secciones de la config que leen los paquetes internos. El error no se
+// comprueba aquí: cada comando decide si las necesita (ver needsConfig).
+func configure(cfg config.Config) error {
+	var errs []error
+	if err := gitrepo.SetHostProviders(cfg.Hosts); err != nil {
+		errs = append(errs, fmt.Errorf("invalid hosts in %s: %w", config.GetConfigPath(), err))
+	}
+	if err := editors.Configure(cfg.Editors); err != nil {
+		errs = append(errs, fmt.Errorf("invalid editors in %s: %w", config.GetConfigPath(), err))
+	}
+	if err := security.Configure(cfg.Security); err != nil {
+		errs = append(errs, fmt.Errorf("invalid security settings in %s: %w", config.GetConfigPath(), err))
+	}
+	if err := provenance.Configure(cfg.Provenance); err != nil {
+		errs = append(errs, fmt.Errorf("invalid provenance settings in %s: %w", config.GetConfigPath(), err))
+	}
+	return errors.Join(errs...)
+}
+
+// needsConfig indica si el comando usa [hosts], [editors], [security] o [provenance].
+// 'lock merge' lo lanza git a mitad de un merge, 'config' es el comando para arreglar
+// la configuración y 'version' y 'update' no la leen: con una config inválida solo avisan.
+func needsConfig(args []string) bool {
+	if len(args) == 0 {
+		return true
+	}
+	switch args[0] {
+	case "config", "version", "update":
+		return false
+	case "lock":
+		return len(args) < 2 || args[1] != "merge"
+	}
+	return true
+}
+
+func buildCLI(service app.Service, configErr error) *cli.Command {
 	return &cli.Command{
 		Name:      "skli",
 		Usage:     "skill manager",
-		UsageText: "skli [command] [arguments]",
+		UsageText: "skli [--project dir] [command] [arguments]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "project",
+				Usage: "project root (default: nearest dir with skli.lock or .git)",
+			},
+		},
+		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+			if configErr != nil {
+				if needsConfig(cmd.Args().Slice()) {
+					return ctx, configErr
+				}
+				fmt.Fprintln(os.Stderr, dimStyle.Render(fmt.Sprintf("⚠ %v", configErr)))
+			}
+			return ctx, project.SetRoot(cmd.String("project"))
+		},
 		CommandNotFound: func(ctx context.Context, c *cli.Command, s string) {
 			fmt.Println(errorStyle.Render(fmt.Sprintf("✘ unknown command: %s", s)))
 			fmt.Println("Use --help to see available commands.")
@@ -46,11 +108,29 @@ func buildCLI(service app.Service) *cli.Command {
 				Name:      "add",
 				Usage:     "install skills from a repo or open the TUI selector",
 				ArgsUsage: "[git-repo-path]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "editor", Aliases: []string{"e"}, Usage: "install into this editor without asking (see 'skli editors'), or 'auto'"},
+					&cli.StringSliceFlag{Name: "set", Usage: "value of a skill variable as name=value (repeatable)"},
+				},
 				Action: func(_ context.Context, cmd *cli.Command) error {
 					if cmd.NArg() > 1 {
-						return cli.Exit("usage: skli add [git-repo-path]", 1)
+						return cli.Exit("usage: skli add [--editor id] [--set name=value...] [git-repo-path]", 1)
+					}
+					values, err := skillvars.ParseAssignments(cmd.StringSlice("set"))
+					if err != nil {
+						return err
+					}
+					return service.WithEditor(cmd.String("editor")).WithValues(values).Add(cmd.Args().First())
+				},
+			},
+			{
+				Name:  "editors",
+				Usage: "list the editors skills can be installed into",
+				Action: func(_ context.Context, cmd *cli.Command) error {
+					if cmd.NArg() != 0 {
+						return cli.Exit("usage: skli editors", 1)
 					}
-					return service.Add(cmd.Args().First())
+					return renderEditors(service)
 				},
 			},
 			{
@@ -75,11 +155,14 @@ func buildCLI(service app.Service) *cli.Command {
 			{
 				Name:  "sync",
 				Usage: "sync installed skills with their source repo",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{Name: "yes", Aliases: []string{"y"}, Usage: "accept security findings when the policy is 'confirm'"},
+				},
 				Action: func(_ context.Context, cmd *cli.Command) error {
 					if cmd.NArg() != 0 {
-						return cli.Exit("usage: skli sync", 1)
+						return cli.Exit("usage: skli sync [--yes]", 1)
 					}
-					return renderSync(service)
+					return renderSync(service.WithConfirm(cmd.Bool("yes")))
 				},
 			},
 			{
@@ -92,27 +175,213 @@ func buildCLI(service app.Service) *cli.Command {
 					return service.ListTUI()
 				},
 			},
+			{
+				Name:      "new",
+				Usage:     "create a skill from a template (opens a form without a name or description)",
+				ArgsUsage: "[skill-name]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "template", Aliases: []string{"t"}, Usage: "template to use (see --list)"},
+					&cli.StringFlag{Name: "description", Aliases: []string{"d"}, Usage: "skill description"},
+					&cli.StringSliceFlag{Name: "tag", Usage: "tag to add (repeatable)"},
+					&cli.BoolFlag{Name: "list", Usage: "list the available templates"},
+				},
+				Action: func(_ context.Context, cmd *cli.Command) error {
+					if cmd.NArg() > 1 {
+						return cli.Exit("usage: skli new [--template name] [--description text] [--tag tag...] [skill-name]", 1)
+					}
+					if cmd.Bool("list") {
+						return renderTemplates(service)
+					}
+					opts := scaffold.Options{
+						Name:        cmd.Args().First(),
+						Description: cmd.String("description"),
+						Tags:        cmd.StringSlice("tag"),
+					}
+					if opts.Name == "" || opts.Description == "" {
+						warnings, err := service.NewSkillTUI(cmd.String("template"), opts)
+						printWarnings(warnings)
+						return err
+					}
+
+					result, err := service.NewSkill(cmd.String("template"), opts)
+					printWarnings(result.Warnings)
+					if result.Path != "" {
+						fmt.Println(successStyle.Render(fmt.Sprintf("✔ %s created in %s (template %s)", opts.Name, result.Path, result.Template.Name)))
+					}
+					return err
+				},
+			},
+			{
+				Name:      "import",
+				Usage:     "convert editor rule files (Cursor .mdc, Copilot instructions, Windsurf rules) into skills",
+				ArgsUsage: "<file-or-dir>",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "from", Usage: "format to import (default: detected from each file name)"},
+					&cli.BoolFlag{Name: "list", Usage: "list the available formats"},
+				},
+				Action: func(_ context.Context, cmd *cli.Command) error {
+					if cmd.Bool("list") {
+						return renderConverters()
+					}
+					if cmd.NArg() != 1 {
+						return cli.Exit("usage: skli import [--from format] <file-or-dir>", 1)
+					}
+					return renderImport(service, cmd.String("from"), cmd.Args().First())
+				},
+			},
+			{
+				Name:      "lint",
+				Usage:     "check a skill or a repo of skills and exit non-zero on errors",
+				ArgsUsage: "[path]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "format", Aliases: []string{"f"}, Value: "text", Usage: "output format: text, json or sarif"},
+					&cli.BoolFlag{Name: "strict", Usage: "fail on warnings too"},
+				},
+				Action: func(_ context.Context, cmd *cli.Command) error {
+					if cmd.NArg() > 1 {
+						return cli.Exit("usage: skli lint [--format text|json|sarif] [--strict] [path]", 1)
+					}
+					return renderLint(service, cmd.Args().First(), cmd.String("format"), cmd.Bool("strict"))
+				},
+			},
 			{
 				Name:      "upload",
 				Usage:     "upload local skills to a target repo",
-				ArgsUsage: "[git-dest-repo-path] [local-skill-path]",
+				ArgsUsage: "[git-dest-repo-path] [local-skill-path...]",
+				Flags: append(prFlags(),
+					&cli.StringFlag{Name: "dest", Usage: "path of the skill in the target repo (single skill only)"},
+					&cli.BoolFlag{Name: "dry-run", Usage: "validate and print the diff without pushing or opening a PR"},
+					&cli.BoolFlag{Name: "register", Usage: "track the uploaded skills in skli.lock, pending until the PR merges"},
+				),
 				Action: func(_ context.Context, cmd *cli.Command) error {
-					if cmd.NArg() != 0 && cmd.NArg() != 2 {
-						return cli.Exit("usage: skli upload [git-dest-repo-path] [local-skill-path]", 1)
+					service, err := withPRFlags(service, cmd)
+					if err != nil {
+						return err
+					}
+					if cmd.NArg() == 1 {
+						return cli.Exit("usage: skli upload [git-dest-repo-path] [local-skill-path...]", 1)
 					}
-					if cmd.NArg() == 2 {
-						result, err := service.UploadDirect(cmd.Args().Get(0), cmd.Args().Get(1))
+					if cmd.NArg() >= 2 {
+						target := cmd.Args().Get(0)
+						paths := cmd.Args().Slice()[1:]
+						if cmd.Bool("dry-run") {
+							return renderDryRun(service, target, paths, cmd.String("dest"))
+						}
+						fmt.Println(infoStyle.Render(fmt.Sprintf("🔄 Uploading %d skill(s) to %s...", len(paths), target)))
+						result, err := service.WithRegister(cmd.Bool("register")).UploadDirect(target, paths, cmd.String("dest"))
 						if err != nil {
+							if result.PRURL != "" {
+								fmt.Println(dimStyle.Render(result.PRURL))
+							}
 							return err
 						}
-						fmt.Println(infoStyle.Render(fmt.Sprintf("🔄 Uploading %s to %s...", result.Skill.Name, cmd.Args().Get(0))))
+						printWarnings(result.Warnings)
+						for _, sk := range result.Skills {
+							fmt.Println(successStyle.Render(fmt.Sprintf("  ✔ %s → %s", sk.Name, sk.RepoPath)))
+						}
+						for _, name := range result.Unchanged {
+							fmt.Println(dimStyle.Render(fmt.Sprintf("  ○ %s unchanged", name)))
+						}
+						if result.Fork != "" {
+							fmt.Println(dimStyle.Render(fmt.Sprintf("  ↳ pushed to fork %s", result.Fork)))
+						}
 						fmt.Println(successStyle.Render("✔ PR created"))
 						fmt.Println(dimStyle.Render(result.PRURL))
+						if len(result.Registered) > 0 {
+							fmt.Println(dimStyle.Render(fmt.Sprintf("  %d skill(s) registered in skli.lock, pending until the PR merges", len(result.Registered))))
+						}
 						return nil
 					}
 					return service.UploadTUI()
 				},
 			},
+			{
+				Name:      "contribute",
+				Usage:     "propose local changes of an installed skill to its source repo",
+				ArgsUsage: "<skill-name>",
+				Flags:     prFlags(),
+				Action: func(_ context.Context, cmd *cli.Command) error {
+					service, err := withPRFlags(service, cmd)
+					if err != nil {
+						return err
+					}
+					if cmd.NArg() != 1 {
+						return cli.Exit("usage: skli contribute <skill-name>", 1)
+					}
+					name := cmd.Args().First()
+					fmt.Println(infoStyle.Render(fmt.Sprintf("🔄 Contributing %s to its source repo...", name)))
+					result, err := service.Contribute(name)
+					if err != nil {
+						return err
+					}
+					printWarnings(result.Warnings)
+					fmt.Println(successStyle.Render(fmt.Sprintf("  ✔ %s (rebased onto %s)", result.RepoPath, result.Base)))
+					if result.Fork != "" {
+						fmt.Println(dimStyle.Render(fmt.Sprintf("  ↳ pushed to fork %s", result.Fork)))
+					}
+					fmt.Println(successStyle.Render("✔ PR created"))
+					fmt.Println(dimStyle.Render(result.PRURL))
+					return nil
+				},
+			},
+			{
+				Name:  "verify",
+				Usage: "check installed skills against the hashes recorded in skli.lock",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "fix",
+						Usage: "restore modified skills from the locked commit",
+					},
+				},
+				Action: func(_ context.Context, cmd *cli.Command) error {
+					if cmd.NArg() != 0 {
+						return cli.Exit("usage: skli verify [--fix]", 1)
+					}
+					return renderVerify(service, cmd.Bool("fix"))
+				},
+			},
+			{
+				Name:  "lock",
+				Usage: "maintain the skli.lock file",
+				Commands: []*cli.Command{
+					{
+						Name:  "upgrade",
+						Usage: "rewrite skli.lock in the current format and backfill tree hashes",
+						Action: func(_ context.Context, cmd *cli.Command) error {
+							if cmd.NArg() != 0 {
+								return cli.Exit("usage: skli lock upgrade", 1)
+							}
+							return renderLockUpgrade(service)
+						},
+					},
+					{
+						Name:      "merge",
+						Usage:     "merge two versions of skli.lock (git merge driver, writes the result to <ours>)",
+						ArgsUsage: "<base> <ours> <theirs>",
+						Action: func(_ context.Context, cmd *cli.Command) error {
+							if cmd.NArg() != 3 {
+								return cli.Exit("usage: skli lock merge <base> <ours> <theirs>", 1)
+							}
+							return service.MergeLock(cmd.Args().Get(0), cmd.Args().Get(1), cmd.Args().Get(2))
+						},
+					},
+					{
+						Name:  "install-driver",
+						Usage: "register 'skli lock merge' as the git merge driver for skli.lock",
+						Action: func(_ context.Context, cmd *cli.Command) error {
+							if cmd.NArg() != 0 {
+								return cli.Exit("usage: skli lock install-driver", 1)
+							}
+							if err := service.InstallMergeDriver(); err != nil {
+								return err
+							}
+							fmt.Println(successStyle.Render("✔ merge driver registered in .gitattributes and .git/config"))
+							fmt.Println(dimStyle.Render("  commit .gitattributes; each clone needs 'skli lock install-driver' once"))
+							return nil
+						},
+					},
+				},
+			},
 			{
 				Name:  "config",
 				Usage: "open skli configuration",
@@ -165,6 +434,180 @@ func buildCLI(service app.Service) *cli.Command {
 	}
 }
 
+// prFlags son las opciones de PR comunes a upload y contribute
+func prFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{Name: "label", Usage: "label to add to the PR (repeatable)"},
+		&cli.StringSliceFlag{Name: "reviewer", Usage: "reviewer to request (repeatable)"},
+		&cli.BoolFlag{Name: "draft", Usage: "open the PR as draft"},
+		&cli.BoolFlag{Name: "fork", Usage: "push to a fork and open the PR from it"},
+		&cli.StringFlag{Name: "title", Usage: "PR title, used as-is (overrides pull_requests.title)"},
+		&cli.StringFlag{Name: "body-file", Usage: "file with the PR body, used as-is (overrides pull_requests.body)"},
+		&cli.StringSliceFlag{Name: "var", Usage: "template variable as key=value (repeatable)"},
+	}
+}
+
+func withPRFlags(service app.Service, cmd *cli.Command) (app.Service, error) {
+	var body string
+	if path := cmd.String("body-file"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return service, fmt.Errorf("error reading body file: %w", err)
+		}
+		body = string(data)
+	}
+
+	vars := make(map[string]string)
+	for _, kv := range cmd.StringSlice("var") {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return service, cli.Exit(fmt.Sprintf("invalid --var %q, expected key=value", kv), 1)
+		}
+		vars[strings.TrimSpace(key)] = value
+	}
+
+	service = service.WithPROptions(cmd.StringSlice("label"), cmd.StringSlice("reviewer"), cmd.Bool("draft"), cmd.Bool("fork"))
+	return service.WithPRTemplates(cmd.String("title"), body, vars), nil
+}
+
+func printWarnings(warnings []string) {
+	for _, w := range warnings {
+		fmt.Println(dimStyle.Render("  ⚠ " + w))
+	}
+}
+
+func renderDryRun(service app.Service, target string, paths []string, dest string) error {
+	fmt.Println(infoStyle.Render(fmt.Sprintf("🔍 Dry run: %d skill(s) to %s", len(paths), target)))
+	preview, err := service.PreviewUpload(target, paths, dest)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, sk := range preview.Skills {
+		fmt.Println(successStyle.Render(fmt.Sprintf("  ✔ %s → %s (%s)", sk.Name, sk.RepoPath, sk.Stats)))
+	}
+	for _, name := range preview.Unchanged {
+		fmt.Println(dimStyle.Render(fmt.Sprintf("  ○ %s unchanged", name)))
+	}
+	names := make([]string, 0, len(preview.Issues))
+	for name := range preview.Issues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, issue := range preview.Issues[name] {
+			if issue.Severity == validate.SeverityError {
+				failed++
+				fmt.Println(errorStyle.Render(fmt.Sprintf("  ✘ %s: %s", name, issue)))
+			} else {
+				fmt.Println(dimStyle.Render(fmt.Sprintf("  ⚠ %s: %s", name, issue)))
+			}
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(dimStyle.Render(fmt.Sprintf("branch: %s (against %s)", preview.Branch, preview.Base)))
+	fmt.Println(dimStyle.Render("title:  " + preview.Title))
+	fmt.Println()
+	fmt.Print(preview.Diff)
+
+	if failed > 0 {
+		return cli.Exit(fmt.Sprintf("validation failed with %d error(s), the upload would be refused", failed), 1)
+	}
+	return nil
+}
+
+func renderVerify(service app.Service, fix bool) error {
+	fmt.Println(infoStyle.Render("🔍 Verifying installed skills..."))
+	fmt.Println()
+
+	results, err := service.Verify(fix)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Println(infoStyle.Render("ℹ There are no installed skills to verify (skli.lock is empty)."))
+		return nil
+	}
+
+	failed := 0
+	for _, r := range results {
+		switch {
+		case r.Error != nil:
+			failed++
+			fmt.Println(errorStyle.Render(fmt.Sprintf("  ✘ %s: %v", r.Skill.Name, r.Error)))
+		case r.Signature != nil:
+			failed++
+			fmt.Println(errorStyle.Render(fmt.Sprintf("  ✘ %s: %v", r.Skill.Name, r.Signature)))
+		case r.Fixed:
+			fmt.Println(successStyle.Render(fmt.Sprintf("  ✔ %s restored from %s", r.Skill.Name, shortHash(r.Skill.CommitHash))))
+		case r.NoManifest:
+			failed++
+			fmt.Println(errorStyle.Render(fmt.Sprintf("  ✘ %s: no file hashes recorded (run 'skli verify --fix')", r.Skill.Name)))
+		case !r.Drift.Clean():
+			failed++
+			fmt.Println(errorStyle.Render(fmt.Sprintf("  ✘ %s (%s)", r.Skill.Name, r.Skill.Path)))
+			for _, f := range r.Drift.Added {
+				fmt.Println(dimStyle.Render("      + " + f))
+			}
+			for _, f := range r.Drift.Removed {
+				fmt.Println(dimStyle.Render("      - " + f))
+			}
+			for _, f := range r.Drift.Modified {
+				fmt.Println(dimStyle.Render("      ~ " + f))
+			}
+		default:
+			fmt.Println(dimStyle.Render(fmt.Sprintf("  ○ %s ok", r.Skill.Name)))
+		}
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		return cli.Exit(fmt.Sprintf("%d of %d skills do not match skli.lock", failed, len(results)), 1)
+	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("✔ All %d skills match skli.lock.", len(results))))
+	return nil
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+func renderLockUpgrade(service app.Service) error {
+	fmt.Println(infoStyle.Render("🔄 Upgrading skli.lock..."))
+
+	report, err := service.UpgradeLock()
+	if err != nil {
+		return err
+	}
+
+	if report.To > report.From {
+		fmt.Println(successStyle.Render(fmt.Sprintf("  ✔ migrated from version %d to %d", report.From, report.To)))
+	} else if !report.Pending && report.Backfilled == 0 {
+		fmt.Println(dimStyle.Render(fmt.Sprintf("  ○ already at version %d", report.To)))
+	}
+	if report.Backfilled > 0 {
+		fmt.Println(successStyle.Render(fmt.Sprintf("  ✔ %d tree hashes backfilled", report.Backfilled)))
+	}
+	failed := make([]string, 0, len(report.Failed))
+	for path := range report.Failed {
+		failed = append(failed, path)
+	}
+	sort.Strings(failed)
+	for _, path := range failed {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("  ✘ %s: %v", path, report.Failed[path])))
+	}
+
+	if report.Pending {
+		return cli.Exit(fmt.Sprintf("%d skills could not be upgraded; skli.lock stays marked as pending, run 'skli lock upgrade' again", len(report.Failed)), 1)
+	}
+	return nil
+}
+
 func renderSync(service app.Service) error {
 	fmt.Println(infoStyle.Render("🔄 Syncing skills..."))
 	fmt.Println()
@@ -183,11 +626,21 @@ func renderSync(service app.Service) error {
 	for _, r := range summary.Results {
 		if r.Error != nil {
 			fmt.Println(errorStyle.Render(fmt.Sprintf("  ✘ %s: %v", r.SkillName, r.Error)))
+		} else if r.Pending {
+			fmt.Println(dimStyle.Render(fmt.Sprintf("  ⧗ %s waiting for PR %s", r.SkillName, r.PRURL)))
+		} else if r.Merged {
+			fmt.Println(successStyle.Render(fmt.Sprintf("  ✔ %s merged, now tracking the default branch", r.SkillName)))
 		} else if r.Updated {
 			fmt.Println(successStyle.Render(fmt.Sprintf("  ✔ %s updated", r.SkillName)))
 		} else if r.Skipped {
 			fmt.Println(dimStyle.Render(fmt.Sprintf("  ○ %s unchanged", r.SkillName)))
 		}
+		for _, f := range r.Findings {
+			fmt.Println(dimStyle.Render(fmt.Sprintf("      ⚠ %s", f)))
+		}
+		if r.Unsigned != nil && r.Error == nil {
+			fmt.Println(dimStyle.Render(fmt.Sprintf("      ⚠ %v", r.Unsigned)))
+		}
 	}
 
 	fmt.Println()
@@ -198,5 +651,124 @@ func renderSync(service app.Service) error {
 	} else {
 		fmt.Println(successStyle.Render(fmt.Sprintf("✔ %d skills updated, %d unchanged.", summary.Updated, summary.Skipped)))
 	}
+	if summary.Pending > 0 {
+		fmt.Println(dimStyle.Render(fmt.Sprintf("%d skill(s) waiting for their PR to merge.", summary.Pending)))
+	}
+	return nil
+}
+
+func renderTemplates(service app.Service) error {
+	templates, warnings := service.Templates()
+	printWarnings(warnings)
+	for _, tpl := range templates {
+		line := fmt.Sprintf("  %-12s %s", tpl.Name, tpl.Description)
+		if tpl.Source != "" {
+			line += dimStyle.Render(" (" + tpl.Source + ")")
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func renderEditors(service app.Service) error {
+	for _, t := range service.Editors() {
+		line := fmt.Sprintf("  %-16s %-24s %-22s", t.ID(), t.Name(), t.ProjectPath())
+		if converter := editors.Converter(t); converter != "" {
+			line += dimStyle.Render(" (" + converter + ")")
+		}
+		if t.GlobalPath() != "" {
+			line += dimStyle.Render(" global: " + t.GlobalPath())
+		}
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+	return nil
+}
+
+func renderConverters() error {
+	for _, c := range convert.All() {
+		fmt.Printf("  %-22s %s\n", c.ID(), c.Name())
+	}
+	return nil
+}
+
+func renderImport(service app.Service, from, path string) error {
+	results, err := service.Import(from, path)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, r := range results {
+		printWarnings(r.Warnings)
+		switch {
+		case r.Err != nil && r.Path != "":
+			failed++
+			fmt.Println(errorStyle.Render(fmt.Sprintf("✘ %s imported to %s but it does not validate: %v", r.Source, r.Path, r.Err)))
+		case r.Err != nil:
+			failed++
+			fmt.Println(errorStyle.Render(fmt.Sprintf("✘ %s: %v", r.Source, r.Err)))
+		default:
+			fmt.Println(successStyle.Render(fmt.Sprintf("✔ %s imported to %s", r.Source, r.Path)))
+		}
+	}
+	if failed > 0 {
+		return cli.Exit(fmt.Sprintf("%d of %d files could not be imported", failed, len(results)), 1)
+	}
+	return nil
+}
+
+func renderLint(service app.Service, path, format string, strict bool) error {
+	if format != "text" && format != "json" && format != "sarif" {
+		return cli.Exit(fmt.Sprintf("unknown format %q, expected text, json or sarif", format), 1)
+	}
+	report, err := service.Lint(path)
+	if err != nil {
+		return err
+	}
+
+	errs, warnings := report.Count()
+	failed := errs > 0 || (strict && warnings > 0)
+	switch format {
+	case "json":
+		if err := report.WriteJSON(os.Stdout); err != nil {
+			return err
+		}
+	case "sarif":
+		if err := report.WriteSARIF(os.Stdout, version); err != nil {
+			return err
+		}
+	default:
+		for _, sk := range report.Skills {
+			if len(sk.Issues) == 0 {
+				fmt.Println(dimStyle.Render(fmt.Sprintf("  ○ %s ok", sk.Name)))
+				continue
+			}
+			fmt.Println(infoStyle.Render(fmt.Sprintf("  %s (%s)", sk.Name, sk.Path)))
+			for _, issue := range sk.Issues {
+				loc := issue.Path
+				if issue.Line > 0 {
+					loc = fmt.Sprintf("%s:%d", loc, issue.Line)
+				}
+				if loc != "" {
+					loc += ": "
+				}
+				text := fmt.Sprintf("%s%s [%s]", loc, issue.Message, issue.Rule)
+				if issue.Severity == validate.SeverityError {
+					fmt.Println(errorStyle.Render("    ✘ " + text))
+				} else {
+					fmt.Println(dimStyle.Render("    ⚠ " + text))
+				}
+			}
+		}
+		fmt.Println()
+		if failed {
+			return cli.Exit(fmt.Sprintf("lint failed: %d error(s), %d warning(s) in %d skill(s)", errs, warnings, len(report.Skills)), 1)
+		}
+		fmt.Println(successStyle.Render(fmt.Sprintf("✔ %d skill(s) checked: %d error(s), %d warning(s).", len(report.Skills), errs, warnings)))
+		return nil
+	}
+
+	if failed {
+		return cli.Exit("", 1)
+	}
 	return nil
 }
@@ -1,32 +1,122 @@
 package app
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"skli/internal/config"
+	"skli/internal/convert"
 	"skli/internal/db"
+	"skli/internal/editors"
 	"skli/internal/gitrepo"
+	"skli/internal/project"
+	"skli/internal/scaffold"
 	"skli/internal/skills"
 	sklisync "skli/internal/sync"
 	"skli/internal/tui"
 	"skli/internal/tui/screens/manage"
+	"skli/internal/validate"
 )
 
 // Service encapsula casos de uso de la app.
 type Service struct {
-	cfg config.Config
+	cfg   config.Config
+	fork  bool
+	title string            // Plantilla del título para esta ejecución (--title)
+	body  string            // Plantilla del cuerpo para esta ejecución (--body-file)
+	vars  map[string]string // Variables extra de las plantillas (--var)
+
+	register bool              // Registrar en skli.lock los skills subidos (--register)
+	editor   string            // Destino (id o "auto") en el que instalar sin pasar por la pantalla de editores (--editor)
+	values   map[string]string // Valores de las variables de los skills a instalar (--set)
+	confirm  bool              // Aceptar los hallazgos de seguridad que la política confirm pide confirmar (--yes)
 }
 
 func NewService(cfg config.Config) Service {
 	return Service{cfg: cfg}
 }
 
+// WithPROptions devuelve una copia del servicio que añade labels y reviewers a los configurados,
+// con draft abre las PRs como borrador y con fork publica siempre la rama en un fork.
+func (s Service) WithPROptions(labels, reviewers []string, draft, fork bool) Service {
+	s.fork = s.fork || fork
+	pr := s.cfg.PullRequests
+	pr.Labels = append(append([]string{}, pr.Labels...), labels...)
+	pr.Reviewers = append(append([]string{}, pr.Reviewers...), reviewers...)
+	pr.Draft = pr.Draft || draft
+	s.cfg.PullRequests = pr
+	return s
+}
+
+// WithPRTemplates devuelve una copia del servicio que usa title y body como plantillas de la PR
+// en lugar de las configuradas (vacías = las de la config) y añade vars a las variables.
+func (s Service) WithPRTemplates(title, body string, vars map[string]string) Service {
+	if title != "" {
+		s.title = title
+	}
+	if body != "" {
+		s.body = body
+	}
+	merged := make(map[string]string, len(s.vars)+len(vars))
+	for k, v := range s.vars {
+		merged[k] = v
+	}
+	for k, v := range vars {
+		merged[k] = v
+	}
+	s.vars = merged
+	return s
+}
+
+// WithRegister devuelve una copia del servicio que registra en skli.lock los skills que sube,
+// pendientes de la PR hasta que sync la detecte fusionada.
+func (s Service) WithRegister(register bool) Service {
+	s.register = register
+	return s
+}
+
+// WithEditor devuelve una copia del servicio que instala en el destino id, o en todos los
+// detectados en el proyecto con editors.Auto
+func (s Service) WithEditor(id string) Service {
+	s.editor = id
+	return s
+}
+
+// WithValues devuelve una copia del servicio que da esos valores a las variables de los skills
+func (s Service) WithValues(values map[string]string) Service {
+	s.values = values
+	return s
+}
+
+// WithConfirm devuelve una copia del servicio que acepta los hallazgos de seguridad de los
+// skills que sincroniza cuando la política es confirm
+func (s Service) WithConfirm(confirm bool) Service {
+	s.confirm = confirm
+	return s
+}
+
 func (s Service) Add(initialURL string) error {
-	return s.runTUI(initialURL, false, manage.ModeNone)
+	var ids []string
+	if s.editor != "" {
+		targets, err := editors.Resolve(s.editor, project.Root())
+		if err != nil {
+			return err
+		}
+		for _, t := range targets {
+			ids = append(ids, t.ID())
+		}
+	}
+	return s.runTUIWithEditors(initialURL, false, manage.ModeNone, ids)
+}
+
+// Editors devuelve los destinos de instalación disponibles
+func (s Service) Editors() []editors.Target {
+	return editors.All()
 }
 
 func (s Service) RemoveTUI() error {
@@ -45,6 +135,16 @@ func (s Service) ConfigTUI() error {
 	return s.runTUI("", true, manage.ModeNone)
 }
 
+// prOptions construye las opciones de PR a partir de la config y de los flags de la ejecución
+func (s Service) prOptions() gitrepo.PROptions {
+	opts := gitrepo.NewPROptions(s.cfg.PullRequests)
+	opts.Fork = s.fork
+	opts.Title = s.title
+	opts.Body = s.body
+	opts.Vars = s.vars
+	return opts
+}
+
 func (s Service) RemoveByName(name string) (db.InstalledSkill, error) {
 	return skills.DeleteByName(name, s.cfg.LocalPath)
 }
@@ -62,31 +162,103 @@ func (s Service) UpdateSelf() error {
 }
 
 type UploadResult struct {
-	Skill db.InstalledSkill
-	PRURL string
+	Skills    []gitrepo.UploadedSkill
+	Unchanged []string
+	PRURL     string
+	Fork      string
+	Warnings  []string
+
+	Registered []db.InstalledSkill // Entradas añadidas a skli.lock con --register
 }
 
-func (s Service) UploadDirect(targetRepo, localSkillPath string) (UploadResult, error) {
-	skill, err := skills.PrepareLocalForUpload(localSkillPath)
+// UploadDirect sube uno o varios skills locales al repo destino en una única PR.
+// dest fija la ruta del skill en el repo y solo se admite al subir uno.
+func (s Service) UploadDirect(targetRepo string, localSkillPaths []string, dest string) (UploadResult, error) {
+	selected, dests, err := prepareDirectUpload(localSkillPaths, dest)
 	if err != nil {
 		return UploadResult{}, err
 	}
-
-	prURL, err := gitrepo.UploadSkill(skill, targetRepo)
+	if s.register {
+		if err := checkRegistrable(selected); err != nil {
+			return UploadResult{}, err
+		}
+	}
+	res, err := gitrepo.UploadSkills(selected, targetRepo, dests, s.prOptions())
 	if err != nil {
 		return UploadResult{}, err
 	}
 
-	return UploadResult{
-		Skill: skill,
-		PRURL: prURL,
-	}, nil
+	out := UploadResult{
+		Skills:    res.Skills,
+		Unchanged: res.Unchanged,
+		PRURL:     res.PRURL,
+		Fork:      res.Fork,
+		Warnings:  res.Warnings,
+	}
+	if s.register {
+		out.Registered, err = skills.RegisterUploads(selected, targetRepo, res)
+		if err != nil {
+			return out, fmt.Errorf("PR created but the skills were not registered: %w", err)
+		}
+	}
+	return out, nil
+}
+
+// checkRegistrable comprueba antes de subir que los skills se pueden registrar en el lock del proyecto
+func checkRegistrable(selected []db.InstalledSkill) error {
+	for _, sk := range selected {
+		if filepath.IsAbs(project.Rel(sk.Path)) {
+			return fmt.Errorf("cannot register '%s': %s is outside the project", sk.Name, sk.Path)
+		}
+	}
+	return nil
+}
+
+// PreviewUpload muestra qué subiría UploadDirect sin hacer push ni abrir la PR (--dry-run).
+func (s Service) PreviewUpload(targetRepo string, localSkillPaths []string, dest string) (gitrepo.UploadPreview, error) {
+	selected, dests, err := prepareDirectUpload(localSkillPaths, dest)
+	if err != nil {
+		return gitrepo.UploadPreview{}, err
+	}
+	return gitrepo.PreviewUpload(selected, targetRepo, dests, s.prOptions())
+}
+
+func prepareDirectUpload(localSkillPaths []string, dest string) ([]db.InstalledSkill, map[string]string, error) {
+	if dest != "" && len(localSkillPaths) != 1 {
+		return nil, nil, fmt.Errorf("--dest can only be used when uploading a single skill")
+	}
+
+	selected := make([]db.InstalledSkill, 0, len(localSkillPaths))
+	for _, p := range localSkillPaths {
+		skill, err := skills.PrepareLocalForUpload(p)
+		if err != nil {
+			return nil, nil, err
+		}
+		selected = append(selected, skill)
+	}
+
+	var dests map[string]string
+	if dest != "" {
+		dests = map[string]string{selected[0].Name: dest}
+	}
+	return selected, dests, nil
+}
+
+// Contribute propone al repo de origen los cambios locales de un skill instalado.
+func (s Service) Contribute(name string) (gitrepo.ContributeResult, error) {
+	skill, err := skills.FindInstalledByName(name, s.cfg.LocalPath)
+	if err != nil {
+		return gitrepo.ContributeResult{}, err
+	}
+	return gitrepo.ContributeSkill(skill, s.prOptions())
 }
 
 type SyncSummary struct {
 	Results []sklisync.SyncResult
 	Updated int
 	Skipped int
+	Pending int // Skills subidos cuya PR sigue abierta
+	Merged  int // Skills cuya PR se ha fusionado en esta ejecución
 	Errors  int
 }
 
@@ -97,7 +269,7 @@ type ListedSkill struct {
 }
 
 func (s Service) SyncAll() (SyncSummary, error) {
-	results, err := sklisync.SyncAllSkills()
+	results, err := sklisync.SyncAllSkills(s.confirm)
 	if err != nil {
 		return SyncSummary{}, err
 	}
@@ -108,6 +280,13 @@ func (s Service) SyncAll() (SyncSummary, error) {
 			summary.Errors++
 			continue
 		}
+		if r.Pending {
+			summary.Pending++
+			continue
+		}
+		if r.Merged {
+			summary.Merged++
+		}
 		if r.Updated {
 			summary.Updated++
 			continue
@@ -120,6 +299,60 @@ func (s Service) SyncAll() (SyncSummary, error) {
 	return summary, nil
 }
 
+// Verify comprueba los skills instalados contra el manifiesto del lock; con fix restaura los alterados.
+func (s Service) Verify(fix bool) ([]sklisync.VerifyResult, error) {
+	return sklisync.VerifyAll(fix)
+}
+
+// UpgradeLock reescribe skli.lock en el formato actual, consultando los remotos para los tree hash
+// y los manifiestos y deduciendo el destino de cada skill de su carpeta.
+func (s Service) UpgradeLock() (db.MigrationReport, error) {
+	return db.UpgradeLockFile(db.MigrateOptions{
+		ResolveTreeHashes: gitrepo.RemoteTreeHashes,
+		ResolveManifest:   sklisync.SourceManifest,
+		ResolveEditor:     builtInEditor,
+	})
+}
+
+// builtInEditor resuelve el destino de los skills de locks antiguos solo con los destinos incluidos:
+// con los de config.toml cada máquina migraría el mismo lock de forma distinta.
+func builtInEditor(dir string) string {
+	if t, ok := editors.BuiltInForPath(dir); ok {
+		return t.ID()
+	}
+	return ""
+}
+
+// MergeLock resuelve un merge de skli.lock como merge driver de git: escribe el resultado en oursPath.
+func (s Service) MergeLock(basePath, oursPath, theirsPath string) error {
+	base, err := db.ReadLockFileAt(basePath)
+	if err != nil {
+		return fmt.Errorf("base: %w", err)
+	}
+	ours, err := db.ReadLockFileAt(oursPath)
+	if err != nil {
+		return fmt.Errorf("ours: %w", err)
+	}
+	theirs, err := db.ReadLockFileAt(theirsPath)
+	if err != nil {
+		return fmt.Errorf("theirs: %w", err)
+	}
+
+	// El resultado depende solo de las tres versiones: el árbol de trabajo está a medio mergear,
+	// así que no se migra aquí; lo que falte queda marcado con pending_upgrade para 'skli lock upgrade'.
+	return db.WriteLockFileAt(oursPath, db.MergeLockFiles(base, ours, theirs))
+}
+
+// InstallMergeDriver registra 'skli lock merge' como merge driver de skli.lock en el repo del proyecto.
+func (s Service) InstallMergeDriver() error {
+	return gitrepo.RegisterMergeDriver(project.Root(), gitrepo.MergeDriver{
+		ID:      "skli-lock",
+		Name:    "skli.lock merge driver",
+		Command: "skli lock merge %O %A %B",
+		Pattern: project.LockFileName,
+	})
+}
+
 func (s Service) ListSkills() ([]ListedSkill, error) {
 	lock, err := db.LoadLockFile()
 	if err != nil {
@@ -142,9 +375,112 @@ func (s Service) ListSkills() ([]ListedSkill, error) {
 	return out, nil
 }
 
+// Lint valida el skill o el repo de skills de dir (vacío = raíz del proyecto).
+// Las rutas del informe son relativas al proyecto.
+func (s Service) Lint(dir string) (validate.Report, error) {
+	if dir == "" {
+		dir = project.Root()
+	} else if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	report, err := validate.Lint(dir)
+	for i := range report.Skills {
+		report.Skills[i].Path = filepath.ToSlash(project.Rel(report.Skills[i].Path))
+	}
+	return report, err
+}
+
+// NewSkillResult es el resultado de crear un skill con 'skli new'
+type NewSkillResult struct {
+	Path     string
+	Template scaffold.Template
+	Warnings []string // Repos de plantillas que no se pudieron cargar y avisos de validación
+}
+
+// NewSkill crea un skill en LocalPath a partir de una plantilla incluida o de los repos configurados.
+// Si el skill se crea pero no valida, Path se rellena y se devuelve el *validate.Error.
+func (s Service) NewSkill(template string, opts scaffold.Options) (NewSkillResult, error) {
+	catalog := scaffold.LoadCatalog(s.cfg.Templates)
+	defer catalog.Close()
+
+	out := NewSkillResult{Warnings: catalog.Warnings}
+	tpl, err := catalog.Find(template)
+	if err != nil {
+		return out, err
+	}
+	out.Template = tpl
+	path, issues, err := scaffold.Create(tpl, s.cfg.LocalPath, opts)
+	out.Path = path
+	for _, issue := range issues {
+		out.Warnings = append(out.Warnings, issue.String())
+	}
+	return out, err
+}
+
+// Templates devuelve las plantillas disponibles y los repos de plantillas que no se pudieron cargar
+func (s Service) Templates() ([]scaffold.Template, []string) {
+	catalog := scaffold.LoadCatalog(s.cfg.Templates)
+	defer catalog.Close()
+	return catalog.Templates, catalog.Warnings
+}
+
+// NewSkillTUI abre el formulario de 'skli new' con los valores ya indicados.
+// Devuelve los avisos de los repos de plantillas que no se pudieron cargar.
+func (s Service) NewSkillTUI(template string, initial scaffold.Options) ([]string, error) {
+	catalog := scaffold.LoadCatalog(s.cfg.Templates)
+	defer catalog.Close()
+	if template != "" {
+		if _, err := catalog.Find(template); err != nil {
+			return catalog.Warnings, err
+		}
+	}
+
+	p := tea.NewProgram(
+		tui.NewRootModelForNewSkill(catalog.Templates, s.cfg.LocalPath, initial, template),
+		tea.WithAltScreen(),
+	)
+	_, err := p.Run()
+	return catalog.Warnings, err
+}
+
+// ImportResult es el resultado de importar un artefacto de editor
+type ImportResult struct {
+	Source   string
+	Path     string   // Carpeta del skill creado (vacía si falló)
+	Warnings []string // Avisos de validación
+	Err      error
+}
+
+// Import convierte en skills de LocalPath los artefactos de path (un fichero o un directorio).
+// from es el conversor a usar; vacío detecta el formato de cada fichero.
+func (s Service) Import(from, path string) ([]ImportResult, error) {
+	sources, err := convert.Sources(from, path)
+	if err != nil {
+		return nil, err
+	}
+	skillsRoot := s.cfg.LocalPath
+	if skillsRoot == "" {
+		skillsRoot = skills.DefaultRoot
+	}
+	results := make([]ImportResult, 0, len(sources))
+	for _, src := range sources {
+		rel, issues, err := convert.Import(from, src, skillsRoot)
+		result := ImportResult{Source: src, Path: rel, Err: err}
+		for _, issue := range issues {
+			result.Warnings = append(result.Warnings, issue.String())
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
 func (s Service) runTUI(initialURL string, configMode bool, manageMode manage.Mode) error {
+	return s.runTUIWithEditors(initialURL, configMode, manageMode, nil)
+}
+
+func (s Service) runTUIWithEditors(initialURL string, configMode bool, manageMode manage.Mode, editorIDs []string) error {
 	p := tea.NewProgram(
-		tui.NewRootModel(initialURL, s.cfg.LocalPath, s.cfg.LocalPath, configMode, manageMode, s.cfg.Remotes),
+		tui.NewRootModel(initialURL, s.cfg.LocalPath, s.cfg.LocalPath, configMode, manageMode, s.cfg.Remotes, editorIDs, s.values),
 		tea.WithAltScreen(),
 	)
 	_, err := p.Run()
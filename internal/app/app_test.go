@@ -3,7 +3,9 @@ package app
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"skli/internal/config"
 	"skli/internal/db"
@@ -69,3 +71,109 @@ func TestListSkillsIncludesManagedAndLocal(t *testing.T) {
 		t.Fatalf("local skill not found or incorrectly labeled")
 	}
 }
+
+func TestWithPROptionsExtendsConfig(t *testing.T) {
+	base := NewService(config.Config{PullRequests: config.PullRequestConfig{Labels: []string{"skills"}}})
+
+	s := base.WithPROptions([]string{"bot"}, []string{"alice"}, true, true)
+	pr := s.cfg.PullRequests
+	if len(pr.Labels) != 2 || pr.Labels[1] != "bot" || len(pr.Reviewers) != 1 || !pr.Draft {
+		t.Fatalf("unexpected options: %+v", pr)
+	}
+	if !s.prOptions().Fork || base.prOptions().Fork {
+		t.Fatalf("fork must only be set on the returned service")
+	}
+	if len(base.cfg.PullRequests.Labels) != 1 || base.cfg.PullRequests.Draft {
+		t.Fatalf("original service must not change: %+v", base.cfg.PullRequests)
+	}
+}
+
+func TestWithPRTemplatesOverridesConfig(t *testing.T) {
+	base := NewService(config.Config{PullRequests: config.PullRequestConfig{Title: "cfg {{ .Name }}"}})
+
+	s := base.WithPRTemplates("{{ .Vars.ticket }}: {{ .Name }}", "", map[string]string{"ticket": "SK-1"})
+	opts := s.prOptions()
+	if opts.Title != "{{ .Vars.ticket }}: {{ .Name }}" || opts.Templates.Title != "cfg {{ .Name }}" || opts.Vars["ticket"] != "SK-1" {
+		t.Fatalf("unexpected options: %+v", opts)
+	}
+	if base.prOptions().Title != "" || len(base.prOptions().Vars) != 0 {
+		t.Fatalf("original service must not change")
+	}
+}
+
+func TestUploadDirectDestRequiresSingleSkill(t *testing.T) {
+	s := NewService(config.Config{})
+	_, err := s.UploadDirect("git@example.com:acme/skills.git", []string{"a", "b"}, "skills/a")
+	if err == nil || !strings.Contains(err.Error(), "--dest") {
+		t.Fatalf("expected --dest error, got %v", err)
+	}
+}
+
+func TestMergeLockOnlyUsesItsInputs(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	tmp := t.TempDir()
+	if err := os.Chdir(tmp); err != nil {
+		t.Fatalf("chdir tmp: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+	})
+
+	// Árbol de trabajo a medio mergear: su contenido no debe acabar en el lock
+	if err := os.MkdirAll(filepath.Join("skills", "a"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("skills", "a", "SKILL.md"), []byte("---\nname: a\nversion: 9.9.9\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, content string) string {
+		path := filepath.Join(tmp, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	entry := "[[skills]]\n  name = \"a\"\n  path = \"skills/a\"\n  remote_repo = \"repo\"\n  remote_path = \"a\"\n"
+	base := write("base", entry)
+	oursContent := "last_updated = 2024-01-01T00:00:00Z\n\n" + entry
+	ours := write("ours", oursContent)
+	theirs := write("theirs", "version = 2\nlast_updated = 2024-02-01T00:00:00Z\n\n"+entry+"\n[[skills]]\n  name = \"b\"\n  path = \"skills/b\"\n  remote_repo = \"repo\"\n  remote_path = \"b\"\n")
+
+	service := NewService(config.Config{})
+	if err := service.MergeLock(base, ours, theirs); err != nil {
+		t.Fatalf("MergeLock: %v", err)
+	}
+	first, err := os.ReadFile(ours)
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, err := db.ReadLockFileAt(ours)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.Version != db.LockVersion || !merged.PendingUpgrade || len(merged.Skills) != 2 {
+		t.Fatalf("expected both skills in the newest format, with ours still pending, got %+v", merged)
+	}
+	if want := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC); !merged.LastUpdated.Equal(want) {
+		t.Fatalf("the merge must keep the newest last_updated, got %v", merged.LastUpdated)
+	}
+
+	// Repetir el merge con las mismas entradas produce los mismos bytes
+	write("ours", oursContent)
+	if err := service.MergeLock(base, ours, theirs); err != nil {
+		t.Fatalf("MergeLock again: %v", err)
+	}
+	second, err := os.ReadFile(ours)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(first) != string(second) {
+		t.Fatalf("the merge must be deterministic:\n%s\n---\n%s", first, second)
+	}
+	if merged.Skills[0].Files != nil || merged.Skills[0].Version != "" {
+		t.Fatalf("the merge must not read the working tree, got %+v", merged.Skills[0])
+	}
+}
@@ -9,8 +9,62 @@ import (
 )
 
 type Config struct {
-	LocalPath string   `toml:"local_path"`
-	Remotes   []string `toml:"remotes"`
+	LocalPath    string            `toml:"local_path"`
+	Remotes      []string          `toml:"remotes"`
+	PullRequests PullRequestConfig `toml:"pull_requests,omitempty"`
+	Hosts        map[string]string `toml:"hosts,omitempty"`     // Provider de hosts propios (ej: "git.acme.io" = "gitlab")
+	Templates    []string          `toml:"templates,omitempty"` // Repos con plantillas para 'skli new' (cada skill es una plantilla)
+	Editors      []Editor          `toml:"editors,omitempty"`   // Destinos propios o que sobrescriben los incluidos
+	Security     SecurityConfig    `toml:"security,omitempty"`
+	Provenance   ProvenanceConfig  `toml:"provenance,omitempty"`
+}
+
+// ProvenanceConfig configura la verificación de las firmas de los repos de skills
+type ProvenanceConfig struct {
+	Strict  bool     `toml:"strict,omitempty"`  // Una firma no verificada impide instalar (sin strict solo se avisa)
+	Signers []Signer `toml:"signers,omitempty"` // Firmantes exigidos por remote
+}
+
+// Signer son las claves que deben firmar lo que se instala de un remote
+type Signer struct {
+	Remote string   `toml:"remote"`           // URL del repo, como se pasa a 'skli add'
+	Method string   `toml:"method,omitempty"` // "commit" (por defecto), "tag" o "file"
+	Keys   []string `toml:"keys"`             // Claves públicas SSH ("ssh-ed25519 AAAA... alice") o fingerprints GPG
+	File   string   `toml:"file,omitempty"`   // Firma separada en el repo con method "file" (por defecto "skli.sig")
+}
+
+// SecurityConfig configura el análisis de los skills antes de instalarlos o sincronizarlos
+type SecurityConfig struct {
+	Policy       string   `toml:"policy,omitempty"`        // "warn" (por defecto), "confirm" o "block"
+	AllowedHosts []string `toml:"allowed_hosts,omitempty"` // Hosts de URLs que no se avisan (también sus subdominios)
+	Ignore       []string `toml:"ignore,omitempty"`        // Reglas que no se comprueban (ej: "hidden-file")
+}
+
+// Editor define un destino de instalación de skills. Con el id de uno incluido solo
+// se sobrescriben los campos indicados.
+type Editor struct {
+	ID         string   `toml:"id"`
+	Name       string   `toml:"name,omitempty"`
+	Path       string   `toml:"path,omitempty"`        // Carpeta de skills relativa a la raíz del proyecto
+	GlobalPath string   `toml:"global_path,omitempty"` // Carpeta de skills del usuario (ej: "~/.agent/skills")
+	Format     string   `toml:"format,omitempty"`      // "skill" (por defecto) o id de conversor (ej: "cursor-mdc")
+	Detect     []string `toml:"detect,omitempty"`      // Ficheros o carpetas que indican que el proyecto usa el destino
+}
+
+// PullRequestConfig configura las PRs/MRs creadas por upload y contribute
+type PullRequestConfig struct {
+	Labels    []string          `toml:"labels,omitempty"`
+	Reviewers []string          `toml:"reviewers,omitempty"`
+	Draft     bool              `toml:"draft,omitempty"`
+	Tokens    map[string]string `toml:"tokens,omitempty"` // Token de API por host (ej: "github.com")
+	Forks     map[string]string `toml:"forks,omitempty"`  // Fork personal por repo original (URL web → remote del fork)
+
+	// Plantillas Go (text/template) de la rama, el mensaje de commit y la PR; vacías = formato por defecto
+	Branch  string `toml:"branch,omitempty"`
+	Commit  string `toml:"commit,omitempty"`
+	Title   string `toml:"title,omitempty"`
+	Body    string `toml:"body,omitempty"`
+	Signoff bool   `toml:"signoff,omitempty"` // Commits con "Signed-off-by"
 }
 
 func GetConfigDir() string {
@@ -43,12 +97,18 @@ func SaveConfig(cfg Config) error {
 		return fmt.Errorf("error creating config directory: %w", err)
 	}
 
+	// La config puede contener tokens de API: solo legible por el usuario
 	path := GetConfigPath()
-	f, err := os.Create(path)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
 	if err != nil {
 		return fmt.Errorf("error creating config file: %w", err)
 	}
 	defer f.Close()
+	if len(cfg.PullRequests.Tokens) > 0 {
+		if err := f.Chmod(0600); err != nil {
+			return fmt.Errorf("error securing config file: %w", err)
+		}
+	}
 
 	if err := toml.NewEncoder(f).Encode(cfg); err != nil {
 		return fmt.Errorf("error encoding config: %w", err)
@@ -56,3 +116,16 @@ func SaveConfig(cfg Config) error {
 
 	return nil
 }
+
+// RememberFork guarda en la config el fork usado para un repo original
+func RememberFork(upstream, fork string) error {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+	if cfg.PullRequests.Forks == nil {
+		cfg.PullRequests.Forks = map[string]string{}
+	}
+	cfg.PullRequests.Forks[upstream] = fork
+	return SaveConfig(cfg)
+}
@@ -78,3 +78,59 @@ func TestLoadConfigInvalidToml(t *testing.T) {
 		t.Fatalf("expected parse error for invalid toml")
 	}
 }
+
+func TestPullRequestConfigRoundtripIsPrivate(t *testing.T) {
+	home := withTempHome(t)
+
+	want := Config{
+		LocalPath: "skills",
+		PullRequests: PullRequestConfig{
+			Labels:    []string{"skills"},
+			Reviewers: []string{"alice"},
+			Draft:     true,
+			Tokens:    map[string]string{"github.com": "secret"},
+		},
+	}
+	if err := SaveConfig(want); err != nil {
+		t.Fatalf("SaveConfig error: %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(home, ".skli", "config.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm&0077 != 0 {
+		t.Fatalf("config with tokens must not be readable by others, got %v", perm)
+	}
+
+	got, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	pr := got.PullRequests
+	if !pr.Draft || len(pr.Labels) != 1 || len(pr.Reviewers) != 1 || pr.Tokens["github.com"] != "secret" {
+		t.Fatalf("pull request settings not preserved: %+v", pr)
+	}
+}
+
+func TestRememberForkKeepsOtherSettings(t *testing.T) {
+	withTempHome(t)
+	if err := SaveConfig(Config{LocalPath: "skills", PullRequests: PullRequestConfig{Draft: true}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RememberFork("https://github.com/acme/skills", "git@github.com:bob/skills.git"); err != nil {
+		t.Fatalf("RememberFork: %v", err)
+	}
+
+	got, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.LocalPath != "skills" || !got.PullRequests.Draft {
+		t.Fatalf("other settings lost: %+v", got)
+	}
+	if got.PullRequests.Forks["https://github.com/acme/skills"] != "git@github.com:bob/skills.git" {
+		t.Fatalf("fork not remembered: %+v", got.PullRequests.Forks)
+	}
+}
@@ -0,0 +1,308 @@
+package convert
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"skli/internal/project"
+	"skli/internal/skillmeta"
+	"skli/internal/validate"
+)
+
+// Skill es el contenido de un skill independiente del formato
+type Skill struct {
+	Name        string
+	Description string
+	Globs       []string // Ficheros a los que se aplica; vacío = lo decide el modelo por la descripción
+	Body        string   // Markdown sin frontmatter
+}
+
+// Converter traduce un skill al formato nativo de un editor (un fichero por skill) y de vuelta
+type Converter interface {
+	ID() string   // Identificador guardado en skli.lock (ej: "cursor-mdc")
+	Name() string // Nombre para mostrar
+	// FileName devuelve el nombre del artefacto generado para el skill
+	FileName(skill string) string
+	// Match indica si path es un artefacto de este formato (para importarlo)
+	Match(path string) bool
+	Encode(s Skill) ([]byte, error)
+	Decode(name string, data []byte) (Skill, error)
+}
+
+var registry = map[string]Converter{}
+
+// Register añade un conversor. Los incluidos en skli se registran al arrancar.
+func Register(c Converter) {
+	if _, ok := registry[c.ID()]; ok {
+		panic("convert: converter registered twice: " + c.ID())
+	}
+	registry[c.ID()] = c
+}
+
+// Get devuelve el conversor con ese identificador
+func Get(id string) (Converter, error) {
+	if c, ok := registry[id]; ok {
+		return c, nil
+	}
+	ids := make([]string, 0, len(registry))
+	for _, c := range All() {
+		ids = append(ids, c.ID())
+	}
+	return nil, fmt.Errorf("unknown converter '%s' (available: %s)", id, strings.Join(ids, ", "))
+}
+
+// All devuelve los conversores registrados ordenados por identificador
+func All() []Converter {
+	out := make([]Converter, 0, len(registry))
+	for _, c := range registry {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID() < out[j].ID() })
+	return out
+}
+
+// Detect busca el conversor cuyo formato corresponde a path
+func Detect(path string) (Converter, bool) {
+	for _, c := range All() {
+		if c.Match(path) {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// Sources devuelve los artefactos de dir que se pueden importar con el conversor id
+// (con cualquiera si id está vacío). Si dir es un fichero se devuelve tal cual.
+func Sources(id, dir string) ([]string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{dir}, nil
+	}
+	var c Converter
+	if id != "" {
+		if c, err = Get(id); err != nil {
+			return nil, err
+		}
+	}
+
+	var sources []string
+	err = filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" || d.Name() == "node_modules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		var match bool
+		if c != nil {
+			match = c.Match(p)
+		} else {
+			_, match = Detect(p)
+		}
+		if match {
+			sources = append(sources, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no rule files to import found in %s", dir)
+	}
+	return sources, nil
+}
+
+// ArtifactPath devuelve la ruta del artefacto del skill folder dentro de dir
+func ArtifactPath(id, dir, folder string) (string, error) {
+	c, err := Get(id)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, c.FileName(folder)), nil
+}
+
+// Read lee un skill en formato SKILL.md. Las globs salen de la clave no estándar "globs".
+func Read(skillDir string) (Skill, error) {
+	meta, err := skillmeta.ParseDir(skillDir)
+	if err != nil {
+		return Skill{}, err
+	}
+	data, err := os.ReadFile(filepath.Join(skillDir, "SKILL.md"))
+	if err != nil {
+		return Skill{}, err
+	}
+	return Skill{
+		Name:        meta.Name,
+		Description: meta.Description,
+		Globs:       globs(meta.Extra["globs"]),
+		Body:        string(skillmeta.Body(data)),
+	}, nil
+}
+
+// Export genera en dest el artefacto del skill de srcDir con el conversor id.
+// Solo se convierte SKILL.md: los ficheros que acompañan al skill no forman parte del artefacto.
+func Export(id, srcDir, dest string) error {
+	c, err := Get(id)
+	if err != nil {
+		return err
+	}
+	skill, err := Read(srcDir)
+	if err != nil {
+		return err
+	}
+	data, err := c.Encode(skill)
+	if err != nil {
+		return fmt.Errorf("error converting '%s' to %s: %w", skill.Name, c.Name(), err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dest, data, 0644)
+}
+
+// Import convierte el artefacto src en un skill <skillsRoot>/<nombre>/SKILL.md y lo valida.
+// skillsRoot es relativo a la raíz del proyecto.
+// id vacío detecta el formato por la ruta. Devuelve la ruta relativa al proyecto y los avisos;
+// si la validación falla el skill se deja en disco y se devuelve un *validate.Error.
+func Import(id, src, skillsRoot string) (string, []validate.Issue, error) {
+	var c Converter
+	if id == "" {
+		var ok bool
+		if c, ok = Detect(src); !ok {
+			return "", nil, fmt.Errorf("could not detect the format of %s, pass the converter explicitly", src)
+		}
+	} else {
+		var err error
+		if c, err = Get(id); err != nil {
+			return "", nil, err
+		}
+	}
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return "", nil, err
+	}
+	skill, err := c.Decode(strings.TrimSuffix(filepath.Base(src), filepath.Ext(src)), data)
+	if err != nil {
+		return "", nil, fmt.Errorf("error reading %s as %s: %w", src, c.Name(), err)
+	}
+	skill.Name = slug(skill.Name)
+	if skill.Description == "" {
+		skill.Description = fmt.Sprintf("Imported from %s", filepath.Base(src))
+	}
+
+	dir := filepath.Join(project.Resolve(skillsRoot), skill.Name)
+	if _, err := os.Stat(dir); err == nil {
+		return "", nil, fmt.Errorf("%s already exists", project.Rel(dir))
+	}
+	if err := write(skill, dir); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, err
+	}
+
+	issues, err := validate.Check(skill.Name, dir)
+	return project.Rel(dir), issues, err
+}
+
+// write crea la carpeta del skill con su SKILL.md
+func write(s Skill, dir string) error {
+	keys := []skillmeta.Key{{Name: "name", Value: s.Name}, {Name: "description", Value: s.Description}}
+	if len(s.Globs) > 0 {
+		keys = append(keys, skillmeta.Key{Name: "globs", Value: s.Globs})
+	}
+	data, err := document(keys, s.Body)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "SKILL.md"), data, 0644)
+}
+
+// document construye un fichero Markdown con frontmatter YAML en el orden de keys
+func document(keys []skillmeta.Key, body string) ([]byte, error) {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range keys {
+		var value yaml.Node
+		if err := value.Encode(key.Value); err != nil {
+			return nil, err
+		}
+		if value.Kind == yaml.SequenceNode {
+			value.Style = yaml.FlowStyle
+		}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key.Name}, &value)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("---\n")
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return nil, err
+	}
+	enc.Close()
+	out.WriteString("---\n")
+	out.WriteString(body)
+	return out.Bytes(), nil
+}
+
+// parse lee el frontmatter y el cuerpo de un artefacto
+func parse(data []byte) (skillmeta.Metadata, string, error) {
+	meta, err := skillmeta.Parse(bytes.NewReader(data))
+	if err != nil {
+		return skillmeta.Metadata{}, "", err
+	}
+	return meta, string(skillmeta.Body(data)), nil
+}
+
+// globs acepta una lista YAML o una cadena separada por comas
+func globs(v any) []string {
+	var items []string
+	switch v := v.(type) {
+	case string:
+		items = strings.Split(v, ",")
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				items = append(items, s)
+			}
+		}
+	}
+	var out []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// slug convierte un nombre de fichero en un nombre de skill válido
+func slug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
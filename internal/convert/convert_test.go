@@ -0,0 +1,112 @@
+package convert
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"skli/internal/project"
+	"skli/internal/skillmeta"
+)
+
+func TestConvertersRoundTrip(t *testing.T) {
+	cases := []Skill{
+		{Name: "go-style", Description: "Go conventions: errors, naming", Globs: []string{"*.go", "cmd/**"}, Body: "# Go\n\nBe idiomatic.\n"},
+		{Name: "reviews", Description: "How to review PRs", Body: "Check the tests.\n"},
+	}
+	for _, c := range All() {
+		for _, want := range cases {
+			data, err := c.Encode(want)
+			if err != nil {
+				t.Fatalf("%s: Encode: %v", c.ID(), err)
+			}
+			path := filepath.Join(".cursor", "rules", c.FileName(want.Name))
+			if c.ID() == "windsurf-rules" {
+				path = filepath.Join(".windsurf", "rules", c.FileName(want.Name))
+			}
+			if !c.Match(path) {
+				t.Fatalf("%s must match its own artefact %s", c.ID(), path)
+			}
+			name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			got, err := c.Decode(name, data)
+			if err != nil {
+				t.Fatalf("%s: Decode: %v", c.ID(), err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("%s: round trip changed the skill\nwant %+v\ngot  %+v\n%s", c.ID(), want, got, data)
+			}
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	cases := map[string]string{
+		".cursor/rules/go.mdc":                    "cursor-mdc",
+		".github/instructions/go.instructions.md": "copilot-instructions",
+		".github/copilot-instructions.md":         "copilot-instructions",
+		"repo/.windsurf/rules/go.md":              "windsurf-rules",
+		"docs/README.md":                          "",
+	}
+	for path, want := range cases {
+		c, ok := Detect(filepath.FromSlash(path))
+		if want == "" {
+			if ok {
+				t.Fatalf("%s must not match any converter, got %s", path, c.ID())
+			}
+			continue
+		}
+		if !ok || c.ID() != want {
+			t.Fatalf("%s: expected %s, got %v", path, want, c)
+		}
+	}
+	if _, err := Get("nope"); err == nil || !strings.Contains(err.Error(), "cursor-mdc") {
+		t.Fatalf("unknown converters must list the available ones, got %v", err)
+	}
+}
+
+func TestExportAndImport(t *testing.T) {
+	root := t.TempDir()
+	project.SetRootForTest(t, root)
+	src := filepath.Join(root, "skills", "go-style")
+	os.MkdirAll(src, 0755)
+	os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("---\nname: go-style\ndescription: Go conventions\nglobs: [\"*.go\"]\n---\n# Go\n"), 0644)
+
+	dest, err := ArtifactPath("cursor-mdc", filepath.Join(root, ".cursor", "rules"), "go-style")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Export("cursor-mdc", src, dest); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	data, _ := os.ReadFile(dest)
+	if string(data) != "---\ndescription: Go conventions\nglobs: '*.go'\nalwaysApply: false\n---\n# Go\n" {
+		t.Fatalf("unexpected artefact:\n%s", data)
+	}
+
+	os.Rename(dest, filepath.Join(filepath.Dir(dest), "Go Style.mdc"))
+	sources, err := Sources("", filepath.Join(root, ".cursor"))
+	if err != nil || len(sources) != 1 {
+		t.Fatalf("expected one source, got %v (%v)", sources, err)
+	}
+	rel, issues, err := Import("", sources[0], "imported")
+	if err != nil || len(issues) > 0 {
+		t.Fatalf("Import: %v %v", issues, err)
+	}
+	if rel != filepath.Join("imported", "go-style") {
+		t.Fatalf("the file name must become a valid skill name, got %s", rel)
+	}
+	meta, err := skillmeta.ParseDir(filepath.Join(root, rel))
+	if err != nil || meta.Name != "go-style" || meta.Description != "Go conventions" {
+		t.Fatalf("unexpected frontmatter %+v (%v)", meta, err)
+	}
+	skill, err := Read(filepath.Join(root, rel))
+	if err != nil || !reflect.DeepEqual(skill.Globs, []string{"*.go"}) || skill.Body != "# Go\n" {
+		t.Fatalf("imported skill must keep globs and body, got %+v (%v)", skill, err)
+	}
+
+	if _, _, err := Import("", sources[0], "imported"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected an error for an existing skill, got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package convert
+
+import (
+	"path/filepath"
+	"strings"
+
+	"skli/internal/skillmeta"
+)
+
+// copilotInstructions genera instrucciones de GitHub Copilot (.github/instructions/<nombre>.instructions.md).
+// Copilot exige applyTo: sin globs se aplica a todos los ficheros ("**").
+type copilotInstructions struct{}
+
+func init() { Register(copilotInstructions{}) }
+
+const (
+	copilotSuffix   = ".instructions.md"
+	copilotRepoFile = "copilot-instructions.md" // Instrucciones de todo el repo (.github/copilot-instructions.md)
+	copilotAllFiles = "**"
+)
+
+func (copilotInstructions) ID() string   { return "copilot-instructions" }
+func (copilotInstructions) Name() string { return "GitHub Copilot instructions" }
+
+func (copilotInstructions) FileName(skill string) string { return skill + copilotSuffix }
+
+func (copilotInstructions) Match(path string) bool {
+	base := strings.ToLower(filepath.Base(path))
+	return strings.HasSuffix(base, copilotSuffix) || base == copilotRepoFile
+}
+
+func (copilotInstructions) Encode(s Skill) ([]byte, error) {
+	applyTo := strings.Join(s.Globs, ",")
+	if applyTo == "" {
+		applyTo = copilotAllFiles
+	}
+	return document([]skillmeta.Key{
+		{Name: "description", Value: s.Description},
+		{Name: "applyTo", Value: applyTo},
+	}, s.Body)
+}
+
+func (copilotInstructions) Decode(name string, data []byte) (Skill, error) {
+	meta, body, err := parse(data)
+	if err != nil {
+		return Skill{}, err
+	}
+	skill := Skill{Name: strings.TrimSuffix(name, ".instructions"), Description: meta.Description, Body: body}
+	if applyTo := globs(meta.Extra["applyTo"]); len(applyTo) != 1 || applyTo[0] != copilotAllFiles {
+		skill.Globs = applyTo
+	}
+	return skill, nil
+}
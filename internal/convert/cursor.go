@@ -0,0 +1,39 @@
+package convert
+
+import (
+	"path/filepath"
+	"strings"
+
+	"skli/internal/skillmeta"
+)
+
+// cursorMDC genera reglas de Cursor (.cursor/rules/<nombre>.mdc). Sin globs la regla se aplica
+// cuando el agente la considera relevante por su descripción.
+type cursorMDC struct{}
+
+func init() { Register(cursorMDC{}) }
+
+func (cursorMDC) ID() string   { return "cursor-mdc" }
+func (cursorMDC) Name() string { return "Cursor rules (.mdc)" }
+
+func (cursorMDC) FileName(skill string) string { return skill + ".mdc" }
+
+func (cursorMDC) Match(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".mdc")
+}
+
+func (cursorMDC) Encode(s Skill) ([]byte, error) {
+	return document([]skillmeta.Key{
+		{Name: "description", Value: s.Description},
+		{Name: "globs", Value: strings.Join(s.Globs, ",")},
+		{Name: "alwaysApply", Value: false},
+	}, s.Body)
+}
+
+func (cursorMDC) Decode(name string, data []byte) (Skill, error) {
+	meta, body, err := parse(data)
+	if err != nil {
+		return Skill{}, err
+	}
+	return Skill{Name: name, Description: meta.Description, Globs: globs(meta.Extra["globs"]), Body: body}, nil
+}
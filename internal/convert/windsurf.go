@@ -0,0 +1,44 @@
+package convert
+
+import (
+	"path/filepath"
+	"strings"
+
+	"skli/internal/skillmeta"
+)
+
+// windsurfRules genera reglas de Windsurf (.windsurf/rules/<nombre>.md). Se activan por globs
+// si el skill las declara y, si no, cuando el modelo decide por la descripción.
+type windsurfRules struct{}
+
+func init() { Register(windsurfRules{}) }
+
+func (windsurfRules) ID() string   { return "windsurf-rules" }
+func (windsurfRules) Name() string { return "Windsurf rules" }
+
+func (windsurfRules) FileName(skill string) string { return skill + ".md" }
+
+func (windsurfRules) Match(path string) bool {
+	dir := filepath.ToSlash(filepath.Dir(path))
+	return strings.EqualFold(filepath.Ext(path), ".md") && strings.HasSuffix(dir, ".windsurf/rules")
+}
+
+func (windsurfRules) Encode(s Skill) ([]byte, error) {
+	keys := []skillmeta.Key{{Name: "trigger", Value: "model_decision"}, {Name: "description", Value: s.Description}}
+	if len(s.Globs) > 0 {
+		keys = []skillmeta.Key{
+			{Name: "trigger", Value: "glob"},
+			{Name: "description", Value: s.Description},
+			{Name: "globs", Value: strings.Join(s.Globs, ",")},
+		}
+	}
+	return document(keys, s.Body)
+}
+
+func (windsurfRules) Decode(name string, data []byte) (Skill, error) {
+	meta, body, err := parse(data)
+	if err != nil {
+		return Skill{}, err
+	}
+	return Skill{Name: name, Description: meta.Description, Globs: globs(meta.Extra["globs"]), Body: body}, nil
+}
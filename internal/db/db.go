@@ -1,11 +1,21 @@
 package db
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
+	"path"
+	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/BurntSushi/toml"
+
+	"skli/internal/integrity"
+	"skli/internal/project"
+	"skli/internal/skillmeta"
 )
 
 // InstalledSkill representa un skill instalado con su origen
@@ -20,98 +30,258 @@ type InstalledSkill struct {
 	TreeHash    string    `toml:"tree_hash"`   // Hash del árbol (carpeta) cuando se instaló
 	InstalledAt time.Time `toml:"installed_at"`
 	UpdatedAt   time.Time `toml:"updated_at"`
+
+	Files integrity.Manifest `toml:"files,omitempty"` // SHA-256 de cada fichero instalado
+
+	// Campos opcionales del frontmatter de SKILL.md (version, tags, editors...)
+	skillmeta.Fields
+
+	// Skill registrado al subirlo: sigue la rama de la PR hasta que se fusiona en la rama por defecto
+	PendingBranch string `toml:"pending_branch,omitempty"`
+	PendingRepo   string `toml:"pending_repo,omitempty"` // Fork donde está la rama (vacío = RemoteRepo)
+	PRURL         string `toml:"pr_url,omitempty"`
+
+	// Valores de las variables del skill con los que se instaló; sync los vuelve a aplicar
+	Values map[string]string `toml:"values,omitempty"`
+
+	// Destino en el que se instaló (id, ej: "claude-code"); vacío = ruta propia
+	Editor string `toml:"editor,omitempty"`
+	// Conversor que generó el artefacto instalado en Path (ej: "cursor-mdc"); vacío = carpeta copiada tal cual
+	Converter string `toml:"converter,omitempty"`
+
+	// Firmante verificado del commit instalado ("nombre clave"); vacío si el remote no exige firmas
+	Signer string `toml:"signer,omitempty"`
+}
+
+// IsPending indica si el skill espera a que se fusione la PR con la que se subió
+func (s InstalledSkill) IsPending() bool {
+	return s.PendingBranch != ""
+}
+
+// RemoteTreePath devuelve la ruta del skill relativa a la raíz del repo remoto
+func (s InstalledSkill) RemoteTreePath() string {
+	if s.RemoteRoot == "" || s.RemoteRoot == "." {
+		return s.RemotePath
+	}
+	return path.Join(s.RemoteRoot, s.RemotePath)
 }
 
 // LockFile representa la estructura del archivo skli.lock
 type LockFile struct {
-	LastUpdated time.Time        `toml:"last_updated"`
-	Skills      []InstalledSkill `toml:"skills"`
+	Version        int              `toml:"version"` // Versión del esquema (0 en ficheros anteriores al versionado)
+	LastUpdated    time.Time        `toml:"last_updated"`
+	PendingUpgrade bool             `toml:"pending_upgrade,omitempty"` // Faltan datos de una versión anterior: 'skli lock upgrade'
+	Skills         []InstalledSkill `toml:"skills"`
 }
 
-const lockFileName = "skli.lock"
+// errNoChange permite abortar un Update sin escribir el fichero
+var errNoChange = errors.New("no change")
 
+// processMu serializa los accesos de escritura dentro del mismo proceso (ej: goroutines de sync)
+var processMu sync.Mutex
+
+// getLockFilePath devuelve la ruta del skli.lock en la raíz del proyecto
 func getLockFilePath() string {
-	return lockFileName
+	return filepath.Join(project.Root(), project.LockFileName)
 }
 
-// LoadLockFile lee el archivo skli.lock
+// getGuardFilePath devuelve el fichero auxiliar sobre el que se toma el bloqueo advisory.
+// No se bloquea skli.lock directamente porque se reemplaza con rename en cada escritura.
+// Vive fuera del árbol del proyecto para que no acabe en el repo: en .git si existe y si no
+// en la caché del usuario, con un nombre derivado de la raíz del proyecto.
+func getGuardFilePath() string {
+	root := project.Root()
+	if info, err := os.Stat(filepath.Join(root, ".git")); err == nil && info.IsDir() {
+		return filepath.Join(root, ".git", project.LockFileName+".lck")
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	sum := sha256.Sum256([]byte(root))
+	return filepath.Join(dir, "skli", "locks", hex.EncodeToString(sum[:8])+".lck")
+}
+
+// LoadLockFile lee el archivo skli.lock migrando en memoria los formatos antiguos
 func LoadLockFile() (*LockFile, error) {
-	var lock LockFile
-	path := getLockFilePath()
+	return loadLockFile(getLockFilePath())
+}
+
+// loadLockFile lee el lock y aplica las migraciones que no requieren red
+func loadLockFile(path string) (*LockFile, error) {
+	lock, err := readLockFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := Migrate(lock, MigrateOptions{}); err != nil {
+		return nil, err
+	}
+	return lock, nil
+}
+
+// readLockFile lee el lock tal cual está en disco, rechazando versiones desconocidas
+func readLockFile(path string) (*LockFile, error) {
+	lock := LockFile{Version: LockVersion}
 
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return &lock, nil
 	}
 
+	lock.Version = 0
 	if _, err := toml.DecodeFile(path, &lock); err != nil {
 		return nil, fmt.Errorf("error reading lock file: %w", err)
 	}
+	if lock.Version > LockVersion {
+		return nil, fmt.Errorf("%w: %s has version %d but this skli supports up to %d, run 'skli update'", ErrUnsupportedLockVersion, path, lock.Version, LockVersion)
+	}
 
 	return &lock, nil
 }
 
-// SaveLockFile guarda el archivo skli.lock
+// SaveLockFile guarda el archivo skli.lock de forma atómica y bajo bloqueo exclusivo
 func SaveLockFile(lock *LockFile) error {
-	path := getLockFilePath()
-	lock.LastUpdated = time.Now()
+	return withLock(func() error {
+		lock.LastUpdated = time.Now()
+		return writeLockFile(getLockFilePath(), lock)
+	})
+}
+
+// Update carga el lock file bajo bloqueo exclusivo, aplica fn y lo guarda una sola vez.
+// Si fn devuelve error no se escribe nada. fn no debe llamar a otras funciones de escritura de db.
+func Update(fn func(lock *LockFile) error) error {
+	return withLock(func() error {
+		path := getLockFilePath()
+		lock, err := loadLockFile(path)
+		if err != nil {
+			return err
+		}
+		if err := fn(lock); err != nil {
+			return err
+		}
+		lock.LastUpdated = time.Now()
+		return writeLockFile(path, lock)
+	})
+}
 
-	f, err := os.Create(path)
+// withLock ejecuta fn con el mutex del proceso y el bloqueo advisory del fichero guard
+func withLock(fn func() error) error {
+	processMu.Lock()
+	defer processMu.Unlock()
+
+	guardPath := getGuardFilePath()
+	if err := os.MkdirAll(filepath.Dir(guardPath), 0755); err != nil {
+		return fmt.Errorf("error opening lock guard: %w", err)
+	}
+	guard, err := os.OpenFile(guardPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening lock guard: %w", err)
+	}
+	defer guard.Close()
+
+	if err := lockFile(guard); err != nil {
+		return fmt.Errorf("error locking skli.lock: %w", err)
+	}
+	defer unlockFile(guard)
+
+	return fn()
+}
+
+// writeLockFile escribe en un temporal del mismo directorio y lo renombra sobre el destino,
+// de forma que los lectores nunca ven un fichero a medio escribir.
+// Siempre se escribe en el formato actual: lo que falte de versiones anteriores lo indica PendingUpgrade.
+// LastUpdated se escribe tal cual: lo fijan los que modifican el lock (Update, SaveLockFile...).
+func writeLockFile(path string, lock *LockFile) error {
+	lock.Version = LockVersion
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
 	if err != nil {
 		return fmt.Errorf("error creating lock file: %w", err)
 	}
-	defer f.Close()
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
 
-	if err := toml.NewEncoder(f).Encode(lock); err != nil {
+	if err := toml.NewEncoder(tmp).Encode(lock); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing lock file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing lock file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
 		return fmt.Errorf("error writing lock file: %w", err)
 	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return fmt.Errorf("error writing lock file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("error replacing lock file: %w", err)
+	}
 
 	return nil
 }
 
-// SaveInstalledSkill añade o actualiza un skill en el lock file
-func SaveInstalledSkill(skill InstalledSkill) error {
-	lock, err := LoadLockFile()
-	if err != nil {
-		return err
+// Key identifica una entrada del lock.
+// La combinación repo + path remoto es única; los skills sin path remoto se identifican por su ruta local.
+func (s InstalledSkill) Key() string {
+	if s.RemotePath == "" {
+		return s.RemoteRepo + "\x00\x00" + s.Path
 	}
+	return s.RemoteRepo + "\x00" + s.RemotePath
+}
 
-	found := false
-	for i, s := range lock.Skills {
-		// Asumimos que la combinación de repo + path es única
-		if s.RemoteRepo == skill.RemoteRepo && s.RemotePath == skill.RemotePath {
+// sameEntry indica si dos entradas del lock representan el mismo skill
+func sameEntry(a, b InstalledSkill) bool {
+	return a.Key() == b.Key()
+}
+
+// Upsert añade o actualiza un skill manteniendo su fecha de instalación original
+func (l *LockFile) Upsert(skill InstalledSkill) {
+	now := time.Now()
+	for i, s := range l.Skills {
+		if sameEntry(s, skill) {
 			skill.InstalledAt = s.InstalledAt // Mantener fecha original
-			skill.UpdatedAt = time.Now()
-			lock.Skills[i] = skill
-			found = true
-			break
+			skill.UpdatedAt = now
+			l.Skills[i] = skill
+			return
 		}
 	}
 
-	if !found {
-		skill.InstalledAt = time.Now()
-		skill.UpdatedAt = time.Now()
-		lock.Skills = append(lock.Skills, skill)
+	skill.InstalledAt = now
+	skill.UpdatedAt = now
+	l.Skills = append(l.Skills, skill)
+}
+
+// RemovePath elimina el skill con la ruta local indicada. Devuelve false si no existía.
+func (l *LockFile) RemovePath(path string) bool {
+	newSkills := make([]InstalledSkill, 0, len(l.Skills))
+	found := false
+	for _, s := range l.Skills {
+		if s.Path == path {
+			found = true
+			continue
+		}
+		newSkills = append(newSkills, s)
 	}
+	l.Skills = newSkills
+	return found
+}
 
-	return SaveLockFile(lock)
+// SaveInstalledSkill añade o actualiza un skill en el lock file
+func SaveInstalledSkill(skill InstalledSkill) error {
+	return Update(func(lock *LockFile) error {
+		lock.Upsert(skill)
+		return nil
+	})
 }
 
 // RemoveInstalledSkill elimina un skill del lock file usando su ruta local
 func RemoveInstalledSkill(localPath string) error {
-	lock, err := LoadLockFile()
-	if err != nil {
-		return err
-	}
-
-	var newSkills []InstalledSkill
-	for _, s := range lock.Skills {
-		if s.Path != localPath {
-			newSkills = append(newSkills, s)
-		}
-	}
-
-	lock.Skills = newSkills
-	return SaveLockFile(lock)
+	return Update(func(lock *LockFile) error {
+		lock.RemovePath(localPath)
+		return nil
+	})
 }
 
 // GetSkillsByRepo agrupa los skills instalados por su repositorio de origen
@@ -133,25 +303,14 @@ func GetSkillsByRepo() (map[string][]InstalledSkill, error) {
 
 // DeleteInstalledSkill elimina un skill del lock file por su nombre (o ruta relativa)
 func DeleteInstalledSkill(path string) error {
-	lock, err := LoadLockFile()
-	if err != nil {
-		return err
-	}
-
-	newSkills := make([]InstalledSkill, 0)
-	found := false
-	for _, s := range lock.Skills {
-		if s.Path == path {
-			found = true
-			continue
+	err := Update(func(lock *LockFile) error {
+		if !lock.RemovePath(path) {
+			return errNoChange // No estaba, nada que hacer
 		}
-		newSkills = append(newSkills, s)
+		return nil
+	})
+	if errors.Is(err, errNoChange) {
+		return nil
 	}
-
-	if !found {
-		return nil // No estaba, nada que hacer
-	}
-
-	lock.Skills = newSkills
-	return SaveLockFile(lock)
+	return err
 }
@@ -1,10 +1,17 @@
 package db
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"skli/internal/integrity"
 )
 
 func withTempWorkdir(t *testing.T) string {
@@ -126,3 +133,389 @@ func TestDeleteInstalledSkillAndGroupByRepo(t *testing.T) {
 		}
 	}
 }
+
+func TestConcurrentSaveInstalledSkillKeepsAllEntries(t *testing.T) {
+	dir := withTempWorkdir(t)
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- SaveInstalledSkill(InstalledSkill{
+				Name:       fmt.Sprintf("skill-%d", i),
+				Path:       fmt.Sprintf("skills/skill-%d", i),
+				RemoteRepo: "repo",
+				RemotePath: fmt.Sprintf("skill-%d", i),
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("SaveInstalledSkill: %v", err)
+		}
+	}
+
+	lock, err := LoadLockFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lock.Skills) != n {
+		t.Fatalf("expected %d skills, got %d", n, len(lock.Skills))
+	}
+
+	leftovers, _ := filepath.Glob(filepath.Join(dir, ".skli.lock-*.tmp"))
+	if len(leftovers) != 0 {
+		t.Fatalf("temporary files left behind: %v", leftovers)
+	}
+}
+
+func TestUpdateAbortsWithoutWriting(t *testing.T) {
+	dir := withTempWorkdir(t)
+
+	if err := SaveInstalledSkill(InstalledSkill{Name: "a", Path: "skills/a"}); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.ReadFile(filepath.Join(dir, "skli.lock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	boom := errors.New("boom")
+	err = Update(func(lock *LockFile) error {
+		lock.Upsert(InstalledSkill{Name: "b", Path: "skills/b"})
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected fn error, got %v", err)
+	}
+
+	after, err := os.ReadFile(filepath.Join(dir, "skli.lock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(before) != string(after) {
+		t.Fatalf("lock file must not change when fn fails")
+	}
+}
+
+func TestUpdateBatchesSeveralChanges(t *testing.T) {
+	withTempWorkdir(t)
+
+	err := Update(func(lock *LockFile) error {
+		lock.Upsert(InstalledSkill{Name: "a", Path: "skills/a", RemoteRepo: "r", RemotePath: "a"})
+		lock.Upsert(InstalledSkill{Name: "b", Path: "skills/b", RemoteRepo: "r", RemotePath: "b"})
+		lock.Upsert(InstalledSkill{Name: "a2", Path: "skills/a", RemoteRepo: "r", RemotePath: "a"})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := LoadLockFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lock.Skills) != 2 || lock.Skills[0].Name != "a2" {
+		t.Fatalf("unexpected skills after batch: %+v", lock.Skills)
+	}
+}
+
+func TestLoadLockFileRejectsNewerVersion(t *testing.T) {
+	dir := withTempWorkdir(t)
+
+	content := fmt.Sprintf("version = %d\n", LockVersion+1)
+	if err := os.WriteFile(filepath.Join(dir, "skli.lock"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadLockFile(); !errors.Is(err, ErrUnsupportedLockVersion) {
+		t.Fatalf("expected ErrUnsupportedLockVersion, got %v", err)
+	}
+	if err := SaveInstalledSkill(InstalledSkill{Name: "a", Path: "skills/a"}); !errors.Is(err, ErrUnsupportedLockVersion) {
+		t.Fatalf("writes must refuse newer formats too, got %v", err)
+	}
+}
+
+func TestLegacyLockFileStaysPendingUntilUpgraded(t *testing.T) {
+	dir := withTempWorkdir(t)
+
+	// El contenido instalado no se lee al cargar: ni manifiesto ni metadatos
+	if err := os.MkdirAll(filepath.Join(dir, "skills", "a"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "skills", "a", "SKILL.md"), []byte("---\nname: a\nversion: 1.0.0\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	legacy := "[[skills]]\n  name = \"a\"\n  path = \"skills/a\"\n  remote_repo = \"repo\"\n  remote_path = \"a\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "skli.lock"), []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := LoadLockFile()
+	if err != nil {
+		t.Fatalf("LoadLockFile legacy: %v", err)
+	}
+	if !lock.PendingUpgrade || len(lock.Skills) != 1 || lock.Skills[0].Files != nil || lock.Skills[0].Version != "" {
+		t.Fatalf("loading must only mark the missing data as pending: %+v", lock)
+	}
+
+	if err := SaveInstalledSkill(InstalledSkill{Name: "b", Path: "skills/b", RemoteRepo: "repo", RemotePath: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := readLockFile(filepath.Join(dir, "skli.lock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Se escribe la versión de los datos que lleva el fichero, para que un skli anterior lo rechace
+	if raw.Version != LockVersion || !raw.PendingUpgrade || len(raw.Skills) != 2 || raw.Skills[0].Files != nil {
+		t.Fatalf("expected the current version, still pending, with 2 skills on disk, got %+v", raw)
+	}
+}
+
+func TestUpgradeLockFileBackfillsTreeHashes(t *testing.T) {
+	dir := withTempWorkdir(t)
+
+	legacy := `[[skills]]
+  name = "a"
+  path = "skills/a"
+  remote_repo = "repo"
+  remote_root = "skills"
+  remote_path = "a"
+  commit_hash = "c1"
+
+[[skills]]
+  name = "b"
+  path = "skills/b"
+  remote_repo = "repo"
+  remote_root = "skills"
+  remote_path = "b"
+  commit_hash = "c1"
+
+[[skills]]
+  name = "gone"
+  path = "skills/gone"
+  remote_repo = "repo"
+  remote_root = "."
+  remote_path = "gone"
+  commit_hash = "c2"
+`
+	if err := os.WriteFile(filepath.Join(dir, "skli.lock"), []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	resolver := func(repoURL, commit string, paths []string) (map[string]string, error) {
+		calls++
+		out := map[string]string{}
+		for _, p := range paths {
+			if p != "gone" {
+				out[p] = "tree-" + commit + "-" + p
+			}
+		}
+		return out, nil
+	}
+
+	report, err := UpgradeLockFile(MigrateOptions{ResolveTreeHashes: resolver})
+	if err != nil {
+		t.Fatalf("UpgradeLockFile: %v", err)
+	}
+	if report.From != 1 || report.To != LockVersion || !report.Pending || report.Backfilled != 2 || len(report.Failed) != 1 {
+		t.Fatalf("a failed backfill must keep the lock pending, got %+v", report)
+	}
+	if _, ok := report.Failed["skills/gone"]; !ok {
+		t.Fatalf("expected skills/gone to fail, got %+v", report.Failed)
+	}
+	if calls != 2 {
+		t.Fatalf("expected one remote query per repo+commit, got %d", calls)
+	}
+
+	lock, err := LoadLockFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock.Skills[0].TreeHash != "tree-c1-skills/a" || lock.Skills[1].TreeHash != "tree-c1-skills/b" {
+		t.Fatalf("tree hashes not backfilled: %+v", lock.Skills)
+	}
+
+	// Una vez que el remoto responde se completa el resto y se quita la marca
+	report, err = UpgradeLockFile(MigrateOptions{
+		ResolveTreeHashes: func(_, _ string, paths []string) (map[string]string, error) {
+			return map[string]string{paths[0]: "tree-gone"}, nil
+		},
+		ResolveManifest: func(InstalledSkill) (integrity.Manifest, error) { return integrity.Manifest{}, nil },
+		ResolveEditor:   func(string) string { return "" },
+	})
+	if err != nil || report.Pending || report.Backfilled != 1 || len(report.Failed) != 0 {
+		t.Fatalf("expected the retry to finish the upgrade, got %+v %v", report, err)
+	}
+	if lock, err = LoadLockFile(); err != nil || lock.PendingUpgrade {
+		t.Fatalf("the finished upgrade must clear the mark, got %+v %v", lock, err)
+	}
+}
+
+func TestSkillMetadataIsMigratedAndStored(t *testing.T) {
+	dir := withTempWorkdir(t)
+
+	skillDir := filepath.Join(dir, "skills", "a")
+	if err := os.MkdirAll(skillDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	frontmatter := "---\nname: a\ndescription: test\nversion: 2.0.0\ntags: [go, cli]\neditors: [cursor]\n---\n"
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(frontmatter), 0644); err != nil {
+		t.Fatal(err)
+	}
+	legacy := "[[skills]]\n  name = \"a\"\n  path = \"skills/a\"\n  remote_repo = \"repo\"\n  remote_path = \"a\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "skli.lock"), []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := LoadLockFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lock.PendingUpgrade || lock.Skills[0].Version != "" {
+		t.Fatalf("metadata must only be read by 'skli lock upgrade', got %+v", lock)
+	}
+	if _, err := UpgradeLockFile(MigrateOptions{}); err != nil {
+		t.Fatalf("UpgradeLockFile: %v", err)
+	}
+	if lock, err = LoadLockFile(); err != nil {
+		t.Fatal(err)
+	}
+	got := lock.Skills[0]
+	if got.Version != "2.0.0" || len(got.Tags) != 2 || got.Tags[1] != "cli" || len(got.Editors) != 1 {
+		t.Fatalf("metadata not migrated from SKILL.md: %+v", got.Fields)
+	}
+
+	// Los campos se guardan en la propia entrada de skli.lock
+	if err := SaveInstalledSkill(got); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, "skli.lock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(raw), `version = "2.0.0"`) || !strings.Contains(string(raw), `tags = ["go", "cli"]`) {
+		t.Fatalf("metadata not written inline:\n%s", raw)
+	}
+	reread, err := readLockFile(filepath.Join(dir, "skli.lock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reread.Skills[0].Version != "2.0.0" || !reread.Skills[0].HasTag("go") {
+		t.Fatalf("metadata lost on round trip: %+v", reread.Skills[0].Fields)
+	}
+}
+
+func TestEditorsAreMigratedFromPaths(t *testing.T) {
+	dir := withTempWorkdir(t)
+
+	old := `[[skills]]
+  name = "a"
+  path = ".claude/skills/a"
+
+[[skills]]
+  name = "b"
+  path = ".cursor/rules/b.mdc"
+  converter = "cursor-mdc"
+
+[[skills]]
+  name = "c"
+  path = "vendor/skills/c"
+`
+	if err := os.WriteFile(filepath.Join(dir, "skli.lock"), []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	builtIn := map[string]string{".claude/skills": "claude-code", ".cursor/rules": "cursor-rules"}
+	var dirs []string
+	if _, err := UpgradeLockFile(MigrateOptions{ResolveEditor: func(dir string) string {
+		dirs = append(dirs, dir)
+		return builtIn[dir]
+	}}); err != nil {
+		t.Fatalf("UpgradeLockFile: %v", err)
+	}
+	if !reflect.DeepEqual(dirs, []string{".claude/skills", ".cursor/rules", "vendor/skills"}) {
+		t.Fatalf("the resolver must get each install folder, got %q", dirs)
+	}
+	lock, err := LoadLockFile()
+	if err != nil {
+		t.Fatalf("LoadLockFile: %v", err)
+	}
+	got := []string{lock.Skills[0].Editor, lock.Skills[1].Editor, lock.Skills[2].Editor}
+	if !reflect.DeepEqual(got, []string{"claude-code", "cursor-rules", ""}) {
+		t.Fatalf("unexpected editors %q", got)
+	}
+}
+
+func TestLockGuardStaysOutOfTheProject(t *testing.T) {
+	dir := withTempWorkdir(t)
+	cache := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cache)
+	t.Setenv("HOME", cache)
+
+	if err := SaveInstalledSkill(InstalledSkill{Name: "a", Path: "skills/a", RemoteRepo: "r", RemotePath: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || entries[0].Name() != "skli.lock" {
+		t.Fatalf("only skli.lock must be written to the project, got %v", entries)
+	}
+
+	os.Mkdir(filepath.Join(dir, ".git"), 0755)
+	if err := SaveInstalledSkill(InstalledSkill{Name: "b", Path: "skills/b", RemoteRepo: "r", RemotePath: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "skli.lock.lck")); err != nil {
+		t.Fatalf("git checkouts must keep the guard in .git: %v", err)
+	}
+}
+
+func TestManifestsComeFromTheLockedCommit(t *testing.T) {
+	dir := withTempWorkdir(t)
+
+	// Contenido alterado en disco: no debe acabar en el manifiesto
+	if err := os.MkdirAll(filepath.Join(dir, "skills", "a"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "skills", "a", "SKILL.md"), []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	legacy := "[[skills]]\n  name = \"a\"\n  path = \"skills/a\"\n  remote_repo = \"repo\"\n  remote_path = \"a\"\n  commit_hash = \"c1\"\n  tree_hash = \"t1\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "skli.lock"), []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := LoadLockFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock.Skills[0].Files != nil || !lock.PendingUpgrade {
+		t.Fatalf("loading must leave the manifest empty for verify to report it, got %+v", lock)
+	}
+
+	fromCommit := integrity.Manifest{"SKILL.md": "sha-at-c1"}
+	report, err := UpgradeLockFile(MigrateOptions{
+		ResolveManifest: func(s InstalledSkill) (integrity.Manifest, error) {
+			if s.CommitHash != "c1" {
+				t.Fatalf("unexpected commit %q", s.CommitHash)
+			}
+			return fromCommit, nil
+		},
+		ResolveEditor: func(string) string { return "" },
+	})
+	if err != nil || report.Pending {
+		t.Fatalf("UpgradeLockFile: %+v %v", report, err)
+	}
+	if lock, err = LoadLockFile(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lock.Skills[0].Files, fromCommit) {
+		t.Fatalf("manifest must come from the locked commit, got %v", lock.Skills[0].Files)
+	}
+}
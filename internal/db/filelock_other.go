@@ -0,0 +1,10 @@
+//go:build !unix && !windows
+
+package db
+
+import "os"
+
+// En plataformas sin bloqueo de ficheros solo se serializa dentro del proceso.
+func lockFile(_ *os.File) error { return nil }
+
+func unlockFile(_ *os.File) error { return nil }
@@ -0,0 +1,102 @@
+package db
+
+// ReadLockFileAt lee un lock en cualquier ruta sin migrarlo (ej: las versiones que pasa git a un merge driver)
+func ReadLockFileAt(path string) (*LockFile, error) {
+	return readLockFile(path)
+}
+
+// WriteLockFileAt escribe un lock de forma atómica en cualquier ruta conservando su LastUpdated
+func WriteLockFileAt(path string, lock *LockFile) error {
+	return writeLockFile(path, lock)
+}
+
+// MergeLockFiles combina dos versiones de un lock a partir de su ancestro común.
+// Las entradas se unen por Key; si ambos lados cambian la misma entrada gana la de UpdatedAt más reciente.
+// Una entrada eliminada en un lado se elimina si el otro lado no la ha modificado.
+// El resultado conserva el orden de ours y añade al final las entradas nuevas de theirs.
+func MergeLockFiles(base, ours, theirs *LockFile) *LockFile {
+	baseByKey := indexByKey(base)
+	oursByKey := indexByKey(ours)
+	theirsByKey := indexByKey(theirs)
+
+	merged := &LockFile{
+		Version:        maxVersion(base, ours, theirs),
+		LastUpdated:    ours.LastUpdated,
+		PendingUpgrade: pendingUpgrade(ours) || pendingUpgrade(theirs),
+	}
+	if theirs.LastUpdated.After(merged.LastUpdated) {
+		merged.LastUpdated = theirs.LastUpdated
+	}
+
+	for _, o := range ours.Skills {
+		key := o.Key()
+		b, inBase := baseByKey[key]
+		t, inTheirs := theirsByKey[key]
+
+		switch {
+		case !inTheirs:
+			// Theirs la eliminó: solo se conserva si ours la ha cambiado
+			if inBase && unchanged(b, o) {
+				continue
+			}
+			merged.Skills = append(merged.Skills, o)
+		case unchanged(o, t), inBase && unchanged(b, t):
+			merged.Skills = append(merged.Skills, o)
+		case inBase && unchanged(b, o):
+			merged.Skills = append(merged.Skills, t)
+		case t.UpdatedAt.After(o.UpdatedAt):
+			merged.Skills = append(merged.Skills, t)
+		default:
+			merged.Skills = append(merged.Skills, o)
+		}
+	}
+
+	for _, t := range theirs.Skills {
+		key := t.Key()
+		if _, inOurs := oursByKey[key]; inOurs {
+			continue
+		}
+		// Ours la eliminó: solo se conserva si theirs la ha cambiado
+		if b, inBase := baseByKey[key]; inBase && unchanged(b, t) {
+			continue
+		}
+		merged.Skills = append(merged.Skills, t)
+	}
+
+	return merged
+}
+
+func indexByKey(lock *LockFile) map[string]InstalledSkill {
+	out := make(map[string]InstalledSkill, len(lock.Skills))
+	for _, s := range lock.Skills {
+		out[s.Key()] = s
+	}
+	return out
+}
+
+// unchanged compara las partes de una entrada que cambian al reinstalar o sincronizar.
+// Toda escritura vía Upsert actualiza UpdatedAt, por lo que basta como marca de modificación.
+func unchanged(a, b InstalledSkill) bool {
+	return a.UpdatedAt.Equal(b.UpdatedAt) &&
+		a.Path == b.Path &&
+		a.CommitHash == b.CommitHash &&
+		a.TreeHash == b.TreeHash
+}
+
+// maxVersion devuelve la versión más nueva: el resultado lleva campos de todas las entradas,
+// así que debe declarar el formato del lado más reciente para que un skli anterior lo rechace.
+func maxVersion(locks ...*LockFile) int {
+	version := 1
+	for _, l := range locks {
+		if l.Version > version {
+			version = l.Version
+		}
+	}
+	return version
+}
+
+// pendingUpgrade indica si a las entradas de un lado les faltan datos de una versión anterior.
+// El merge no los rellena: quedan marcados para 'skli lock upgrade'.
+func pendingUpgrade(lock *LockFile) bool {
+	return lock.PendingUpgrade || (lock.Version < LockVersion && len(lock.Skills) > 0)
+}
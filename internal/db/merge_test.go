@@ -0,0 +1,123 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func names(lock *LockFile) []string {
+	out := make([]string, 0, len(lock.Skills))
+	for _, s := range lock.Skills {
+		out = append(out, s.Name)
+	}
+	return out
+}
+
+func TestMergeLockFilesUnionsNewEntries(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	shared := InstalledSkill{Name: "shared", Path: "skills/shared", RemoteRepo: "r", RemotePath: "shared", UpdatedAt: t0}
+
+	base := &LockFile{Version: LockVersion, Skills: []InstalledSkill{shared}}
+	ours := &LockFile{Version: LockVersion, Skills: []InstalledSkill{shared,
+		{Name: "ours", Path: "skills/ours", RemoteRepo: "r", RemotePath: "ours", UpdatedAt: t0.Add(time.Hour)}}}
+	theirs := &LockFile{Version: LockVersion, Skills: []InstalledSkill{shared,
+		{Name: "theirs", Path: "skills/theirs", RemoteRepo: "r2", RemotePath: "theirs", UpdatedAt: t0.Add(time.Hour)}}}
+
+	merged := MergeLockFiles(base, ours, theirs)
+	got := names(merged)
+	if len(got) != 3 || got[0] != "shared" || got[1] != "ours" || got[2] != "theirs" {
+		t.Fatalf("unexpected merge: %v", got)
+	}
+}
+
+func TestMergeLockFilesConflictTakesNewest(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	orig := InstalledSkill{Name: "a", Path: "skills/a", RemoteRepo: "r", RemotePath: "a", CommitHash: "c0", UpdatedAt: t0}
+	oursA := orig
+	oursA.CommitHash, oursA.UpdatedAt = "c1", t0.Add(time.Hour)
+	theirsA := orig
+	theirsA.CommitHash, theirsA.UpdatedAt = "c2", t0.Add(2*time.Hour)
+
+	merged := MergeLockFiles(
+		&LockFile{Skills: []InstalledSkill{orig}},
+		&LockFile{Skills: []InstalledSkill{oursA}},
+		&LockFile{Skills: []InstalledSkill{theirsA}},
+	)
+	if len(merged.Skills) != 1 || merged.Skills[0].CommitHash != "c2" {
+		t.Fatalf("expected newest entry, got %+v", merged.Skills)
+	}
+
+	// Si solo un lado cambia, gana ese lado aunque el otro sea "más nuevo" por reloj
+	merged = MergeLockFiles(
+		&LockFile{Skills: []InstalledSkill{orig}},
+		&LockFile{Skills: []InstalledSkill{oursA}},
+		&LockFile{Skills: []InstalledSkill{orig}},
+	)
+	if merged.Skills[0].CommitHash != "c1" {
+		t.Fatalf("expected one-sided change to win, got %+v", merged.Skills)
+	}
+}
+
+func TestMergeLockFilesHonoursDeletions(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := InstalledSkill{Name: "a", Path: "skills/a", RemoteRepo: "r", RemotePath: "a", UpdatedAt: t0}
+	b := InstalledSkill{Name: "b", Path: "skills/b", RemoteRepo: "r", RemotePath: "b", UpdatedAt: t0}
+	bChanged := b
+	bChanged.UpdatedAt = t0.Add(time.Hour)
+
+	merged := MergeLockFiles(
+		&LockFile{Skills: []InstalledSkill{a, b}},
+		&LockFile{Skills: []InstalledSkill{b}},           // ours eliminó a
+		&LockFile{Skills: []InstalledSkill{a, bChanged}}, // theirs modificó b
+	)
+	got := names(merged)
+	if len(got) != 1 || got[0] != "b" || !merged.Skills[0].UpdatedAt.Equal(bChanged.UpdatedAt) {
+		t.Fatalf("unexpected merge: %+v", merged.Skills)
+	}
+
+	// Eliminado en un lado pero modificado en el otro: se conserva la modificación
+	merged = MergeLockFiles(
+		&LockFile{Skills: []InstalledSkill{a, b}},
+		&LockFile{Skills: []InstalledSkill{a}},
+		&LockFile{Skills: []InstalledSkill{a, bChanged}},
+	)
+	if got := names(merged); len(got) != 2 {
+		t.Fatalf("modified entry must survive deletion, got %v", got)
+	}
+}
+
+func TestMergeLockFilesRoundtripOnDisk(t *testing.T) {
+	dir := withTempWorkdir(t)
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	write := func(name string, skills ...InstalledSkill) string {
+		p := filepath.Join(dir, name)
+		if err := WriteLockFileAt(p, &LockFile{Skills: skills}); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	basePath := write("base", InstalledSkill{Name: "a", Path: "skills/a", RemoteRepo: "r", RemotePath: "a", UpdatedAt: t0})
+	oursPath := write("ours",
+		InstalledSkill{Name: "a", Path: "skills/a", RemoteRepo: "r", RemotePath: "a", UpdatedAt: t0},
+		InstalledSkill{Name: "b", Path: "skills/b", RemoteRepo: "r", RemotePath: "b", UpdatedAt: t0})
+	theirsPath := write("theirs",
+		InstalledSkill{Name: "a", Path: "skills/a", RemoteRepo: "r", RemotePath: "a", UpdatedAt: t0},
+		InstalledSkill{Name: "c", Path: "skills/c", RemoteRepo: "r", RemotePath: "c", UpdatedAt: t0})
+
+	base, _ := ReadLockFileAt(basePath)
+	ours, _ := ReadLockFileAt(oursPath)
+	theirs, _ := ReadLockFileAt(theirsPath)
+	if err := WriteLockFileAt(oursPath, MergeLockFiles(base, ours, theirs)); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ReadLockFileAt(oursPath)
+	if err != nil {
+		t.Fatalf("merged file must decode: %v", err)
+	}
+	if got := names(result); len(got) != 3 {
+		t.Fatalf("unexpected merged skills: %v", got)
+	}
+}
@@ -0,0 +1,234 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"skli/internal/integrity"
+	"skli/internal/project"
+	"skli/internal/skillmeta"
+)
+
+// LockVersion es la versión del esquema de skli.lock que escribe este binario.
+// Solo cambia una vez por release; los campos opcionales nuevos se añaden a la versión en curso.
+//
+//	1: formato original sin campo version
+//	2: tree_hash, manifiesto files, subidas pendientes, metadatos del frontmatter, converter,
+//	   editor, variables, values y signer
+const LockVersion = 2
+
+// ErrUnsupportedLockVersion indica un skli.lock escrito por una versión más nueva de skli
+var ErrUnsupportedLockVersion = errors.New("unsupported skli.lock version")
+
+// ManifestResolver calcula el manifiesto de un skill a partir del commit registrado en el lock
+type ManifestResolver func(skill InstalledSkill) (integrity.Manifest, error)
+
+// TreeHashResolver devuelve el hash del árbol de cada path (relativo a la raíz del repo)
+// en el commit indicado. Un commit vacío significa la rama de la URL.
+type TreeHashResolver func(repoURL, commit string, paths []string) (map[string]string, error)
+
+// EditorResolver devuelve el id del destino cuya carpeta es dir (relativa a la raíz); vacío si no es de ninguno
+type EditorResolver func(dir string) string
+
+// MigrateOptions configura el relleno de los datos que faltan en locks antiguos.
+// Sin Upgrade solo se sella la versión y se marca el lock como pendiente.
+type MigrateOptions struct {
+	Upgrade           bool // Rellenar los datos que leen el disco o el remoto ('skli lock upgrade')
+	ResolveTreeHashes TreeHashResolver
+	ResolveManifest   ManifestResolver
+	ResolveEditor     EditorResolver
+}
+
+// MigrationReport resume lo que ha hecho una migración
+type MigrationReport struct {
+	From       int
+	To         int
+	Backfilled int
+	Pending    bool             // Quedan datos sin rellenar: hay que volver a ejecutar 'skli lock upgrade'
+	Failed     map[string]error // Errores por ruta local del skill
+}
+
+// backfills rellenan los datos que los locks de versiones anteriores no tienen.
+// Cada uno devuelve false si algún skill se quedó sin completar.
+var backfills = []func(lock *LockFile, opts MigrateOptions, report *MigrationReport) bool{
+	backfillTreeHashes,
+	recordManifests,
+	recordMetadata,
+	recordEditors,
+}
+
+// Migrate lleva el lock al formato actual. Los locks anteriores se marcan con PendingUpgrade, ya que
+// los datos que les faltan se leen del disco o del remoto y solo se rellenan con Upgrade;
+// la marca se quita cuando todos los rellenos se completan.
+func Migrate(lock *LockFile, opts MigrateOptions) (MigrationReport, error) {
+	version := lock.Version
+	if version == 0 {
+		version = 1
+	}
+	if version > LockVersion {
+		return MigrationReport{}, fmt.Errorf("%w: version %d, supported up to %d", ErrUnsupportedLockVersion, version, LockVersion)
+	}
+
+	report := MigrationReport{From: version, To: LockVersion, Failed: map[string]error{}}
+	if version < LockVersion && len(lock.Skills) > 0 {
+		lock.PendingUpgrade = true
+	}
+	lock.Version = LockVersion
+
+	if opts.Upgrade && lock.PendingUpgrade {
+		complete := true
+		for _, backfill := range backfills {
+			if !backfill(lock, opts, &report) {
+				complete = false
+			}
+		}
+		lock.PendingUpgrade = !complete
+	}
+	report.Pending = lock.PendingUpgrade
+
+	return report, nil
+}
+
+// UpgradeLockFile reescribe skli.lock en el formato actual rellenando también los datos que
+// leen el disco o consultan el remoto. Si alguno falla el lock sigue marcado como pendiente.
+func UpgradeLockFile(opts MigrateOptions) (MigrationReport, error) {
+	opts.Upgrade = true
+	var report MigrationReport
+	err := withLock(func() error {
+		path := getLockFilePath()
+		lock, err := readLockFile(path)
+		if err != nil {
+			return err
+		}
+
+		report, err = Migrate(lock, opts)
+		if err != nil {
+			return err
+		}
+
+		lock.LastUpdated = time.Now()
+		return writeLockFile(path, lock)
+	})
+	return report, err
+}
+
+// backfillTreeHashes rellena los tree_hash vacíos agrupando las consultas por repo y commit
+func backfillTreeHashes(lock *LockFile, opts MigrateOptions, report *MigrationReport) bool {
+
+	type source struct{ repo, commit string }
+	pending := make(map[source][]int)
+	var order []source
+	for i, s := range lock.Skills {
+		if s.TreeHash != "" || s.RemoteRepo == "" {
+			continue
+		}
+		if _, failed := report.Failed[s.Path]; failed {
+			continue
+		}
+		key := source{repo: s.RemoteRepo, commit: s.CommitHash}
+		if _, ok := pending[key]; !ok {
+			order = append(order, key)
+		}
+		pending[key] = append(pending[key], i)
+	}
+	if len(order) == 0 {
+		return true
+	}
+	if opts.ResolveTreeHashes == nil {
+		return false
+	}
+
+	complete := true
+
+	for _, key := range order {
+		idxs := pending[key]
+		paths := make([]string, 0, len(idxs))
+		for _, i := range idxs {
+			paths = append(paths, lock.Skills[i].RemoteTreePath())
+		}
+
+		hashes, err := opts.ResolveTreeHashes(key.repo, key.commit, paths)
+		for _, i := range idxs {
+			s := &lock.Skills[i]
+			if err != nil {
+				report.Failed[s.Path] = err
+				complete = false
+				continue
+			}
+			hash, ok := hashes[s.RemoteTreePath()]
+			if !ok || hash == "" {
+				report.Failed[s.Path] = fmt.Errorf("path %s not found in %s", s.RemoteTreePath(), key.repo)
+				complete = false
+				continue
+			}
+			s.TreeHash = hash
+			report.Backfilled++
+		}
+	}
+	return complete
+}
+
+// recordManifests calcula el manifiesto de los skills que no lo tienen a partir del commit del lock.
+// Nunca se usa lo que hay en disco: verify acabaría comparando el contenido instalado consigo mismo.
+func recordManifests(lock *LockFile, opts MigrateOptions, report *MigrationReport) bool {
+	complete := true
+	for i := range lock.Skills {
+		s := &lock.Skills[i]
+		if s.Files != nil {
+			continue
+		}
+		if opts.ResolveManifest == nil {
+			return false
+		}
+		manifest, err := opts.ResolveManifest(*s)
+		if err != nil {
+			report.Failed[s.Path] = err
+			complete = false
+			continue
+		}
+		s.Files = manifest
+	}
+	return complete
+}
+
+// recordMetadata copia al lock los campos opcionales del SKILL.md instalado.
+// Los skills convertidos son de la versión actual y ya guardan sus campos al instalarse.
+func recordMetadata(lock *LockFile, _ MigrateOptions, report *MigrationReport) bool {
+	complete := true
+	for i := range lock.Skills {
+		s := &lock.Skills[i]
+		if s.Converter != "" {
+			continue
+		}
+		meta, err := skillmeta.ParseDir(project.Resolve(s.Path))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				report.Failed[s.Path] = err
+				complete = false
+			}
+			continue
+		}
+		s.Fields = meta.Fields
+	}
+	return complete
+}
+
+// recordEditors deduce el destino de cada skill a partir de la carpeta en la que está instalado.
+// Los skills instalados en rutas propias se quedan sin destino.
+func recordEditors(lock *LockFile, opts MigrateOptions, _ *MigrationReport) bool {
+	for i := range lock.Skills {
+		s := &lock.Skills[i]
+		if s.Editor != "" {
+			continue
+		}
+		if opts.ResolveEditor == nil {
+			return false
+		}
+		s.Editor = opts.ResolveEditor(path.Dir(filepath.ToSlash(s.Path)))
+	}
+	return true
+}
@@ -0,0 +1,241 @@
+package editors
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"skli/internal/config"
+	"skli/internal/convert"
+	"skli/internal/skillmeta"
+)
+
+const (
+	// FormatSkill es el formato de los destinos que reciben la carpeta del skill tal cual
+	FormatSkill = "skill"
+	// Auto es el valor de --editor que instala en todos los destinos detectados
+	Auto = "auto"
+)
+
+// Target es un destino donde se instalan skills: un editor o un agente
+type Target interface {
+	ID() string   // Identificador usado en --editor, config.toml y skli.lock (ej: "claude-code")
+	Name() string // Nombre para mostrar
+	// ProjectPath es la carpeta de skills relativa a la raíz del proyecto
+	ProjectPath() string
+	// GlobalPath es la carpeta de skills del usuario ("~/..."); vacía si el destino no la tiene
+	GlobalPath() string
+	// Format es FormatSkill o el id del conversor que genera el formato nativo del destino
+	Format() string
+	// Detect indica si el proyecto de root usa este destino
+	Detect(root string) bool
+}
+
+// target es un destino definido por datos, incluido en skli o en config.toml
+type target struct {
+	id, name, path, global, format string
+	markers                        []string // Ficheros o carpetas (relativos a la raíz) que delatan el destino
+}
+
+func (t target) ID() string          { return t.id }
+func (t target) Name() string        { return t.name }
+func (t target) ProjectPath() string { return t.path }
+func (t target) GlobalPath() string  { return t.global }
+func (t target) Format() string      { return t.format }
+
+func (t target) Detect(root string) bool {
+	for _, marker := range t.markers {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(marker))); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// builtIn son los destinos incluidos en skli, en el orden en que se muestran
+var builtIn = []target{
+	{id: "windsurf", name: "Windsurf", path: ".windsurf/skills", global: "~/.codeium/windsurf/skills", format: FormatSkill, markers: []string{".windsurf"}},
+	{id: "antigravity", name: "Antigravity", path: ".antigravity/skills", format: FormatSkill, markers: []string{".antigravity"}},
+	{id: "cursor", name: "Cursor", path: ".cursor/skills", global: "~/.cursor/skills", format: FormatSkill, markers: []string{".cursor"}},
+	{id: "vscode", name: "VSCode", path: ".vscode/skills", format: FormatSkill, markers: []string{".vscode"}},
+	{id: "opencode", name: "OpenCode", path: ".opencode/skills", global: "~/.config/opencode/skills", format: FormatSkill, markers: []string{".opencode", "opencode.json"}},
+	{id: "claude-code", name: "Claude Code", path: ".claude/skills", global: "~/.claude/skills", format: FormatSkill, markers: []string{".claude", "CLAUDE.md"}},
+	{id: "codex", name: "Codex", path: ".codex/skills", global: "~/.codex/skills", format: FormatSkill, markers: []string{".codex", "AGENTS.md"}},
+	{id: "gemini-cli", name: "Gemini CLI", path: ".gemini/skills", global: "~/.gemini/skills", format: FormatSkill, markers: []string{".gemini", "GEMINI.md"}},
+	{id: "cursor-rules", name: "Cursor (rules)", path: ".cursor/rules", format: "cursor-mdc", markers: []string{".cursor/rules"}},
+	{id: "copilot", name: "Copilot (instructions)", path: ".github/instructions", format: "copilot-instructions", markers: []string{".github/instructions", ".github/copilot-instructions.md"}},
+	{id: "windsurf-rules", name: "Windsurf (rules)", path: ".windsurf/rules", format: "windsurf-rules", markers: []string{".windsurf/rules"}},
+}
+
+// targets son los destinos activos: los incluidos más los de config.toml
+var targets = toTargets(builtIn)
+
+func toTargets(list []target) []Target {
+	out := make([]Target, len(list))
+	for i, t := range list {
+		out[i] = t
+	}
+	return out
+}
+
+// Configure aplica los destinos de config.toml: los que usan el id de uno incluido lo
+// sobrescriben campo a campo y el resto se añaden al final. Configure(nil) deja solo los incluidos.
+func Configure(defs []config.Editor) error {
+	list := append([]target(nil), builtIn...)
+	for _, def := range defs {
+		id := strings.ToLower(strings.TrimSpace(def.ID))
+		if id == "" {
+			return fmt.Errorf("editor without id")
+		}
+		i := indexOf(list, id)
+		if i < 0 {
+			list = append(list, target{id: id, name: id, format: FormatSkill})
+			i = len(list) - 1
+		}
+		t := &list[i]
+		if def.Name != "" {
+			t.name = def.Name
+		}
+		if def.Path != "" {
+			t.path = filepath.ToSlash(filepath.Clean(def.Path))
+		}
+		if def.GlobalPath != "" {
+			t.global = def.GlobalPath
+		}
+		if def.Format != "" {
+			t.format = def.Format
+		}
+		if def.Detect != nil {
+			t.markers = def.Detect
+		}
+
+		if t.path == "" {
+			return fmt.Errorf("editor '%s' has no path", id)
+		}
+		if t.format != FormatSkill {
+			if _, err := convert.Get(t.format); err != nil {
+				return fmt.Errorf("editor '%s': %w", id, err)
+			}
+		}
+	}
+	targets = toTargets(list)
+	return nil
+}
+
+func indexOf(list []target, id string) int {
+	for i, t := range list {
+		if t.id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// All devuelve los destinos activos
+func All() []Target {
+	return append([]Target(nil), targets...)
+}
+
+// Get devuelve el destino con ese id
+func Get(id string) (Target, error) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	for _, t := range targets {
+		if t.ID() == id {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown editor '%s' (available: %s, %s)", id, strings.Join(ids(), ", "), Auto)
+}
+
+func ids() []string {
+	out := make([]string, len(targets))
+	for i, t := range targets {
+		out[i] = t.ID()
+	}
+	return out
+}
+
+// Detected devuelve los destinos que usa el proyecto de root, en el orden de All.
+// Si se detecta un destino con formato propio (ej: cursor-rules) se descarta el de carpetas
+// del mismo editor (cursor): su marcador también coincide e instalar en ambos duplicaría los skills.
+func Detected(root string) []Target {
+	var found []Target
+	converted := make(map[string]bool)
+	for _, t := range targets {
+		if t.Detect(root) {
+			found = append(found, t)
+			if t.Format() != FormatSkill {
+				converted[family(t)] = true
+			}
+		}
+	}
+
+	var out []Target
+	for _, t := range found {
+		if t.Format() == FormatSkill && family(t) != "" && converted[family(t)] {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// family es la carpeta de primer nivel del destino, compartida por los destinos de un mismo editor
+// (".cursor" para cursor y cursor-rules)
+func family(t Target) string {
+	first, _, _ := strings.Cut(t.ProjectPath(), "/")
+	return first
+}
+
+// Resolve convierte el valor de --editor en destinos: un id o Auto para los detectados en root
+func Resolve(value, root string) ([]Target, error) {
+	if strings.EqualFold(strings.TrimSpace(value), Auto) {
+		detected := Detected(root)
+		if len(detected) == 0 {
+			return nil, fmt.Errorf("no editors detected in %s, pass --editor with one of: %s", root, strings.Join(ids(), ", "))
+		}
+		return detected, nil
+	}
+	t, err := Get(value)
+	if err != nil {
+		return nil, err
+	}
+	return []Target{t}, nil
+}
+
+// ForPath devuelve el destino cuya carpeta de proyecto es path
+func ForPath(path string) (Target, bool) {
+	return forPath(targets, path)
+}
+
+// BuiltInForPath es ForPath solo con los destinos incluidos en skli, sin los de config.toml.
+// Lo usan las migraciones del lock, que deben dar el mismo resultado en cualquier máquina.
+func BuiltInForPath(path string) (Target, bool) {
+	return forPath(toTargets(builtIn), path)
+}
+
+func forPath(list []Target, path string) (Target, bool) {
+	path = strings.TrimSuffix(filepath.ToSlash(filepath.Clean(path)), "/")
+	for _, t := range list {
+		if t.ProjectPath() == path {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// Converter devuelve el conversor del destino; vacío si recibe la carpeta del skill
+func Converter(t Target) string {
+	if t == nil || t.Format() == FormatSkill {
+		return ""
+	}
+	return t.Format()
+}
+
+// Compatible indica si el skill admite el destino. La lista editors del frontmatter puede
+// nombrar el id ("cursor-rules"), su primera parte ("cursor") o el nombre del destino.
+func Compatible(f skillmeta.Fields, t Target) bool {
+	family, _, _ := strings.Cut(t.ID(), "-")
+	return f.SupportsEditor(t.ID()) || f.SupportsEditor(family) || f.SupportsEditor(t.Name())
+}
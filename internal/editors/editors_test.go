@@ -0,0 +1,161 @@
+package editors
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"skli/internal/config"
+	"skli/internal/skillmeta"
+)
+
+func configure(t *testing.T, defs []config.Editor) error {
+	t.Helper()
+	t.Cleanup(func() { Configure(nil) })
+	return Configure(defs)
+}
+
+func TestBuiltInTargets(t *testing.T) {
+	for _, id := range []string{"claude-code", "codex", "gemini-cli", "cursor", "cursor-rules"} {
+		if _, err := Get(id); err != nil {
+			t.Fatalf("missing built-in %s: %v", id, err)
+		}
+	}
+	target, ok := ForPath("./.cursor/rules/")
+	if !ok || target.ID() != "cursor-rules" || Converter(target) != "cursor-mdc" {
+		t.Fatalf("unexpected target for .cursor/rules: %v", target)
+	}
+	if target, _ := Get("Claude-Code"); Converter(target) != "" {
+		t.Fatal("skill targets must not use a converter")
+	}
+	if _, ok := ForPath("vendor/skills"); ok {
+		t.Fatal("custom paths must not match a target")
+	}
+}
+
+func TestConfigureOverridesAndAddsTargets(t *testing.T) {
+	err := configure(t, []config.Editor{
+		{ID: "claude-code", Path: ".agents/claude"},
+		{ID: "acme-agent", Name: "Acme Agent", Path: ".acme/skills", GlobalPath: "~/.acme/skills", Detect: []string{".acme"}},
+	})
+	if err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+
+	claude, _ := Get("claude-code")
+	if claude.ProjectPath() != ".agents/claude" || claude.Name() != "Claude Code" || claude.GlobalPath() != "~/.claude/skills" {
+		t.Fatalf("overrides must only replace the given fields: %+v", claude)
+	}
+	all := All()
+	last := all[len(all)-1]
+	if last.ID() != "acme-agent" || last.Format() != FormatSkill || last.GlobalPath() != "~/.acme/skills" {
+		t.Fatalf("new targets must be appended with the skill format: %+v", last)
+	}
+
+	// Las migraciones del lock solo usan los destinos incluidos
+	if target, ok := ForPath(".acme/skills"); !ok || target.ID() != "acme-agent" {
+		t.Fatalf("configured targets must match their path, got %v", target)
+	}
+	if _, ok := BuiltInForPath(".acme/skills"); ok {
+		t.Fatal("BuiltInForPath must ignore configured targets")
+	}
+	if target, ok := BuiltInForPath(".claude/skills"); !ok || target.ID() != "claude-code" {
+		t.Fatalf("BuiltInForPath must ignore overridden paths, got %v", target)
+	}
+
+	root := t.TempDir()
+	if last.Detect(root) {
+		t.Fatal("target must not be detected without its marker")
+	}
+	os.Mkdir(filepath.Join(root, ".acme"), 0755)
+	if !last.Detect(root) {
+		t.Fatal("target must be detected by its marker")
+	}
+
+	Configure(nil)
+	if _, err := Get("acme-agent"); err == nil {
+		t.Fatal("Configure(nil) must restore the built-in targets")
+	}
+}
+
+func TestConfigureRejectsInvalidTargets(t *testing.T) {
+	cases := map[string][]config.Editor{
+		"without id":      {{Path: ".x/skills"}},
+		"has no path":     {{ID: "x"}},
+		"unknown convert": {{ID: "x", Path: ".x", Format: "nope"}},
+	}
+	for want, defs := range cases {
+		if err := configure(t, defs); err == nil || !strings.Contains(err.Error(), strings.Fields(want)[0]) {
+			t.Fatalf("%s: expected an error, got %v", want, err)
+		}
+	}
+}
+
+func TestCompatible(t *testing.T) {
+	rules, _ := Get("cursor-rules")
+	cases := map[string]bool{
+		"":             true,
+		"cursor":       true,
+		"cursor-rules": true,
+		"windsurf":     false,
+	}
+	for declared, want := range cases {
+		var fields skillmeta.Fields
+		if declared != "" {
+			fields.Editors = []string{declared}
+		}
+		if got := Compatible(fields, rules); got != want {
+			t.Fatalf("editors [%s]: expected %v, got %v", declared, want, got)
+		}
+	}
+}
+
+func TestDetectedKeepsOneTargetPerEditor(t *testing.T) {
+	ids := func(root string) string {
+		var out []string
+		for _, target := range Detected(root) {
+			out = append(out, target.ID())
+		}
+		return strings.Join(out, ",")
+	}
+
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, ".windsurf", "skills"), 0755)
+	if got := ids(root); got != "windsurf" {
+		t.Fatalf("expected windsurf, got %q", got)
+	}
+
+	// Con las reglas de Windsurf, .windsurf también coincide: solo se instala como regla
+	os.MkdirAll(filepath.Join(root, ".windsurf", "rules"), 0755)
+	os.MkdirAll(filepath.Join(root, ".cursor", "rules"), 0755)
+	if got := ids(root); got != "cursor-rules,windsurf-rules" {
+		t.Fatalf("expected only the rule formats, got %q", got)
+	}
+}
+
+func TestResolveAutoUsesDetectedTargets(t *testing.T) {
+	root := t.TempDir()
+	if _, err := Resolve(Auto, root); err == nil || !strings.Contains(err.Error(), "no editors detected") {
+		t.Fatalf("expected an error without detected editors, got %v", err)
+	}
+
+	os.MkdirAll(filepath.Join(root, ".cursor", "rules"), 0755)
+	os.WriteFile(filepath.Join(root, "CLAUDE.md"), []byte("# Notes\n"), 0644)
+	targets, err := Resolve("AUTO", root)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	var got []string
+	for _, target := range targets {
+		got = append(got, target.ID())
+	}
+	if strings.Join(got, ",") != "claude-code,cursor-rules" {
+		t.Fatalf("unexpected detected targets %v", got)
+	}
+
+	targets, err = Resolve("codex", root)
+	if err != nil || len(targets) != 1 || targets[0].ID() != "codex" {
+		t.Fatalf("undetected targets must stay reachable by id, got %v (%v)", targets, err)
+	}
+}
@@ -0,0 +1,85 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// AzureClient crea PRs con la API REST de Azure DevOps (Services o Server)
+type AzureClient struct {
+	BaseURL string
+	Token   string // Personal access token
+	HTTP    *http.Client
+}
+
+// azureAPIHost devuelve el host de la API: las URLs *.visualstudio.com antiguas usan el mismo servicio
+func azureAPIHost(host string) string {
+	if host == "" || strings.HasSuffix(host, ".visualstudio.com") || host == "ssh.dev.azure.com" {
+		return "dev.azure.com"
+	}
+	return host
+}
+
+func (c *AzureClient) auth(req *http.Request) {
+	if c.Token != "" {
+		req.SetBasicAuth("", c.Token)
+	}
+}
+
+// CreatePullRequest crea la PR en repoPath con forma "organización/proyecto/_git/repo".
+// Azure identifica a los reviewers por id, así que deben indicarse como ids de identidad.
+func (c *AzureClient) CreatePullRequest(ctx context.Context, repoPath string, pr PullRequest) (string, error) {
+	org, proj, repo, err := splitAzureRepoPath(repoPath)
+	if err != nil {
+		return "", err
+	}
+	if pr.HeadRepo != "" {
+		return "", fmt.Errorf("pull requests from forks are not supported for Azure DevOps")
+	}
+
+	payload := map[string]any{
+		"sourceRefName": "refs/heads/" + pr.Head,
+		"targetRefName": "refs/heads/" + pr.Base,
+		"title":         pr.Title,
+		"description":   pr.Body,
+		"isDraft":       pr.Draft,
+	}
+	if len(pr.Labels) > 0 {
+		labels := make([]map[string]string, 0, len(pr.Labels))
+		for _, l := range pr.Labels {
+			labels = append(labels, map[string]string{"name": l})
+		}
+		payload["labels"] = labels
+	}
+	if len(pr.Reviewers) > 0 {
+		reviewers := make([]map[string]string, 0, len(pr.Reviewers))
+		for _, r := range pr.Reviewers {
+			reviewers = append(reviewers, map[string]string{"id": r})
+		}
+		payload["reviewers"] = reviewers
+	}
+
+	var created struct {
+		PullRequestID int `json:"pullRequestId"`
+	}
+	// La API y la web comparten host y prefijo organización/proyecto
+	base := fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(c.BaseURL, "/"), url.PathEscape(org), url.PathEscape(proj))
+	endpoint := fmt.Sprintf("%s/_apis/git/repositories/%s/pullrequests?api-version=7.0", base, url.PathEscape(repo))
+	if err := doJSON(ctx, c.HTTP, http.MethodPost, endpoint, c.auth, payload, &created); err != nil {
+		return "", fmt.Errorf("error creating Azure DevOps pull request: %w", err)
+	}
+
+	return fmt.Sprintf("%s/_git/%s/pullrequest/%d", base, url.PathEscape(repo), created.PullRequestID), nil
+}
+
+// splitAzureRepoPath separa "org/proyecto/_git/repo" en sus partes
+func splitAzureRepoPath(repoPath string) (org, proj, repo string, err error) {
+	parts := strings.Split(strings.Trim(repoPath, "/"), "/")
+	if len(parts) == 4 && parts[2] == "_git" {
+		return parts[0], parts[1], parts[3], nil
+	}
+	return "", "", "", fmt.Errorf("invalid Azure DevOps repository path '%s' (expected org/project/_git/repo)", repoPath)
+}
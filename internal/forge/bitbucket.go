@@ -0,0 +1,95 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// BitbucketClient crea PRs con la API REST de Bitbucket Cloud
+type BitbucketClient struct {
+	BaseURL string
+	Token   string // Access token, o "usuario:app-password" para autenticación básica
+	HTTP    *http.Client
+}
+
+func (c *BitbucketClient) auth(req *http.Request) {
+	if c.Token == "" {
+		return
+	}
+	if user, password, ok := strings.Cut(c.Token, ":"); ok {
+		req.SetBasicAuth(user, password)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+}
+
+// CreatePullRequest crea la PR. Bitbucket no tiene labels, así que se ignoran;
+// los reviewers se indican por UUID ("{...}") o account id.
+func (c *BitbucketClient) CreatePullRequest(ctx context.Context, repoPath string, pr PullRequest) (string, error) {
+	reviewers := make([]map[string]string, 0, len(pr.Reviewers))
+	for _, r := range pr.Reviewers {
+		if strings.HasPrefix(r, "{") {
+			reviewers = append(reviewers, map[string]string{"uuid": r})
+		} else {
+			reviewers = append(reviewers, map[string]string{"account_id": r})
+		}
+	}
+
+	source := map[string]any{"branch": map[string]string{"name": pr.Head}}
+	if pr.HeadRepo != "" {
+		source["repository"] = map[string]string{"full_name": pr.HeadRepo}
+	}
+	payload := map[string]any{
+		"title":       pr.Title,
+		"description": pr.Body,
+		"source":      source,
+		"destination": map[string]any{"branch": map[string]string{"name": pr.Base}},
+		"draft":       pr.Draft,
+	}
+	if len(reviewers) > 0 {
+		payload["reviewers"] = reviewers
+	}
+
+	var created struct {
+		Links struct {
+			HTML struct {
+				Href string `json:"href"`
+			} `json:"html"`
+		} `json:"links"`
+	}
+	endpoint := fmt.Sprintf("%s/repositories/%s/pullrequests", strings.TrimSuffix(c.BaseURL, "/"), strings.Trim(repoPath, "/"))
+	if err := doJSON(ctx, c.HTTP, http.MethodPost, endpoint, c.auth, payload, &created); err != nil {
+		return "", fmt.Errorf("error creating Bitbucket pull request: %w", err)
+	}
+	return created.Links.HTML.Href, nil
+}
+
+// Fork crea el fork en el workspace del usuario autenticado
+func (c *BitbucketClient) Fork(ctx context.Context, repoPath string) (ForkInfo, error) {
+	var fork struct {
+		FullName string `json:"full_name"`
+		Links    struct {
+			Clone []struct {
+				Name string `json:"name"`
+				Href string `json:"href"`
+			} `json:"clone"`
+		} `json:"links"`
+	}
+	endpoint := fmt.Sprintf("%s/repositories/%s/forks", strings.TrimSuffix(c.BaseURL, "/"), strings.Trim(repoPath, "/"))
+	if err := doJSON(ctx, c.HTTP, http.MethodPost, endpoint, c.auth, map[string]any{}, &fork); err != nil {
+		return ForkInfo{}, fmt.Errorf("error forking %s on Bitbucket: %w", repoPath, err)
+	}
+
+	info := ForkInfo{RepoPath: fork.FullName}
+	for _, link := range fork.Links.Clone {
+		switch link.Name {
+		case "https":
+			info.CloneURL = link.Href
+		case "ssh":
+			info.SSHURL = link.Href
+		}
+	}
+	return info, nil
+}
@@ -0,0 +1,241 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Provider identifica el servicio de hosting del repositorio
+type Provider int
+
+const (
+	Unknown Provider = iota
+	GitHub
+	GitLab
+	Bitbucket
+	Gitea // También Forgejo, que mantiene la misma API
+	Azure
+)
+
+// ParseProvider convierte el nombre usado en la config (ej: "gitlab", "forgejo") en Provider
+func ParseProvider(name string) (Provider, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "github":
+		return GitHub, nil
+	case "gitlab":
+		return GitLab, nil
+	case "bitbucket":
+		return Bitbucket, nil
+	case "gitea", "forgejo":
+		return Gitea, nil
+	case "azure", "azure-devops":
+		return Azure, nil
+	default:
+		return Unknown, fmt.Errorf("unknown git provider '%s' (expected github, gitlab, bitbucket, gitea, forgejo or azure)", name)
+	}
+}
+
+// String devuelve el nombre del provider
+func (p Provider) String() string {
+	switch p {
+	case GitHub:
+		return "github"
+	case GitLab:
+		return "gitlab"
+	case Bitbucket:
+		return "bitbucket"
+	case Gitea:
+		return "gitea"
+	case Azure:
+		return "azure"
+	default:
+		return "unknown"
+	}
+}
+
+// PullRequest contiene los datos de la PR/MR a crear
+type PullRequest struct {
+	Title     string
+	Body      string
+	Head      string // Rama con los cambios
+	HeadRepo  string // Ruta del fork que contiene Head (vacío si es el mismo repo)
+	Base      string // Rama destino
+	Labels    []string
+	Reviewers []string
+	Draft     bool
+}
+
+// Client crea PRs/MRs usando la API REST del provider
+type Client interface {
+	// CreatePullRequest crea la PR en repoPath (ej: "owner/repo" o "group/subgroup/repo") y devuelve su URL web.
+	// Si la PR se crea pero falla un paso posterior (labels, reviewers) devuelve la URL junto al error.
+	CreatePullRequest(ctx context.Context, repoPath string, pr PullRequest) (string, error)
+}
+
+// ForkInfo describe un fork del repositorio
+type ForkInfo struct {
+	RepoPath string // Ruta del fork (ej: "me/skills")
+	CloneURL string // URL HTTPS para push
+	SSHURL   string
+}
+
+// Forker lo implementan los clientes que pueden crear forks.
+// Si el fork ya existe devuelve el existente.
+type Forker interface {
+	Fork(ctx context.Context, repoPath string) (ForkInfo, error)
+}
+
+// headOwner devuelve el propietario del fork para las APIs que usan "owner:rama"
+func headOwner(headRepo string) string {
+	owner, _, _ := strings.Cut(strings.Trim(headRepo, "/"), "/")
+	return owner
+}
+
+// DefaultTimeout limita cada petición a la API
+const DefaultTimeout = 30 * time.Second
+
+// NewClient devuelve el cliente del provider para host, o nil si el provider no tiene API soportada
+func NewClient(provider Provider, host, token string) Client {
+	httpClient := &http.Client{Timeout: DefaultTimeout}
+	switch provider {
+	case GitHub:
+		return &GitHubClient{BaseURL: gitHubAPIBase(host), Token: token, HTTP: httpClient}
+	case GitLab:
+		return &GitLabClient{BaseURL: "https://" + host + "/api/v4", Token: token, HTTP: httpClient}
+	case Bitbucket:
+		return &BitbucketClient{BaseURL: "https://api.bitbucket.org/2.0", Token: token, HTTP: httpClient}
+	case Gitea:
+		return &GiteaClient{BaseURL: "https://" + host + "/api/v1", Token: token, HTTP: httpClient}
+	case Azure:
+		return &AzureClient{BaseURL: "https://" + azureAPIHost(host), Token: token, HTTP: httpClient}
+	default:
+		return nil
+	}
+}
+
+func gitHubAPIBase(host string) string {
+	if host == "" || host == "github.com" {
+		return "https://api.github.com"
+	}
+	// GitHub Enterprise Server
+	return "https://" + host + "/api/v3"
+}
+
+// envTokens son las variables de entorno consultadas por provider, en orden de preferencia
+var envTokens = map[Provider][]string{
+	GitHub:    {"SKLI_GITHUB_TOKEN", "GITHUB_TOKEN", "GH_TOKEN"},
+	GitLab:    {"SKLI_GITLAB_TOKEN", "GITLAB_TOKEN", "GITLAB_PRIVATE_TOKEN"},
+	Bitbucket: {"SKLI_BITBUCKET_TOKEN", "BITBUCKET_TOKEN"},
+	Gitea:     {"SKLI_GITEA_TOKEN", "GITEA_TOKEN", "FORGEJO_TOKEN"},
+	Azure:     {"SKLI_AZURE_TOKEN", "AZURE_DEVOPS_EXT_PAT", "SYSTEM_ACCESSTOKEN"},
+}
+
+// Token busca el token para host: primero en la config (por host) y, si env lo permite, en el entorno.
+// Las variables de entorno no van ligadas a un host, así que solo deben usarse con hosts de confianza.
+func Token(provider Provider, host string, configured map[string]string, env bool) string {
+	if token := strings.TrimSpace(configured[host]); token != "" {
+		return token
+	}
+	if !env {
+		return ""
+	}
+	for _, name := range envTokens[provider] {
+		if token := strings.TrimSpace(os.Getenv(name)); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// APIError es una respuesta de error de la API
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("API request failed with status %d", e.Status)
+	}
+	return fmt.Sprintf("API request failed with status %d: %s", e.Status, e.Message)
+}
+
+// doJSON envía in como JSON (si no es nil) y decodifica la respuesta en out (si no es nil)
+func doJSON(ctx context.Context, client *http.Client, method, url string, auth func(*http.Request), in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	auth(req)
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &APIError{Status: resp.StatusCode, Message: errorMessage(data)}
+	}
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("invalid API response: %w", err)
+		}
+	}
+	return nil
+}
+
+// errorMessage extrae el mensaje de error de las respuestas de los distintos providers
+func errorMessage(data []byte) string {
+	var payload struct {
+		Message any `json:"message"`
+		Error   any `json:"error"`
+	}
+	if err := json.Unmarshal(data, &payload); err == nil {
+		for _, v := range []any{payload.Message, payload.Error} {
+			switch m := v.(type) {
+			case string:
+				if m != "" {
+					return m
+				}
+			case map[string]any:
+				if s, ok := m["message"].(string); ok && s != "" {
+					return s
+				}
+			case nil:
+			default:
+				if b, err := json.Marshal(m); err == nil {
+					return string(b)
+				}
+			}
+		}
+	}
+	return strings.TrimSpace(string(data))
+}
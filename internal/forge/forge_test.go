@@ -0,0 +1,281 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// recordedRequest guarda lo recibido por el servidor falso
+type recordedRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   map[string]any
+}
+
+// fakeServer responde con responses[método+" "+ruta] y registra las peticiones.
+// Una respuesta puede empezar por un código de estado (ej: "409 {...}"); por defecto es 201.
+func fakeServer(t *testing.T, responses map[string]string) (*httptest.Server, *[]recordedRequest) {
+	t.Helper()
+	var requests []recordedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := recordedRequest{Method: r.Method, Path: r.URL.EscapedPath(), Header: r.Header.Clone()}
+		if r.URL.RawQuery != "" {
+			rec.Path += "?" + r.URL.RawQuery
+		}
+		if r.Body != nil {
+			_ = json.NewDecoder(r.Body).Decode(&rec.Body)
+		}
+		requests = append(requests, rec)
+
+		body, ok := responses[r.Method+" "+rec.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+			return
+		}
+		status := http.StatusCreated
+		if code, rest, ok := strings.Cut(body, " "); ok && len(code) == 3 {
+			if n, err := strconv.Atoi(code); err == nil {
+				status, body = n, rest
+			}
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestGitHubCreatePullRequest(t *testing.T) {
+	srv, requests := fakeServer(t, map[string]string{
+		"POST /repos/acme/skills/pulls":                       `{"number":7,"html_url":"https://github.com/acme/skills/pull/7"}`,
+		"POST /repos/acme/skills/issues/7/labels":             `[]`,
+		"POST /repos/acme/skills/pulls/7/requested_reviewers": `{}`,
+	})
+	client := &GitHubClient{BaseURL: srv.URL, Token: "secret", HTTP: srv.Client()}
+
+	url, err := client.CreatePullRequest(context.Background(), "acme/skills", PullRequest{
+		Title: "Update skill: demo", Body: "body", Head: "feat/x", Base: "main",
+		Labels: []string{"skills"}, Reviewers: []string{"alice", "acme/reviewers"}, Draft: true,
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+	if url != "https://github.com/acme/skills/pull/7" {
+		t.Fatalf("unexpected url %q", url)
+	}
+
+	reqs := *requests
+	if len(reqs) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(reqs))
+	}
+	if got := reqs[0].Header.Get("Authorization"); got != "Bearer secret" {
+		t.Fatalf("unexpected auth header %q", got)
+	}
+	if reqs[0].Body["draft"] != true || reqs[0].Body["head"] != "feat/x" || reqs[0].Body["base"] != "main" {
+		t.Fatalf("unexpected create body: %v", reqs[0].Body)
+	}
+	reviewers := reqs[2].Body
+	if users, _ := reviewers["reviewers"].([]any); len(users) != 1 || users[0] != "alice" {
+		t.Fatalf("unexpected reviewers: %v", reviewers)
+	}
+	if teams, _ := reviewers["team_reviewers"].([]any); len(teams) != 1 || teams[0] != "reviewers" {
+		t.Fatalf("unexpected team reviewers: %v", reviewers)
+	}
+}
+
+func TestGitHubCreatePullRequestReportsAPIError(t *testing.T) {
+	srv, _ := fakeServer(t, nil)
+	client := &GitHubClient{BaseURL: srv.URL, Token: "secret", HTTP: srv.Client()}
+
+	_, err := client.CreatePullRequest(context.Background(), "acme/skills", PullRequest{Title: "t", Head: "h", Base: "main"})
+	if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "Not Found") {
+		t.Fatalf("expected API error with status and message, got %v", err)
+	}
+}
+
+func TestGitLabCreateMergeRequest(t *testing.T) {
+	srv, requests := fakeServer(t, map[string]string{
+		"GET /users?username=bob":                            `[{"id":42}]`,
+		"POST /projects/group%2Fsub%2Fskills/merge_requests": `{"web_url":"https://gitlab.example.com/group/sub/skills/-/merge_requests/3"}`,
+	})
+	client := &GitLabClient{BaseURL: srv.URL, Token: "glpat", HTTP: srv.Client()}
+
+	url, err := client.CreatePullRequest(context.Background(), "group/sub/skills", PullRequest{
+		Title: "Update skill: demo", Head: "feat/x", Base: "main",
+		Labels: []string{"skills", "bot"}, Reviewers: []string{"bob"}, Draft: true,
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+	if url != "https://gitlab.example.com/group/sub/skills/-/merge_requests/3" {
+		t.Fatalf("unexpected url %q", url)
+	}
+
+	create := (*requests)[1]
+	if got := create.Header.Get("PRIVATE-TOKEN"); got != "glpat" {
+		t.Fatalf("unexpected token header %q", got)
+	}
+	if create.Body["title"] != "Draft: Update skill: demo" || create.Body["labels"] != "skills,bot" {
+		t.Fatalf("unexpected body: %v", create.Body)
+	}
+	if ids, _ := create.Body["reviewer_ids"].([]any); len(ids) != 1 || ids[0] != float64(42) {
+		t.Fatalf("unexpected reviewer ids: %v", create.Body["reviewer_ids"])
+	}
+}
+
+func TestBitbucketCreatePullRequest(t *testing.T) {
+	srv, requests := fakeServer(t, map[string]string{
+		"POST /repositories/team/skills/pullrequests": `{"links":{"html":{"href":"https://bitbucket.org/team/skills/pull-requests/5"}}}`,
+	})
+	client := &BitbucketClient{BaseURL: srv.URL, Token: "user:app-pass", HTTP: srv.Client()}
+
+	url, err := client.CreatePullRequest(context.Background(), "team/skills", PullRequest{
+		Title: "Update skill: demo", Head: "feat/x", Base: "main", Reviewers: []string{"{abc}"},
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+	if url != "https://bitbucket.org/team/skills/pull-requests/5" {
+		t.Fatalf("unexpected url %q", url)
+	}
+
+	req := (*requests)[0]
+	if !strings.HasPrefix(req.Header.Get("Authorization"), "Basic ") {
+		t.Fatalf("expected basic auth for user:password token, got %q", req.Header.Get("Authorization"))
+	}
+	source, _ := req.Body["source"].(map[string]any)
+	branch, _ := source["branch"].(map[string]any)
+	if branch["name"] != "feat/x" {
+		t.Fatalf("unexpected source: %v", req.Body["source"])
+	}
+	if reviewers, _ := req.Body["reviewers"].([]any); len(reviewers) != 1 {
+		t.Fatalf("unexpected reviewers: %v", req.Body["reviewers"])
+	}
+}
+
+func TestTokenPrefersConfigOverEnv(t *testing.T) {
+	t.Setenv("SKLI_GITHUB_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "from-env")
+
+	if got := Token(GitHub, "github.com", map[string]string{"github.com": "from-config"}, true); got != "from-config" {
+		t.Fatalf("expected config token, got %q", got)
+	}
+	if got := Token(GitHub, "github.com", nil, true); got != "from-env" {
+		t.Fatalf("expected env token, got %q", got)
+	}
+	if got := Token(GitHub, "github.com.evil.io", nil, false); got != "" {
+		t.Fatalf("env tokens must not be sent to untrusted hosts, got %q", got)
+	}
+	for _, name := range envTokens[GitLab] {
+		t.Setenv(name, "")
+	}
+	if got := Token(GitLab, "gitlab.com", nil, true); got != "" {
+		t.Fatalf("GitHub token must not be used for GitLab, got %q", got)
+	}
+}
+
+func TestGiteaCreatePullRequestResolvesLabels(t *testing.T) {
+	srv, requests := fakeServer(t, map[string]string{
+		"GET /repos/team/skills/labels?limit=100": `[{"id":3,"name":"Skills"},{"id":4,"name":"bug"}]`,
+		"POST /repos/team/skills/pulls":           `{"number":9,"html_url":"https://code.acme.io/team/skills/pulls/9"}`,
+	})
+	client := &GiteaClient{BaseURL: srv.URL, Token: "tok", HTTP: srv.Client()}
+
+	url, err := client.CreatePullRequest(context.Background(), "team/skills", PullRequest{
+		Title: "Update skill: demo", Head: "feat/x", Base: "main", Labels: []string{"skills"}, Draft: true,
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+	if url != "https://code.acme.io/team/skills/pulls/9" {
+		t.Fatalf("unexpected url %q", url)
+	}
+	create := (*requests)[1]
+	if create.Header.Get("Authorization") != "token tok" || create.Body["title"] != "WIP: Update skill: demo" {
+		t.Fatalf("unexpected request: %v %v", create.Header, create.Body)
+	}
+	if labels, _ := create.Body["labels"].([]any); len(labels) != 1 || labels[0] != float64(3) {
+		t.Fatalf("unexpected labels: %v", create.Body["labels"])
+	}
+}
+
+func TestAzureCreatePullRequest(t *testing.T) {
+	srv, requests := fakeServer(t, map[string]string{
+		"POST /acme/proj/_apis/git/repositories/skills/pullrequests?api-version=7.0": `{"pullRequestId":12}`,
+	})
+	client := &AzureClient{BaseURL: srv.URL, Token: "pat", HTTP: srv.Client()}
+
+	url, err := client.CreatePullRequest(context.Background(), "acme/proj/_git/skills", PullRequest{
+		Title: "Update skill: demo", Head: "feat/x", Base: "main", Draft: true,
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+	if url != srv.URL+"/acme/proj/_git/skills/pullrequest/12" {
+		t.Fatalf("unexpected url %q", url)
+	}
+	create := (*requests)[0]
+	if create.Body["sourceRefName"] != "refs/heads/feat/x" || create.Body["isDraft"] != true {
+		t.Fatalf("unexpected body: %v", create.Body)
+	}
+
+	if _, err := client.CreatePullRequest(context.Background(), "acme/skills", PullRequest{}); err == nil {
+		t.Fatalf("expected error for non Azure repo path")
+	}
+}
+
+func TestGitHubForkAndCrossRepoPullRequest(t *testing.T) {
+	srv, requests := fakeServer(t, map[string]string{
+		"POST /repos/acme/skills/forks": `{"full_name":"bob/skills","clone_url":"https://github.com/bob/skills.git","ssh_url":"git@github.com:bob/skills.git"}`,
+		"POST /repos/acme/skills/pulls": `{"number":1,"html_url":"https://github.com/acme/skills/pull/1"}`,
+	})
+	client := &GitHubClient{BaseURL: srv.URL, Token: "t", HTTP: srv.Client()}
+
+	fork, err := client.Fork(context.Background(), "acme/skills")
+	if err != nil {
+		t.Fatalf("Fork: %v", err)
+	}
+	if fork.RepoPath != "bob/skills" || fork.SSHURL != "git@github.com:bob/skills.git" {
+		t.Fatalf("unexpected fork: %+v", fork)
+	}
+
+	if _, err := client.CreatePullRequest(context.Background(), "acme/skills", PullRequest{Title: "t", Head: "feat/x", HeadRepo: fork.RepoPath, Base: "main"}); err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+	if head := (*requests)[1].Body["head"]; head != "bob:feat/x" {
+		t.Fatalf("cross-repo PR must use owner:branch, got %v", head)
+	}
+}
+
+func TestGitLabForkReusesExistingAndTargetsUpstream(t *testing.T) {
+	srv, requests := fakeServer(t, map[string]string{
+		"POST /projects/acme%2Fskills/fork":            `409 {"message":{"name":["has already been taken"]}}`,
+		"GET /projects/acme%2Fskills/forks?owned=true": `[{"id":20,"path_with_namespace":"bob/skills","http_url_to_repo":"https://gitlab.com/bob/skills.git"}]`,
+		"GET /projects/acme%2Fskills":                  `{"id":10}`,
+		"POST /projects/bob%2Fskills/merge_requests":   `{"web_url":"https://gitlab.com/acme/skills/-/merge_requests/1"}`,
+	})
+	client := &GitLabClient{BaseURL: srv.URL, Token: "t", HTTP: srv.Client()}
+
+	fork, err := client.Fork(context.Background(), "acme/skills")
+	if err != nil {
+		t.Fatalf("Fork: %v", err)
+	}
+	if fork.RepoPath != "bob/skills" {
+		t.Fatalf("expected existing fork, got %+v", fork)
+	}
+
+	if _, err := client.CreatePullRequest(context.Background(), "acme/skills", PullRequest{Title: "t", Head: "feat/x", HeadRepo: fork.RepoPath, Base: "main"}); err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+	reqs := *requests
+	if target := reqs[len(reqs)-1].Body["target_project_id"]; target != float64(10) {
+		t.Fatalf("MR must target the upstream project, got %v", target)
+	}
+}
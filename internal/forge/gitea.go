@@ -0,0 +1,127 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// GiteaClient crea PRs con la API REST de Gitea y Forgejo
+type GiteaClient struct {
+	BaseURL string
+	Token   string
+	HTTP    *http.Client
+}
+
+func (c *GiteaClient) auth(req *http.Request) {
+	if c.Token != "" {
+		req.Header.Set("Authorization", "token "+c.Token)
+	}
+}
+
+// CreatePullRequest crea la PR. Las labels se resuelven por nombre a ids y
+// el modo borrador se marca con el prefijo "WIP:" que Gitea reconoce.
+func (c *GiteaClient) CreatePullRequest(ctx context.Context, repoPath string, pr PullRequest) (string, error) {
+	base := strings.TrimSuffix(c.BaseURL, "/") + "/repos/" + strings.Trim(repoPath, "/")
+
+	labelIDs, err := c.labelIDs(ctx, base, pr.Labels)
+	if err != nil {
+		return "", err
+	}
+
+	title := pr.Title
+	if pr.Draft && !strings.HasPrefix(title, "WIP:") {
+		title = "WIP: " + title
+	}
+	head := pr.Head
+	if pr.HeadRepo != "" {
+		head = headOwner(pr.HeadRepo) + ":" + pr.Head
+	}
+	payload := map[string]any{
+		"title": title,
+		"body":  pr.Body,
+		"head":  head,
+		"base":  pr.Base,
+	}
+	if len(labelIDs) > 0 {
+		payload["labels"] = labelIDs
+	}
+
+	var created struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := doJSON(ctx, c.HTTP, http.MethodPost, base+"/pulls", c.auth, payload, &created); err != nil {
+		return "", fmt.Errorf("error creating Gitea pull request: %w", err)
+	}
+
+	if len(pr.Reviewers) > 0 {
+		url := fmt.Sprintf("%s/pulls/%d/requested_reviewers", base, created.Number)
+		if err := doJSON(ctx, c.HTTP, http.MethodPost, url, c.auth, map[string]any{"reviewers": pr.Reviewers}, nil); err != nil {
+			return created.HTMLURL, fmt.Errorf("pull request %s created but reviewers could not be requested: %w", created.HTMLURL, err)
+		}
+	}
+
+	return created.HTMLURL, nil
+}
+
+// giteaRepo es la parte de la respuesta de repos que se usa para forks
+type giteaRepo struct {
+	FullName string `json:"full_name"`
+	CloneURL string `json:"clone_url"`
+	SSHURL   string `json:"ssh_url"`
+}
+
+// Fork crea el fork en la cuenta del token; si ya existe (409) devuelve el existente
+func (c *GiteaClient) Fork(ctx context.Context, repoPath string) (ForkInfo, error) {
+	api := strings.TrimSuffix(c.BaseURL, "/")
+	repoPath = strings.Trim(repoPath, "/")
+
+	var fork giteaRepo
+	err := doJSON(ctx, c.HTTP, http.MethodPost, api+"/repos/"+repoPath+"/forks", c.auth, map[string]any{}, &fork)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Status == http.StatusConflict {
+		var user struct {
+			Login string `json:"login"`
+		}
+		if err := doJSON(ctx, c.HTTP, http.MethodGet, api+"/user", c.auth, nil, &user); err != nil {
+			return ForkInfo{}, fmt.Errorf("error looking up Gitea user: %w", err)
+		}
+		_, name, _ := strings.Cut(repoPath, "/")
+		err = doJSON(ctx, c.HTTP, http.MethodGet, api+"/repos/"+user.Login+"/"+name, c.auth, nil, &fork)
+	}
+	if err != nil {
+		return ForkInfo{}, fmt.Errorf("error forking %s on Gitea: %w", repoPath, err)
+	}
+	return ForkInfo{RepoPath: fork.FullName, CloneURL: fork.CloneURL, SSHURL: fork.SSHURL}, nil
+}
+
+func (c *GiteaClient) labelIDs(ctx context.Context, base string, names []string) ([]int, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	var labels []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := doJSON(ctx, c.HTTP, http.MethodGet, base+"/labels?limit=100", c.auth, nil, &labels); err != nil {
+		return nil, fmt.Errorf("error listing Gitea labels: %w", err)
+	}
+
+	byName := make(map[string]int, len(labels))
+	for _, l := range labels {
+		byName[strings.ToLower(l.Name)] = l.ID
+	}
+	ids := make([]int, 0, len(names))
+	for _, name := range names {
+		id, ok := byName[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("label '%s' does not exist in the repository", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,98 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// GitHubClient crea PRs con la API REST de GitHub (github.com o Enterprise)
+type GitHubClient struct {
+	BaseURL string
+	Token   string
+	HTTP    *http.Client
+}
+
+func (c *GitHubClient) auth(req *http.Request) {
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+}
+
+// CreatePullRequest crea la PR y después aplica labels y reviewers.
+// Los reviewers con forma "org/team" se piden como equipos.
+func (c *GitHubClient) CreatePullRequest(ctx context.Context, repoPath string, pr PullRequest) (string, error) {
+	base := strings.TrimSuffix(c.BaseURL, "/") + "/repos/" + strings.Trim(repoPath, "/")
+
+	head := pr.Head
+	if pr.HeadRepo != "" {
+		head = headOwner(pr.HeadRepo) + ":" + pr.Head
+	}
+
+	var created struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	err := doJSON(ctx, c.HTTP, http.MethodPost, base+"/pulls", c.auth, map[string]any{
+		"title": pr.Title,
+		"body":  pr.Body,
+		"head":  head,
+		"base":  pr.Base,
+		"draft": pr.Draft,
+	}, &created)
+	if err != nil {
+		return "", fmt.Errorf("error creating GitHub pull request: %w", err)
+	}
+
+	if len(pr.Labels) > 0 {
+		url := fmt.Sprintf("%s/issues/%d/labels", base, created.Number)
+		if err := doJSON(ctx, c.HTTP, http.MethodPost, url, c.auth, map[string]any{"labels": pr.Labels}, nil); err != nil {
+			return created.HTMLURL, fmt.Errorf("pull request %s created but labels could not be set: %w", created.HTMLURL, err)
+		}
+	}
+
+	if len(pr.Reviewers) > 0 {
+		users, teams := splitGitHubReviewers(pr.Reviewers)
+		payload := map[string]any{}
+		if len(users) > 0 {
+			payload["reviewers"] = users
+		}
+		if len(teams) > 0 {
+			payload["team_reviewers"] = teams
+		}
+		url := fmt.Sprintf("%s/pulls/%d/requested_reviewers", base, created.Number)
+		if err := doJSON(ctx, c.HTTP, http.MethodPost, url, c.auth, payload, nil); err != nil {
+			return created.HTMLURL, fmt.Errorf("pull request %s created but reviewers could not be requested: %w", created.HTMLURL, err)
+		}
+	}
+
+	return created.HTMLURL, nil
+}
+
+// Fork crea el fork en la cuenta del token. GitHub devuelve el fork existente si ya lo hay;
+// la copia del contenido es asíncrona, así que el primer push puede tener que reintentarse.
+func (c *GitHubClient) Fork(ctx context.Context, repoPath string) (ForkInfo, error) {
+	var fork struct {
+		FullName string `json:"full_name"`
+		CloneURL string `json:"clone_url"`
+		SSHURL   string `json:"ssh_url"`
+	}
+	url := strings.TrimSuffix(c.BaseURL, "/") + "/repos/" + strings.Trim(repoPath, "/") + "/forks"
+	if err := doJSON(ctx, c.HTTP, http.MethodPost, url, c.auth, map[string]any{}, &fork); err != nil {
+		return ForkInfo{}, fmt.Errorf("error forking %s on GitHub: %w", repoPath, err)
+	}
+	return ForkInfo{RepoPath: fork.FullName, CloneURL: fork.CloneURL, SSHURL: fork.SSHURL}, nil
+}
+
+func splitGitHubReviewers(reviewers []string) (users, teams []string) {
+	for _, r := range reviewers {
+		if _, team, ok := strings.Cut(r, "/"); ok {
+			teams = append(teams, team)
+			continue
+		}
+		users = append(users, r)
+	}
+	return users, teams
+}
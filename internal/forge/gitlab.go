@@ -0,0 +1,133 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GitLabClient crea merge requests con la API REST de GitLab (gitlab.com o self-hosted)
+type GitLabClient struct {
+	BaseURL string
+	Token   string
+	HTTP    *http.Client
+}
+
+func (c *GitLabClient) auth(req *http.Request) {
+	if c.Token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.Token)
+	}
+}
+
+// CreatePullRequest crea la MR. Los reviewers se indican por username y se resuelven a ids;
+// el modo borrador se marca con el prefijo "Draft:" en el título.
+func (c *GitLabClient) CreatePullRequest(ctx context.Context, repoPath string, pr PullRequest) (string, error) {
+	api := strings.TrimSuffix(c.BaseURL, "/")
+
+	reviewerIDs := make([]int, 0, len(pr.Reviewers))
+	for _, username := range pr.Reviewers {
+		id, err := c.userID(ctx, username)
+		if err != nil {
+			return "", err
+		}
+		reviewerIDs = append(reviewerIDs, id)
+	}
+
+	title := pr.Title
+	if pr.Draft && !strings.HasPrefix(title, "Draft:") {
+		title = "Draft: " + title
+	}
+	payload := map[string]any{
+		"source_branch": pr.Head,
+		"target_branch": pr.Base,
+		"title":         title,
+		"description":   pr.Body,
+	}
+	if len(pr.Labels) > 0 {
+		payload["labels"] = strings.Join(pr.Labels, ",")
+	}
+	if len(reviewerIDs) > 0 {
+		payload["reviewer_ids"] = reviewerIDs
+	}
+
+	// Desde un fork la MR se crea en el proyecto del fork apuntando al original
+	project := repoPath
+	if pr.HeadRepo != "" {
+		target, err := c.project(ctx, repoPath)
+		if err != nil {
+			return "", err
+		}
+		payload["target_project_id"] = target.ID
+		project = pr.HeadRepo
+	}
+
+	var created struct {
+		WebURL string `json:"web_url"`
+	}
+	endpoint := fmt.Sprintf("%s/projects/%s/merge_requests", api, url.PathEscape(strings.Trim(project, "/")))
+	if err := doJSON(ctx, c.HTTP, http.MethodPost, endpoint, c.auth, payload, &created); err != nil {
+		return "", fmt.Errorf("error creating GitLab merge request: %w", err)
+	}
+	return created.WebURL, nil
+}
+
+func (c *GitLabClient) userID(ctx context.Context, username string) (int, error) {
+	var users []struct {
+		ID int `json:"id"`
+	}
+	endpoint := strings.TrimSuffix(c.BaseURL, "/") + "/users?username=" + url.QueryEscape(username)
+	if err := doJSON(ctx, c.HTTP, http.MethodGet, endpoint, c.auth, nil, &users); err != nil {
+		return 0, fmt.Errorf("error looking up GitLab reviewer '%s': %w", username, err)
+	}
+	if len(users) == 0 {
+		return 0, fmt.Errorf("GitLab reviewer '%s' not found", username)
+	}
+	return users[0].ID, nil
+}
+
+// gitlabProject es la parte de la respuesta de projects que se usa para forks
+type gitlabProject struct {
+	ID                int    `json:"id"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	HTTPURLToRepo     string `json:"http_url_to_repo"`
+	SSHURLToRepo      string `json:"ssh_url_to_repo"`
+}
+
+func (p gitlabProject) forkInfo() ForkInfo {
+	return ForkInfo{RepoPath: p.PathWithNamespace, CloneURL: p.HTTPURLToRepo, SSHURL: p.SSHURLToRepo}
+}
+
+func (c *GitLabClient) project(ctx context.Context, repoPath string) (gitlabProject, error) {
+	var p gitlabProject
+	endpoint := fmt.Sprintf("%s/projects/%s", strings.TrimSuffix(c.BaseURL, "/"), url.PathEscape(strings.Trim(repoPath, "/")))
+	if err := doJSON(ctx, c.HTTP, http.MethodGet, endpoint, c.auth, nil, &p); err != nil {
+		return gitlabProject{}, fmt.Errorf("error looking up GitLab project %s: %w", repoPath, err)
+	}
+	return p, nil
+}
+
+// Fork crea el fork en el namespace del token; si ya existe (409) devuelve el propio
+func (c *GitLabClient) Fork(ctx context.Context, repoPath string) (ForkInfo, error) {
+	base := fmt.Sprintf("%s/projects/%s", strings.TrimSuffix(c.BaseURL, "/"), url.PathEscape(strings.Trim(repoPath, "/")))
+
+	var fork gitlabProject
+	err := doJSON(ctx, c.HTTP, http.MethodPost, base+"/fork", c.auth, map[string]any{}, &fork)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Status == http.StatusConflict {
+		var owned []gitlabProject
+		if err := doJSON(ctx, c.HTTP, http.MethodGet, base+"/forks?owned=true", c.auth, nil, &owned); err != nil {
+			return ForkInfo{}, fmt.Errorf("error listing GitLab forks of %s: %w", repoPath, err)
+		}
+		if len(owned) == 0 {
+			return ForkInfo{}, fmt.Errorf("error forking %s on GitLab: %w", repoPath, apiErr)
+		}
+		return owned[0].forkInfo(), nil
+	}
+	if err != nil {
+		return ForkInfo{}, fmt.Errorf("error forking %s on GitLab: %w", repoPath, err)
+	}
+	return fork.forkInfo(), nil
+}
@@ -0,0 +1,134 @@
+package gitrepo
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"skli/internal/db"
+	"skli/internal/project"
+)
+
+// ContributeResult describe la PR abierta para devolver cambios de un skill instalado
+type ContributeResult struct {
+	PRURL    string
+	Fork     string // Remote del fork si la rama se publicó en un fork
+	Branch   string
+	RepoPath string   // Ruta del skill dentro del repo de origen
+	Base     string   // Rama sobre la que se ha rebasado el cambio
+	Warnings []string // Avisos de la validación previa y de la PR
+}
+
+// ContributeSkill propone al repo de origen los cambios locales de un skill instalado.
+// Aplica los cambios sobre el commit del lock, en la ruta remota exacta, y los rebasa
+// sobre la rama por defecto antes de abrir la PR/MR.
+func ContributeSkill(skill db.InstalledSkill, opts PROptions) (ContributeResult, error) {
+	if skill.RemoteRepo == "" || skill.CommitHash == "" {
+		return ContributeResult{}, fmt.Errorf("skill '%s' has no source repo or commit in skli.lock", skill.Name)
+	}
+	repoPath := skill.RemoteTreePath()
+	if repoPath == "" {
+		return ContributeResult{}, fmt.Errorf("skill '%s' has no remote path in skli.lock", skill.Name)
+	}
+	// Un artefacto convertido (ej: una regla .mdc) sustituiría la carpeta con el SKILL.md del repo
+	if skill.Converter != "" {
+		return ContributeResult{}, fmt.Errorf("skill '%s' is installed in the %s format; contribute from a SKILL.md folder instead", skill.Name, skill.Converter)
+	}
+	// Con valores los placeholders {{ skli.* }} ya están sustituidos: se publicarían los valores del equipo
+	if len(skill.Values) > 0 {
+		names := make([]string, 0, len(skill.Values))
+		for name := range skill.Values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return ContributeResult{}, fmt.Errorf("skill '%s' was installed with values for %s; contributing would replace its {{ skli.* }} placeholders upstream, propose the change from the template instead", skill.Name, strings.Join(names, ", "))
+	}
+
+	warnings, err := validateSkills([]db.InstalledSkill{skill})
+	if err != nil {
+		return ContributeResult{}, err
+	}
+
+	remoteURL := ParseGitURL(skill.RemoteRepo).BaseURL
+	// Sin depth: hace falta la historia para partir del commit del lock y rebasar
+	tempDir, err := clonePartial(remoteURL)
+	if err != nil {
+		return ContributeResult{}, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := runGit(tempDir, "checkout", "-q", "--detach", skill.CommitHash); err != nil {
+		return ContributeResult{}, fmt.Errorf("locked commit %s not found in %s: %w", skill.CommitHash, remoteURL, err)
+	}
+	if err := CheckoutPaths(tempDir, repoPath); err != nil {
+		return ContributeResult{}, err
+	}
+	data := newTemplateData(tempDir, remoteURL, skill.Name, []string{skill.Name}, opts.Vars)
+	branchName, err := renderBranchName(opts.Templates.Branch, data)
+	if err != nil {
+		return ContributeResult{}, err
+	}
+	if err := PrepareSkillBranch(tempDir, branchName); err != nil {
+		return ContributeResult{}, err
+	}
+
+	if err := CopySkillFiles(tempDir, project.Resolve(skill.Path), filepath.FromSlash(repoPath)); err != nil {
+		return ContributeResult{}, err
+	}
+	stats, changed, err := StageSkill(tempDir, filepath.FromSlash(repoPath))
+	if err != nil {
+		return ContributeResult{}, err
+	}
+	if !changed {
+		return ContributeResult{}, fmt.Errorf("no local changes in '%s' since commit %s", skill.Name, skill.CommitHash)
+	}
+
+	uploaded := UploadedSkill{Name: skill.Name, Description: skill.Description, RepoPath: repoPath, Stats: stats}
+	msg, err := renderCommitMessage(opts.Templates.Commit, data.forSkill(uploaded))
+	if err != nil {
+		return ContributeResult{}, err
+	}
+	if err := Commit(tempDir, msg, opts.Templates.Signoff); err != nil {
+		return ContributeResult{}, err
+	}
+
+	base := getDefaultBranch(tempDir)
+	if err := rebaseOnto(tempDir, "origin/"+base, skill.CommitHash); err != nil {
+		return ContributeResult{}, err
+	}
+
+	title, body, err := renderPR(opts, data.forPR([]UploadedSkill{uploaded}))
+	if err != nil {
+		return ContributeResult{}, err
+	}
+	pr, err := PushAndCreatePR(tempDir, remoteURL, branchName, title, body, opts)
+	if err != nil {
+		return ContributeResult{}, err
+	}
+
+	if pr.Warning != "" {
+		warnings = append(warnings, pr.Warning)
+	}
+	return ContributeResult{PRURL: pr.URL, Fork: pr.Fork, Branch: branchName, RepoPath: repoPath, Base: base, Warnings: warnings}, nil
+}
+
+// rebaseOnto mueve los commits posteriores a upstream de la rama actual sobre newBase.
+// Si hay conflictos aborta el rebase e informa de los ficheros afectados.
+func rebaseOnto(repoDir, newBase, upstream string) error {
+	if err := runGit(repoDir, "rebase", "--onto", newBase, upstream); err != nil {
+		cmd := exec.Command("git", "diff", "--name-only", "--diff-filter=U")
+		cmd.Dir = repoDir
+		output, _ := cmd.Output()
+		_ = runGit(repoDir, "rebase", "--abort")
+
+		conflicts := strings.Fields(string(output))
+		if len(conflicts) > 0 {
+			return fmt.Errorf("local changes conflict with %s: %s", newBase, strings.Join(conflicts, ", "))
+		}
+		return fmt.Errorf("error rebasing onto %s: %w", newBase, err)
+	}
+	return nil
+}
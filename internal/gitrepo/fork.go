@@ -0,0 +1,118 @@
+package gitrepo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"skli/internal/config"
+	"skli/internal/forge"
+)
+
+var (
+	rememberForkFn = config.RememberFork
+
+	// Un fork recién creado puede tardar en aceptar pushes (GitHub lo copia en segundo plano)
+	forkPushAttempts = 5
+	forkPushDelay    = 3 * time.Second
+)
+
+// forkTarget es el fork en el que se publicó la rama
+type forkTarget struct {
+	url      string // Remote al que se hizo push
+	repoPath string // Ruta del fork en el provider (ej: "me/skills")
+}
+
+// pushToFork publica la rama en el fork configurado o, si no hay, crea uno con la API del provider
+// y lo guarda en la config para siguientes subidas.
+func pushToFork(repoDir, remoteURL, branchName, forkURL string, client forge.Client) (forkTarget, error) {
+	upstream := normalizeRepoWebURL(remoteURL)
+	fork := forkTarget{url: forkURL}
+	attempts := 1
+
+	if fork.url == "" {
+		forker, ok := client.(forge.Forker)
+		if !ok {
+			return forkTarget{}, fmt.Errorf("no push access to %s: set an API token to fork it or configure your fork under [pull_requests.forks]", upstream)
+		}
+
+		_, repoPath := repoHostAndPath(remoteURL)
+		ctx, cancel := context.WithTimeout(context.Background(), forge.DefaultTimeout)
+		info, err := forker.Fork(ctx, repoPath)
+		cancel()
+		if err != nil {
+			return forkTarget{}, err
+		}
+
+		fork.url = info.CloneURL
+		if isSSHURL(remoteURL) && info.SSHURL != "" {
+			fork.url = info.SSHURL
+		}
+		fork.repoPath = info.RepoPath
+		attempts = forkPushAttempts
+	} else {
+		_, fork.repoPath = repoHostAndPath(fork.url)
+	}
+
+	if err := runGit(repoDir, "remote", "add", "fork", fork.url); err != nil {
+		return forkTarget{}, fmt.Errorf("error adding fork remote: %w", err)
+	}
+
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			time.Sleep(forkPushDelay)
+		}
+		if err = pushBranch(repoDir, "fork", branchName); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return forkTarget{}, fmt.Errorf("git push to fork %s failed: %w", fork.url, err)
+	}
+
+	if forkURL == "" {
+		// No recordar el fork no impide la PR; la próxima subida volverá a resolverlo con la API
+		_ = rememberForkFn(upstream, fork.url)
+	}
+	return fork, nil
+}
+
+// buildForkPRURL genera la URL para abrir a mano una PR desde el fork hacia el repo original
+func buildForkPRURL(provider forge.Provider, upstreamURL string, fork forkTarget, branchName, targetBranch, title string) string {
+	switch provider {
+	case forge.GitHub, forge.Gitea:
+		owner, _, _ := strings.Cut(fork.repoPath, "/")
+		return buildPRURL(provider, upstreamURL, owner+":"+branchName, targetBranch, title)
+	case forge.GitLab, forge.Bitbucket:
+		// La página de nueva PR del fork propone el repo original como destino
+		return buildPRURL(provider, fork.url, branchName, targetBranch, title)
+	default:
+		return normalizeRepoWebURL(upstreamURL)
+	}
+}
+
+// isPushPermissionError detecta los rechazos de push por falta de permisos o credenciales
+func isPushPermissionError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, pattern := range []string{
+		"permission to",
+		"permission denied",
+		"not allowed to push",
+		"you are not allowed",
+		"access denied",
+		"insufficient permission",
+		"could not read username",
+		"the requested url returned error: 403",
+	} {
+		if strings.Contains(msg, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSSHURL(remoteURL string) bool {
+	return strings.HasPrefix(remoteURL, "ssh://") || (!strings.Contains(remoteURL, "://") && strings.Contains(remoteURL, "@"))
+}
@@ -3,11 +3,14 @@ package gitrepo
 import (
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 
+	"skli/internal/forge"
+	"skli/internal/security"
 	"skli/internal/skillmeta"
 )
 
@@ -104,7 +107,8 @@ func findSkillsInNestedSkillsDir(repoRoot string) ([]SkillInfo, string, error) {
 }
 
 // ParseGitURL analiza una URL de repositorio y extrae el repo base, rama y path interno
-// Soporta GitHub (/tree/), Bitbucket (/src/) y URLs estándar (HTTPS, SSH)
+// Soporta GitHub (/tree/), GitLab (/-/tree/), Bitbucket (/src/), Gitea/Forgejo (/src/branch/),
+// Azure DevOps (?path=&version=) y URLs estándar (HTTPS, SSH)
 func ParseGitURL(urlStr string) RepoInfo {
 	info := RepoInfo{
 		BaseURL: urlStr,
@@ -114,35 +118,54 @@ func ParseGitURL(urlStr string) RepoInfo {
 
 	urlStr = strings.TrimSuffix(urlStr, "/")
 
-	// Caso SSH (ej: git@github.com:user/repo.git)
-	if strings.Contains(urlStr, "@") && strings.Contains(urlStr, ":") {
+	// Caso SSH (ej: git@github.com:user/repo.git o ssh://git@host/repo.git)
+	if strings.HasPrefix(urlStr, "ssh://") || (!strings.Contains(urlStr, "://") && strings.Contains(urlStr, "@") && strings.Contains(urlStr, ":")) {
 		// En SSH no solemos tener "tree/rama/path" en la URL misma
 		return info
 	}
 
-	var delimiter string
-	if strings.Contains(urlStr, "github.com") {
-		delimiter = "/tree/"
-	} else if strings.Contains(urlStr, "bitbucket.org") {
-		delimiter = "/src/"
-	}
-
-	if delimiter != "" {
-		parts := strings.Split(urlStr, delimiter)
-		if len(parts) == 2 {
-			info.BaseURL = parts[0]
+	provider := detectProvider(urlStr)
 
-			// El resto es "rama/path"
-			remaining := parts[1]
-			subParts := strings.Split(remaining, "/")
-			if len(subParts) > 0 {
-				info.Branch = subParts[0]
-				if len(subParts) > 1 {
-					info.SubPath = strings.Join(subParts[1:], "/")
-				}
+	// Azure DevOps: la rama y el path van en la query (?path=/skills&version=GBmain)
+	if base, query, ok := strings.Cut(urlStr, "?"); ok && (provider == forge.Azure || strings.Contains(base, "/_git/")) {
+		info.BaseURL = base
+		if q, err := url.ParseQuery(query); err == nil {
+			info.SubPath = strings.Trim(q.Get("path"), "/")
+			if version := q.Get("version"); len(version) > 2 {
+				// GB = rama, GT = tag, GC = commit
+				info.Branch = version[2:]
 			}
-			return info
 		}
+		return info
+	}
+
+	var delimiters []string
+	switch provider {
+	case forge.GitHub:
+		delimiters = []string{"/tree/"}
+	case forge.GitLab:
+		delimiters = []string{"/-/tree/", "/tree/"}
+	case forge.Bitbucket:
+		delimiters = []string{"/src/"}
+	case forge.Gitea:
+		delimiters = []string{"/src/branch/", "/src/tag/", "/src/commit/"}
+	default:
+		// Formatos inequívocos aunque el host no esté configurado
+		delimiters = []string{"/-/tree/", "/src/branch/"}
+	}
+
+	for _, delimiter := range delimiters {
+		base, remaining, ok := strings.Cut(urlStr, delimiter)
+		if !ok {
+			continue
+		}
+		info.BaseURL = base
+
+		// El resto es "rama/path"
+		branch, subPath, _ := strings.Cut(remaining, "/")
+		info.Branch = branch
+		info.SubPath = subPath
+		return info
 	}
 
 	// Fallback para URLs estándar
@@ -155,6 +178,9 @@ type SkillInfo struct {
 	Description string
 	Path        string // Ruta relativa dentro del repo (para copiar)
 	TreeHash    string // Hash del árbol de git para esta carpeta
+	skillmeta.Fields
+	Findings []security.Finding // Hallazgos del análisis de seguridad, si se ha hecho
+	Signer   string             // Firmante verificado del commit (vacío si el remote no exige firmas)
 }
 
 // ScanResult contiene el resultado del escaneo de un repositorio
@@ -312,6 +338,104 @@ func GetRemoteHash(repoURL string) (string, error) {
 	return parts[0], nil
 }
 
+// RemoteBranchExists indica si la rama existe en el remoto
+func RemoteBranchExists(repoURL, branch string) (bool, error) {
+	cmd := exec.Command("git", "ls-remote", "--heads", ParseGitURL(repoURL).BaseURL, "refs/heads/"+branch)
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("error checking branch %s: %w", branch, err)
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
+// RemoteTreeHashes obtiene el hash del árbol de cada path en un commit remoto sin descargar blobs.
+// Si commit está vacío se usa la rama indicada en la URL. Los paths que no existen no aparecen en el mapa.
+func RemoteTreeHashes(repoURL, commit string, paths []string) (map[string]string, error) {
+	repoInfo := ParseGitURL(repoURL)
+	ref := commit
+	if ref == "" {
+		ref = repoInfo.Branch
+	}
+
+	tempDir, err := os.MkdirTemp("", "skli-tree-*")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temp dir: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := runGit(tempDir, "init", "--bare"); err != nil {
+		return nil, fmt.Errorf("error initializing repo: %w", err)
+	}
+
+	// Solo hacen falta los árboles; si el servidor no soporta filtros se descarga el commit completo
+	if err := runGit(tempDir, "fetch", "--depth", "1", "--filter=blob:none", repoInfo.BaseURL, ref); err != nil {
+		if err := runGit(tempDir, "fetch", "--depth", "1", repoInfo.BaseURL, ref); err != nil {
+			return nil, fmt.Errorf("error fetching %s (%s): %w", repoInfo.BaseURL, ref, err)
+		}
+	}
+
+	hashes := make(map[string]string, len(paths))
+	for _, p := range paths {
+		cmd := exec.Command("git", "rev-parse", "FETCH_HEAD:"+p)
+		cmd.Dir = tempDir
+		output, err := cmd.Output()
+		if err != nil {
+			continue
+		}
+		hashes[p] = strings.TrimSpace(string(output))
+	}
+
+	return hashes, nil
+}
+
+// FetchPaths descarga con sparse-checkout y depth 1 solo los paths indicados de un commit concreto.
+// Si commit está vacío se usa la rama de la URL. Devuelve el directorio temporal con el checkout.
+func FetchPaths(repoURL, commit string, paths []string) (string, error) {
+	repoInfo := ParseGitURL(repoURL)
+	ref := commit
+	if ref == "" {
+		ref = repoInfo.Branch
+	}
+
+	tempDir, err := os.MkdirTemp("", "skli-fetch-*")
+	if err != nil {
+		return "", fmt.Errorf("error creating temp dir: %w", err)
+	}
+
+	steps := [][]string{
+		{"init"},
+		{"remote", "add", "origin", repoInfo.BaseURL},
+		{"config", "core.sparseCheckout", "true"},
+	}
+	for _, args := range steps {
+		if err := runGit(tempDir, args...); err != nil {
+			os.RemoveAll(tempDir)
+			return "", fmt.Errorf("error preparing repo: %w", err)
+		}
+	}
+
+	var sparse strings.Builder
+	for _, p := range paths {
+		sparse.WriteString(strings.TrimSuffix(p, "/") + "/\n")
+	}
+	sparseFile := filepath.Join(tempDir, ".git", "info", "sparse-checkout")
+	if err := os.WriteFile(sparseFile, []byte(sparse.String()), 0644); err != nil {
+		os.RemoveAll(tempDir)
+		return "", fmt.Errorf("error writing sparse-checkout: %w", err)
+	}
+
+	if err := runGit(tempDir, "fetch", "--depth", "1", "origin", ref); err != nil {
+		os.RemoveAll(tempDir)
+		return "", fmt.Errorf("error fetching %s (%s): %w", repoInfo.BaseURL, ref, err)
+	}
+	if err := runGit(tempDir, "checkout", "FETCH_HEAD"); err != nil {
+		os.RemoveAll(tempDir)
+		return "", fmt.Errorf("error checking out: %w", err)
+	}
+
+	return tempDir, nil
+}
+
 // findSkills busca recursivamente archivos SKILL.md y extrae sus metadatos
 func findSkills(baseDir string) ([]SkillInfo, error) {
 	var skills []SkillInfo
@@ -347,12 +471,13 @@ func parseSkillFile(filePath, baseDir string) (SkillInfo, error) {
 		skill.TreeHash = treeHash
 	}
 
-	meta, err := skillmeta.ParseFile(filePath, 20)
+	meta, err := skillmeta.ParseFile(filePath)
 	if err != nil {
 		return SkillInfo{}, err
 	}
 	skill.Name = meta.Name
 	skill.Description = meta.Description
+	skill.Fields = meta.Fields
 
 	return skill, nil
 }
@@ -371,12 +496,7 @@ func InstallSkills(tempRepoPath, skillsPath, localPath string, selectedSkills []
 	}
 
 	for _, skill := range selectedSkills {
-		var src string
-		if skillsPath == "." {
-			src = filepath.Join(tempRepoPath, skill.Path)
-		} else {
-			src = filepath.Join(tempRepoPath, skillsPath, skill.Path)
-		}
+		src := SkillSource(tempRepoPath, skillsPath, skill)
 
 		// Determinar el nombre de la carpeta destino (siempre plana)
 		folderName := GetSkillFolderName(skill)
@@ -396,6 +516,29 @@ func InstallSkills(tempRepoPath, skillsPath, localPath string, selectedSkills []
 	return nil
 }
 
+// SkillSource devuelve la carpeta del skill dentro del repo clonado en tempRepoPath
+func SkillSource(tempRepoPath, skillsPath string, skill SkillInfo) string {
+	if skillsPath == "" {
+		skillsPath = DefaultSkillsPath
+	}
+	if skillsPath == "." {
+		return filepath.Join(tempRepoPath, skill.Path)
+	}
+	return filepath.Join(tempRepoPath, skillsPath, skill.Path)
+}
+
+// ScanSecurity analiza cada skill del resultado y guarda sus hallazgos
+func ScanSecurity(res *ScanResult, remoteURL string) error {
+	for i := range res.Skills {
+		findings, err := security.Scan(SkillSource(res.TempDir, res.SkillsPath, res.Skills[i]), remoteURL)
+		if err != nil {
+			return fmt.Errorf("%s: %w", res.Skills[i].Name, err)
+		}
+		res.Skills[i].Findings = findings
+	}
+	return nil
+}
+
 // copyDir copies a directory recursively from src to dst.
 func copyDir(src string, dst string) error {
 	srcInfo, err := os.Stat(src)
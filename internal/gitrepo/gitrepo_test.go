@@ -1,11 +1,71 @@
 package gitrepo
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+
+	"skli/internal/db"
+	"skli/internal/forge"
+	"skli/internal/integrity"
 )
 
+// initBareRemote crea un repo bare a partir de initTestRepo para poder hacer push
+func initBareRemote(t *testing.T, files map[string]string) string {
+	t.Helper()
+	src := initTestRepo(t, files)
+	bare := filepath.Join(t.TempDir(), "remote.git")
+	if err := runGit(filepath.Dir(bare), "clone", "-q", "--bare", src, bare); err != nil {
+		t.Fatalf("clone bare: %v", err)
+	}
+	return bare
+}
+
+// gitOutput ejecuta git y devuelve la salida recortada
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v: %s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// initTestRepo crea un repo git local con los ficheros indicados y devuelve su ruta
+func initTestRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	for name, content := range files {
+		full := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"add", "."},
+		{"-c", "user.email=test@skli", "-c", "user.name=test", "commit", "-q", "-m", "init"},
+	} {
+		if err := runGit(dir, args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+	return dir
+}
+
 func TestParseGitURLGitHubTree(t *testing.T) {
 	info := ParseGitURL("https://github.com/org/repo/tree/main/skills/experimental")
 	if info.BaseURL != "https://github.com/org/repo" {
@@ -106,3 +166,785 @@ func TestInstallSkills(t *testing.T) {
 		t.Fatalf("expected installed skill file: %v", err)
 	}
 }
+
+func TestRemoteTreeHashes(t *testing.T) {
+	repo := initTestRepo(t, map[string]string{
+		"skills/alpha/SKILL.md": "---\nname: alpha\n---\n",
+	})
+	want, err := getTreeHash(repo, "skills/alpha")
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit, err := getCommitHash(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hashes, err := RemoteTreeHashes("file://"+repo, commit, []string{"skills/alpha", "skills/missing"})
+	if err != nil {
+		t.Fatalf("RemoteTreeHashes: %v", err)
+	}
+	if hashes["skills/alpha"] != want {
+		t.Fatalf("expected %s, got %+v", want, hashes)
+	}
+	if _, ok := hashes["skills/missing"]; ok {
+		t.Fatalf("missing path must not be returned")
+	}
+}
+
+func TestUploadSkillsSinglePRWithOneCommitPerSkill(t *testing.T) {
+	setGitIdentity(t)
+
+	remote := initBareRemote(t, map[string]string{
+		"skills/existing/SKILL.md": "---\nname: existing\n---\nold\n",
+		"skills/same/SKILL.md":     "---\nname: same\ndescription: test\n---\n",
+	})
+
+	local := t.TempDir()
+	write := func(rel, content string) string {
+		full := filepath.Join(local, rel)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return filepath.Dir(full)
+	}
+	existing := write("existing/SKILL.md", "---\nname: existing\ndescription: test\n---\nnew\n")
+	fresh := write("fresh/SKILL.md", "---\nname: fresh\ndescription: test\n---\n")
+	same := write("same/SKILL.md", "---\nname: same\ndescription: test\n---\n")
+
+	result, err := UploadSkills([]db.InstalledSkill{
+		{Name: "existing", Path: existing},
+		{Name: "fresh", Path: fresh, Description: "brand new"},
+		{Name: "same", Path: same},
+	}, remote, nil, PROptions{})
+	if err != nil {
+		t.Fatalf("UploadSkills: %v", err)
+	}
+
+	if len(result.Skills) != 2 || result.Skills[0].RepoPath != "skills/existing" || result.Skills[1].RepoPath != "skills/fresh" {
+		t.Fatalf("unexpected uploaded skills: %+v", result.Skills)
+	}
+	if len(result.Unchanged) != 1 || result.Unchanged[0] != "same" {
+		t.Fatalf("unexpected unchanged skills: %+v", result.Unchanged)
+	}
+
+	count := gitOutput(t, remote, "rev-list", "--count", "main.."+result.Branch)
+	if count != "2" {
+		t.Fatalf("expected one commit per changed skill, got %s", count)
+	}
+}
+
+func TestDescribePRListsEverySkill(t *testing.T) {
+	title, body := describePR([]UploadedSkill{
+		{Name: "a", RepoPath: "skills/a", Description: "first"},
+		{Name: "b", RepoPath: "skills/b"},
+	})
+	if title != "Update skills: a, b" {
+		t.Fatalf("unexpected title: %q", title)
+	}
+	if !strings.Contains(body, "- **a** (`skills/a`): first") || !strings.Contains(body, "- **b** (`skills/b`)") {
+		t.Fatalf("body must list every skill:\n%s", body)
+	}
+}
+
+// pushRemoteChange añade un commit al repo bare con los ficheros indicados
+func pushRemoteChange(t *testing.T, remote string, files map[string]string) {
+	t.Helper()
+	work := filepath.Join(t.TempDir(), "work")
+	gitOutput(t, filepath.Dir(work), "clone", "-q", remote, work)
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(work, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitOutput(t, work, "add", ".")
+	gitOutput(t, work, "-c", "user.email=test@skli", "-c", "user.name=test", "commit", "-q", "-m", "remote change")
+	gitOutput(t, work, "push", "-q", "origin", "main")
+}
+
+func setGitIdentity(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@skli")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@skli")
+}
+
+func TestContributeSkillRebasesOntoDefaultBranch(t *testing.T) {
+	setGitIdentity(t)
+	remote := initBareRemote(t, map[string]string{
+		"skills/demo/SKILL.md": "---\nname: demo\n---\nv1\n",
+		"README.md":            "readme\n",
+	})
+	locked := gitOutput(t, remote, "rev-parse", "main")
+	pushRemoteChange(t, remote, map[string]string{"README.md": "readme v2\n"})
+	head := gitOutput(t, remote, "rev-parse", "main")
+
+	local := filepath.Join(t.TempDir(), "demo")
+	if err := os.MkdirAll(local, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(local, "SKILL.md"), []byte("---\nname: demo\ndescription: test\n---\nv1 improved\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ContributeSkill(db.InstalledSkill{
+		Name:       "demo",
+		Path:       local,
+		RemoteRepo: remote,
+		RemoteRoot: "skills",
+		RemotePath: "demo",
+		CommitHash: locked,
+	}, PROptions{})
+	if err != nil {
+		t.Fatalf("ContributeSkill: %v", err)
+	}
+	if result.RepoPath != "skills/demo" || result.Base != "main" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if parent := gitOutput(t, remote, "rev-parse", result.Branch+"^"); parent != head {
+		t.Fatalf("branch must be rebased onto %s, parent is %s", head, parent)
+	}
+	if got := gitOutput(t, remote, "show", result.Branch+":skills/demo/SKILL.md"); !strings.Contains(got, "v1 improved") {
+		t.Fatalf("local changes not applied: %q", got)
+	}
+}
+
+func TestContributeSkillReportsConflicts(t *testing.T) {
+	setGitIdentity(t)
+	remote := initBareRemote(t, map[string]string{
+		"skills/demo/SKILL.md": "---\nname: demo\n---\nv1\n",
+	})
+	locked := gitOutput(t, remote, "rev-parse", "main")
+	pushRemoteChange(t, remote, map[string]string{"skills/demo/SKILL.md": "---\nname: demo\n---\nupstream\n"})
+
+	local := filepath.Join(t.TempDir(), "demo")
+	if err := os.MkdirAll(local, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(local, "SKILL.md"), []byte("---\nname: demo\ndescription: test\n---\nlocal\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := ContributeSkill(db.InstalledSkill{
+		Name:       "demo",
+		Path:       local,
+		RemoteRepo: remote,
+		RemoteRoot: "skills",
+		RemotePath: "demo",
+		CommitHash: locked,
+	}, PROptions{})
+	if err == nil || !strings.Contains(err.Error(), "skills/demo/SKILL.md") {
+		t.Fatalf("expected conflict error naming the file, got %v", err)
+	}
+}
+
+func TestContributeSkillRejectsConvertedInstalls(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "demo.mdc")
+	if err := os.WriteFile(file, []byte("---\ndescription: test\n---\nrule\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := ContributeSkill(db.InstalledSkill{
+		Name:       "demo",
+		Path:       file,
+		RemoteRepo: "https://example.com/acme/skills.git",
+		RemoteRoot: "skills",
+		RemotePath: "demo",
+		CommitHash: "abc",
+		Converter:  "cursor-mdc",
+	}, PROptions{})
+	if err == nil || !strings.Contains(err.Error(), "cursor-mdc") {
+		t.Fatalf("converted installs must not be contributed, got %v", err)
+	}
+}
+
+func TestContributeSkillRejectsRenderedValues(t *testing.T) {
+	local := filepath.Join(t.TempDir(), "demo")
+	if err := os.MkdirAll(local, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(local, "SKILL.md"), []byte("---\nname: demo\ndescription: test\n---\nDeploy to acme-prod\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := ContributeSkill(db.InstalledSkill{
+		Name:       "demo",
+		Path:       local,
+		RemoteRepo: "https://example.com/acme/skills.git",
+		RemoteRoot: "skills",
+		RemotePath: "demo",
+		CommitHash: "abc",
+		Values:     map[string]string{"project": "acme-prod", "cluster": "eu-1"},
+	}, PROptions{})
+	if err == nil || !strings.Contains(err.Error(), "cluster, project") {
+		t.Fatalf("skills with rendered values must not be contributed, got %v", err)
+	}
+}
+
+func TestRepoHostAndPath(t *testing.T) {
+	cases := map[string][2]string{
+		"https://github.com/acme/skills.git":              {"github.com", "acme/skills"},
+		"git@gitlab.com:group/sub/skills.git":             {"gitlab.com", "group/sub/skills"},
+		"https://github.com/acme/skills/tree/main/skills": {"github.com", "acme/skills"},
+	}
+	for in, want := range cases {
+		host, path := repoHostAndPath(in)
+		if host != want[0] || path != want[1] {
+			t.Fatalf("%s: got (%q, %q), want %v", in, host, path, want)
+		}
+	}
+}
+
+func TestParseGitURLSelfHostedFormats(t *testing.T) {
+	if err := SetHostProviders(map[string]string{"git.acme.io": "gitlab", "code.acme.io": "forgejo"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = SetHostProviders(nil) })
+
+	cases := []struct {
+		in                    string
+		base, branch, subPath string
+	}{
+		{"https://git.acme.io/team/skills/-/tree/main/skills/core", "https://git.acme.io/team/skills", "main", "skills/core"},
+		{"https://git.acme.io/team/skills/tree/dev", "https://git.acme.io/team/skills", "dev", ""},
+		{"https://code.acme.io/team/skills/src/branch/main/skills", "https://code.acme.io/team/skills", "main", "skills"},
+		{"https://other.example/team/skills/-/tree/v2/skills", "https://other.example/team/skills", "v2", "skills"},
+		{"https://dev.azure.com/acme/proj/_git/skills?path=/skills/core&version=GBrelease", "https://dev.azure.com/acme/proj/_git/skills", "release", "skills/core"},
+	}
+	for _, c := range cases {
+		info := ParseGitURL(c.in)
+		if info.BaseURL != c.base || info.Branch != c.branch || info.SubPath != c.subPath {
+			t.Fatalf("%s: got %+v", c.in, info)
+		}
+	}
+}
+
+func TestDetectProviderUsesConfiguredHosts(t *testing.T) {
+	if detectProvider("https://git.acme.io/team/skills.git") != forge.Unknown {
+		t.Fatalf("unconfigured host must be unknown")
+	}
+	for _, url := range []string{"https://github.com.evil.io/o/r", "https://evil.io/github.com/o/r", "git@gitlab.com.evil.io:o/r.git"} {
+		if detectProvider(url) != forge.Unknown || knownHost(url) {
+			t.Fatalf("%s must not be recognised as a known forge", url)
+		}
+	}
+	if !knownHost("git@github.com:acme/skills.git") {
+		t.Fatalf("public hosts must be known")
+	}
+	if err := SetHostProviders(map[string]string{"git.acme.io": "gitea"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = SetHostProviders(nil) })
+
+	if got := detectProvider("git@git.acme.io:team/skills.git"); got != forge.Gitea {
+		t.Fatalf("expected gitea for configured host, got %v", got)
+	}
+	if !knownHost("https://git.acme.io/team/skills") {
+		t.Fatalf("configured hosts must be known")
+	}
+	if got := detectProvider("https://acme.visualstudio.com/proj/_git/skills"); got != forge.Azure {
+		t.Fatalf("expected azure, got %v", got)
+	}
+	if err := SetHostProviders(map[string]string{"x": "svn"}); err == nil {
+		t.Fatalf("expected error for unknown provider name")
+	}
+}
+
+func TestSSHPortIsNotUsedForTheWebOrTokens(t *testing.T) {
+	if err := SetHostProviders(map[string]string{"gitlab.corp": "gitlab"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = SetHostProviders(nil) })
+
+	remote := "ssh://git@gitlab.corp:2222/group/skills.git"
+	if got := normalizeRepoWebURL(remote); got != "https://gitlab.corp/group/skills" {
+		t.Fatalf("unexpected web URL %q", got)
+	}
+	if host, path := repoHostAndPath(remote); host != "gitlab.corp" || path != "group/skills" {
+		t.Fatalf("unexpected host and path (%q, %q)", host, path)
+	}
+	if got := buildPRURL(detectProvider(remote), remote, "feat/x", "main", "t"); !strings.HasPrefix(got, "https://gitlab.corp/group/skills/-/merge_requests/new?") {
+		t.Fatalf("unexpected PR URL %q", got)
+	}
+
+	client, ok := newForgeClient(remote, PROptions{Tokens: map[string]string{"gitlab.corp": "secret"}}).(*forge.GitLabClient)
+	if !ok || client.Token != "secret" || client.BaseURL != "https://gitlab.corp/api/v4" {
+		t.Fatalf("expected a GitLab client for gitlab.corp, got %+v", client)
+	}
+}
+
+func TestBuildPRURLSelfHosted(t *testing.T) {
+	if got := buildPRURL(forge.Gitea, "https://code.acme.io/team/skills.git", "feat/x", "main", "t"); got != "https://code.acme.io/team/skills/compare/main...feat%2Fx" {
+		t.Fatalf("unexpected Gitea URL %q", got)
+	}
+	got := buildPRURL(forge.Azure, "git@ssh.dev.azure.com:v3/acme/proj/skills", "feat/x", "main", "t")
+	if got != "https://dev.azure.com/acme/proj/_git/skills/pullrequestcreate?sourceRef=feat%2Fx&targetRef=main" {
+		t.Fatalf("unexpected Azure URL %q", got)
+	}
+	if got := normalizeRepoWebURL("https://acme.visualstudio.com/proj/_git/skills"); got != "https://dev.azure.com/acme/proj/_git/skills" {
+		t.Fatalf("unexpected legacy Azure URL %q", got)
+	}
+}
+
+func TestUploadSkillsPushesToConfiguredFork(t *testing.T) {
+	setGitIdentity(t)
+	upstream := initBareRemote(t, map[string]string{"README.md": "upstream\n"})
+	fork := initBareRemote(t, map[string]string{"README.md": "upstream\n"})
+
+	local := filepath.Join(t.TempDir(), "demo")
+	if err := os.MkdirAll(local, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(local, "SKILL.md"), []byte("---\nname: demo\ndescription: test\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	remembered := false
+	prev := rememberForkFn
+	rememberForkFn = func(string, string) error { remembered = true; return nil }
+	t.Cleanup(func() { rememberForkFn = prev })
+
+	opts := PROptions{Forks: map[string]string{normalizeRepoWebURL(upstream): fork}}
+	result, err := UploadSkills([]db.InstalledSkill{{Name: "demo", Path: local}}, upstream, nil, opts)
+	if err != nil {
+		t.Fatalf("UploadSkills: %v", err)
+	}
+	if result.Fork != fork {
+		t.Fatalf("expected push to fork %s, got %q", fork, result.Fork)
+	}
+	if got := gitOutput(t, fork, "branch", "--list", result.Branch); got == "" {
+		t.Fatalf("branch %s missing in fork", result.Branch)
+	}
+	if got := gitOutput(t, upstream, "branch", "--list", result.Branch); got != "" {
+		t.Fatalf("branch must not be pushed to upstream")
+	}
+	if remembered {
+		t.Fatalf("an already configured fork must not be saved again")
+	}
+}
+
+func TestUploadSkillsForkWithoutTokenOrConfig(t *testing.T) {
+	setGitIdentity(t)
+	upstream := initBareRemote(t, map[string]string{"README.md": "upstream\n"})
+	local := filepath.Join(t.TempDir(), "demo")
+	if err := os.MkdirAll(local, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(local, "SKILL.md"), []byte("---\nname: demo\ndescription: test\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := UploadSkills([]db.InstalledSkill{{Name: "demo", Path: local}}, upstream, nil, PROptions{Fork: true})
+	if err == nil || !strings.Contains(err.Error(), "pull_requests.forks") {
+		t.Fatalf("expected hint about configuring a fork, got %v", err)
+	}
+}
+
+func TestIsPushPermissionError(t *testing.T) {
+	denied := []string{
+		"remote: Permission to acme/skills.git denied to bob.\nfatal: unable to access",
+		"remote: You are not allowed to push code to this project.",
+		"fatal: could not read Username for 'https://github.com': terminal prompts disabled",
+		"ERROR: Permission denied (publickey).",
+	}
+	for _, msg := range denied {
+		if !isPushPermissionError(fmt.Errorf("exit status 128: %s", msg)) {
+			t.Fatalf("expected permission error for %q", msg)
+		}
+	}
+	if isPushPermissionError(fmt.Errorf("exit status 1: ! [rejected] main -> main (non-fast-forward)")) {
+		t.Fatalf("rejected push is not a permission error")
+	}
+}
+
+func TestBuildForkPRURL(t *testing.T) {
+	fork := forkTarget{url: "https://github.com/bob/skills.git", repoPath: "bob/skills"}
+	got := buildForkPRURL(forge.GitHub, "https://github.com/acme/skills.git", fork, "feat/x", "main", "t")
+	if got != "https://github.com/acme/skills/compare/main...bob:feat/x?expand=1" {
+		t.Fatalf("unexpected GitHub fork URL %q", got)
+	}
+}
+
+func TestUploadSkillsUsesTemplates(t *testing.T) {
+	setGitIdentity(t)
+	remote := initBareRemote(t, map[string]string{"skills/demo/SKILL.md": "---\nname: demo\ndescription: test\n---\nold\n"})
+
+	local := filepath.Join(t.TempDir(), "demo")
+	if err := os.MkdirAll(local, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(local, "SKILL.md"), []byte("---\nname: demo\ndescription: test\n---\nnew\nmore\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := PROptions{
+		Templates: Templates{
+			Branch:  "skills/{{ .Vars.ticket }}",
+			Commit:  "{{ .Name }}: {{ .Stats }} by {{ .User }}",
+			Signoff: true,
+		},
+		Vars: map[string]string{"ticket": "SK-12"},
+	}
+	result, err := UploadSkills([]db.InstalledSkill{{Name: "demo", Path: local}}, remote, nil, opts)
+	if err != nil {
+		t.Fatalf("UploadSkills: %v", err)
+	}
+	if result.Branch != "skills/SK-12" {
+		t.Fatalf("unexpected branch %q", result.Branch)
+	}
+	if stats := result.Skills[0].Stats; stats.Files != 1 || stats.Insertions != 2 || stats.Deletions != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	msg := gitOutput(t, remote, "log", "-1", "--format=%B", result.Branch)
+	if !strings.HasPrefix(msg, "demo: 1 file changed, +2 -1 by test") {
+		t.Fatalf("unexpected commit message %q", msg)
+	}
+	if !strings.Contains(msg, "Signed-off-by: test <test@skli>") {
+		t.Fatalf("commit is not signed off: %q", msg)
+	}
+}
+
+func TestRenderBranchNameRejectsInvalidNames(t *testing.T) {
+	data := TemplateData{Slug: "demo", Timestamp: "20260101-000000", Vars: map[string]string{}}
+
+	if name, err := renderBranchName("", data); err != nil || name != "feat/update-demo-20260101-000000" {
+		t.Fatalf("unexpected default branch %q (%v)", name, err)
+	}
+	if _, err := renderBranchName("skills/{{ .Name }} ..", data); err == nil {
+		t.Fatalf("expected invalid branch name error")
+	}
+	if _, err := renderBranchName("skills/{{ .Vars.ticket }}", data); err == nil {
+		t.Fatalf("expected error for missing variable")
+	}
+}
+
+func TestRenderPROverridesTakePrecedence(t *testing.T) {
+	data := TemplateData{Vars: map[string]string{}}.forPR([]UploadedSkill{
+		{Name: "a", Stats: DiffStats{Files: 1, Insertions: 3}},
+		{Name: "b", Stats: DiffStats{Files: 2, Deletions: 1}},
+	})
+
+	opts := PROptions{
+		Title:     "Render {{ .Values.image }} in charts",
+		Templates: Templates{Title: "from config", Body: "- [ ] {{ len .Skills }} skills reviewed ({{ .Stats }})\n"},
+	}
+	title, body, err := renderPR(opts, data)
+	if err != nil {
+		t.Fatalf("renderPR: %v", err)
+	}
+	if title != "Render {{ .Values.image }} in charts" {
+		t.Fatalf("overrides must be used literally, got %q", title)
+	}
+	if body != "- [ ] 2 skills reviewed (3 files changed, +3 -1)\n" {
+		t.Fatalf("unexpected body %q", body)
+	}
+
+	opts.Body = "```go\nfmt.Println(\"{{ .Missing }}\")\n```\n"
+	if _, body, err = renderPR(opts, data); err != nil || body != opts.Body {
+		t.Fatalf("a body file with braces must not fail, got %q (%v)", body, err)
+	}
+
+	defTitle, _, err := renderPR(PROptions{}, data)
+	if err != nil || defTitle == "" {
+		t.Fatalf("expected default title, got %q (%v)", defTitle, err)
+	}
+}
+
+func TestParseNumstat(t *testing.T) {
+	stats := parseNumstat("3\t1\tskills/a/SKILL.md\n-\t-\tskills/a/logo.png\n10\t0\tskills/a/ref.md\n")
+	if stats.Files != 3 || stats.Insertions != 13 || stats.Deletions != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestRepoLayoutSuggestsExistingLocations(t *testing.T) {
+	layout := RepoLayout{Skills: map[string]string{
+		".curated/pdf":        "pdf",
+		".curated/docx":       "docx",
+		"skills/devops/k8s":   "k8s",
+		"skills/devops/taken": "other",
+	}}
+
+	got := layout.Suggest("taken")
+	want := []string{".curated/taken", "skills/taken"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("Suggest = %v, want %v", got, want)
+	}
+
+	if got := (RepoLayout{Skills: map[string]string{}}).Suggest("demo"); len(got) != 1 || got[0] != "skills/demo" {
+		t.Fatalf("empty repo must default to skills/<folder>, got %v", got)
+	}
+}
+
+func TestRepoLayoutCheckDestination(t *testing.T) {
+	layout := RepoLayout{Skills: map[string]string{"skills/pdf": "pdf", "skills/docx": "docx"}}
+
+	cases := []struct {
+		name, dest string
+		ok         bool
+	}{
+		{"new", "skills/new", true},
+		{"pdf", "skills/pdf/", true},
+		{"pdf", "other/pdf", false},
+		{"new", "skills/docx", false},
+		{"new", "skills/docx/nested", false},
+		{"new", "skills", false},
+		{"new", "../outside", false},
+		{"new", "/abs/path", false},
+	}
+	for _, c := range cases {
+		err := layout.CheckDestination(c.name, c.dest)
+		if (err == nil) != c.ok {
+			t.Errorf("CheckDestination(%q, %q) = %v, want ok=%v", c.name, c.dest, err, c.ok)
+		}
+	}
+}
+
+func TestUploadSkillsHonoursDestination(t *testing.T) {
+	setGitIdentity(t)
+	remote := initBareRemote(t, map[string]string{
+		".curated/existing/SKILL.md": "---\nname: existing\n---\n",
+	})
+
+	local := t.TempDir()
+	newSkill := filepath.Join(local, "fresh")
+	if err := os.MkdirAll(newSkill, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(newSkill, "SKILL.md"), []byte("---\nname: fresh\ndescription: test\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := UploadSkills([]db.InstalledSkill{{Name: "fresh", Path: newSkill}}, remote, nil, PROptions{Templates: Templates{Branch: "suggested"}})
+	if err != nil {
+		t.Fatalf("UploadSkills: %v", err)
+	}
+	if result.Skills[0].RepoPath != ".curated/fresh" {
+		t.Fatalf("expected layout suggestion, got %s", result.Skills[0].RepoPath)
+	}
+
+	dests := map[string]string{"fresh": "catalog/tools/fresh"}
+	result, err = UploadSkills([]db.InstalledSkill{{Name: "fresh", Path: newSkill}}, remote, dests, PROptions{Templates: Templates{Branch: "explicit"}})
+	if err != nil {
+		t.Fatalf("UploadSkills with dest: %v", err)
+	}
+	if result.Skills[0].RepoPath != "catalog/tools/fresh" {
+		t.Fatalf("expected explicit destination, got %s", result.Skills[0].RepoPath)
+	}
+
+	dests = map[string]string{"fresh": ".curated/existing"}
+	if _, err := UploadSkills([]db.InstalledSkill{{Name: "fresh", Path: newSkill}}, remote, dests, PROptions{}); err == nil || !strings.Contains(err.Error(), "already contains skill 'existing'") {
+		t.Fatalf("expected collision error, got %v", err)
+	}
+}
+
+func TestPreviewUploadShowsDiffWithoutPushing(t *testing.T) {
+	setGitIdentity(t)
+	remote := initBareRemote(t, map[string]string{"skills/demo/SKILL.md": "---\nname: demo\ndescription: test\n---\nold\n"})
+
+	local := filepath.Join(t.TempDir(), "demo")
+	if err := os.MkdirAll(local, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(local, "SKILL.md"), []byte("---\nname: demo\ndescription: test\n---\nnew\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(local, ".DS_Store"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	preview, err := PreviewUpload([]db.InstalledSkill{{Name: "demo", Path: local}}, remote, nil, PROptions{})
+	if err != nil {
+		t.Fatalf("PreviewUpload: %v", err)
+	}
+	if preview.Base != "main" || !strings.Contains(preview.Diff, "-old") || !strings.Contains(preview.Diff, "+new") {
+		t.Fatalf("unexpected preview: base=%s diff=%q", preview.Base, preview.Diff)
+	}
+	if issues := preview.Issues["demo"]; len(issues) != 1 || issues[0].Rule != "editor-junk" {
+		t.Fatalf("expected junk issue in preview, got %+v", preview.Issues)
+	}
+	if got := gitOutput(t, remote, "branch", "--list", preview.Branch); got != "" {
+		t.Fatalf("dry run must not push %s", preview.Branch)
+	}
+
+	if _, err := UploadSkills([]db.InstalledSkill{{Name: "demo", Path: local}}, remote, nil, PROptions{}); err == nil || !strings.Contains(err.Error(), "failed validation") {
+		t.Fatalf("expected upload to be refused, got %v", err)
+	}
+}
+
+func TestCloneForPushOnlyChecksOutSkillFiles(t *testing.T) {
+	setGitIdentity(t)
+
+	remote := initBareRemote(t, map[string]string{
+		"skills/alpha/SKILL.md":  "---\nname: alpha\ndescription: test\n---\n",
+		"skills/alpha/guide.md":  "alpha guide\n",
+		"docs/large-asset.txt":   strings.Repeat("x", 4096),
+		"tools/beta/SKILL.md":    "---\nname: beta\ndescription: test\n---\n",
+		"tools/beta/scripts.txt": "beta\n",
+	})
+	// Los clones parciales necesitan que el servidor acepte filtros
+	gitOutput(t, remote, "config", "uploadpack.allowFilter", "true")
+
+	dir, err := CloneForPush("file://" + remote)
+	if err != nil {
+		t.Fatalf("CloneForPush: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if got := gitOutput(t, dir, "rev-parse", "--is-shallow-repository"); got != "true" {
+		t.Fatalf("expected a shallow clone, got %s", got)
+	}
+	if got := gitOutput(t, dir, "config", "remote.origin.partialclonefilter"); got != "blob:none" {
+		t.Fatalf("expected a blobless clone, got %s", got)
+	}
+	for _, rel := range []string{"skills/alpha/SKILL.md", "tools/beta/SKILL.md"} {
+		if _, err := os.Stat(filepath.Join(dir, rel)); err != nil {
+			t.Fatalf("%s must be checked out: %v", rel, err)
+		}
+	}
+	for _, rel := range []string{"skills/alpha/guide.md", "docs/large-asset.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, rel)); !os.IsNotExist(err) {
+			t.Fatalf("%s must not be checked out", rel)
+		}
+	}
+
+	layout, err := ScanRepoLayout(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, ok := layout.Find("beta"); !ok || p != "tools/beta" {
+		t.Fatalf("skill lookup must work on the sparse clone, got %q", p)
+	}
+
+	if err := CheckoutPaths(dir, "skills/alpha"); err != nil {
+		t.Fatalf("CheckoutPaths: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "skills", "alpha", "guide.md")); err != nil {
+		t.Fatalf("checked out folder must be complete: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "docs", "large-asset.txt")); !os.IsNotExist(err) {
+		t.Fatalf("other folders must stay out of the checkout")
+	}
+}
+
+func TestUploadSkillsFromPartialClone(t *testing.T) {
+	setGitIdentity(t)
+
+	remote := initBareRemote(t, map[string]string{
+		"skills/alpha/SKILL.md": "---\nname: alpha\ndescription: test\n---\nold\n",
+		"skills/alpha/old.md":   "to be removed\n",
+		"docs/readme.md":        "docs\n",
+	})
+	gitOutput(t, remote, "config", "uploadpack.allowFilter", "true")
+
+	local := filepath.Join(t.TempDir(), "alpha")
+	if err := os.MkdirAll(local, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(local, "SKILL.md"), []byte("---\nname: alpha\ndescription: test\n---\nnew\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := UploadSkills([]db.InstalledSkill{{Name: "alpha", Path: local}}, "file://"+remote, nil, PROptions{})
+	if err != nil {
+		t.Fatalf("UploadSkills: %v", err)
+	}
+
+	// La rama publicada conserva el resto del repo y solo cambia el skill
+	files := gitOutput(t, remote, "ls-tree", "-r", "--name-only", result.Branch)
+	if files != "docs/readme.md\nskills/alpha/SKILL.md" {
+		t.Fatalf("unexpected tree on the pushed branch:\n%s", files)
+	}
+}
+
+func TestUploadSkillsPartialCloneToStaleFork(t *testing.T) {
+	setGitIdentity(t)
+	upstream := initBareRemote(t, map[string]string{"README.md": "upstream\n"})
+	gitOutput(t, upstream, "config", "uploadpack.allowFilter", "true")
+	fork := filepath.Join(t.TempDir(), "fork.git")
+	gitOutput(t, filepath.Dir(fork), "clone", "-q", "--bare", upstream, fork)
+	// El upstream avanza y el fork se queda sin el commit base de la rama
+	pushRemoteChange(t, upstream, map[string]string{"README.md": "newer\n"})
+
+	local := filepath.Join(t.TempDir(), "demo")
+	if err := os.MkdirAll(local, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(local, "SKILL.md"), []byte("---\nname: demo\ndescription: test\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	prev := rememberForkFn
+	rememberForkFn = func(string, string) error { return nil }
+	t.Cleanup(func() { rememberForkFn = prev })
+
+	upstreamURL := "file://" + upstream
+	opts := PROptions{Forks: map[string]string{normalizeRepoWebURL(upstreamURL): fork}}
+	result, err := UploadSkills([]db.InstalledSkill{{Name: "demo", Path: local}}, upstreamURL, nil, opts)
+	if err != nil {
+		t.Fatalf("UploadSkills: %v", err)
+	}
+	if got := gitOutput(t, fork, "branch", "--list", result.Branch); got == "" {
+		t.Fatalf("branch %s missing in fork", result.Branch)
+	}
+}
+
+// stubClient simula la API del forge: crea la PR en url y falla después con err
+type stubClient struct {
+	url string
+	err error
+}
+
+func (c stubClient) CreatePullRequest(context.Context, string, forge.PullRequest) (string, error) {
+	return c.url, c.err
+}
+
+func TestCreatePRKeepsURLWhenLabelsFail(t *testing.T) {
+	labelsErr := fmt.Errorf("pull request https://github.com/acme/skills/pull/7 created but labels could not be set: 422")
+	url, warning, err := createPR(t.TempDir(), "https://github.com/acme/skills", "feat/x", "t", "b", PROptions{Labels: []string{"skills"}},
+		stubClient{url: "https://github.com/acme/skills/pull/7", err: labelsErr}, forkTarget{})
+	if err != nil || url != "https://github.com/acme/skills/pull/7" || !strings.Contains(warning, "labels") {
+		t.Fatalf("a created PR must be returned with a warning, got %q %q %v", url, warning, err)
+	}
+
+	_, _, err = createPR(t.TempDir(), "https://github.com/acme/skills", "feat/x", "t", "b", PROptions{},
+		stubClient{err: fmt.Errorf("401")}, forkTarget{})
+	if err == nil || !strings.Contains(err.Error(), "open the PR at") {
+		t.Fatalf("a failed PR must fail with the manual URL, got %v", err)
+	}
+}
+
+func TestInstalledSymlinksMatchTheSourceManifest(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "demo")
+	if err := os.MkdirAll(filepath.Join(src, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "docs", "guide.md"), []byte("guide"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("docs", "guide.md"), filepath.Join(src, "README.md")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "demo")
+	if err := copyDir(src, dst); err != nil {
+		t.Fatalf("copyDir: %v", err)
+	}
+	if info, err := os.Lstat(filepath.Join(dst, "README.md")); err != nil || !info.Mode().IsRegular() {
+		t.Fatalf("install must copy the link as a regular file: %v %v", info, err)
+	}
+
+	fromSource, err := integrity.Compute(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	installed, err := integrity.Compute(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fromSource, installed) {
+		t.Fatalf("a clean install must match the manifest of its source:\n%v\n%v", fromSource, installed)
+	}
+}
@@ -0,0 +1,96 @@
+package gitrepo
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"skli/internal/forge"
+)
+
+var (
+	hostsMu       sync.RWMutex
+	hostProviders map[string]forge.Provider
+)
+
+// builtinHosts son los hosts públicos conocidos de cada provider
+var builtinHosts = map[string]forge.Provider{
+	"github.com":        forge.GitHub,
+	"gitlab.com":        forge.GitLab,
+	"bitbucket.org":     forge.Bitbucket,
+	"gitea.com":         forge.Gitea,
+	"codeberg.org":      forge.Gitea,
+	"dev.azure.com":     forge.Azure,
+	"ssh.dev.azure.com": forge.Azure,
+}
+
+// SetHostProviders registra el provider de hosts propios (ej: "git.acme.io" = "gitlab").
+// Sustituye los registrados antes; los nombres válidos son los de forge.ParseProvider.
+func SetHostProviders(hosts map[string]string) error {
+	parsed := make(map[string]forge.Provider, len(hosts))
+	for host, name := range hosts {
+		provider, err := forge.ParseProvider(name)
+		if err != nil {
+			return fmt.Errorf("host %s: %w", host, err)
+		}
+		parsed[strings.ToLower(strings.TrimSpace(host))] = provider
+	}
+
+	hostsMu.Lock()
+	hostProviders = parsed
+	hostsMu.Unlock()
+	return nil
+}
+
+// detectProvider identifica el provider por el host de la URL: primero los hosts de la config y
+// después los públicos conocidos. Los demás son forge.Unknown: comparar por subcadena clasificaría
+// hosts como github.com.evil.io como GitHub y les enviaría su token.
+func detectProvider(repoURL string) forge.Provider {
+	provider, _ := hostProvider(urlHost(repoURL))
+	return provider
+}
+
+// knownHost indica si el host de la URL es un host público conocido o uno configurado en [hosts].
+// Solo a esos se envían los tokens del entorno (GITHUB_TOKEN...).
+func knownHost(repoURL string) bool {
+	_, ok := hostProvider(urlHost(repoURL))
+	return ok
+}
+
+func hostProvider(host string) (forge.Provider, bool) {
+	hostsMu.RLock()
+	provider, ok := hostProviders[host]
+	hostsMu.RUnlock()
+	if ok {
+		return provider, true
+	}
+
+	if provider, ok := builtinHosts[host]; ok {
+		return provider, true
+	}
+	if strings.HasSuffix(host, ".visualstudio.com") {
+		return forge.Azure, true
+	}
+	return forge.Unknown, false
+}
+
+// urlHost devuelve el host (sin puerto ni usuario) de una URL HTTPS, ssh:// o scp (git@host:path)
+func urlHost(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return ""
+		}
+		return strings.ToLower(u.Hostname())
+	}
+
+	if at := strings.Index(raw, "@"); at >= 0 {
+		rest := raw[at+1:]
+		if colon := strings.Index(rest, ":"); colon >= 0 {
+			return strings.ToLower(rest[:colon])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package gitrepo
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"skli/internal/skillmeta"
+)
+
+// RepoLayout son los skills que ya existen en el repo destino (ruta con "/" → nombre)
+type RepoLayout struct {
+	Skills map[string]string
+}
+
+// ScanRepoLayout recorre el repo clonado y recoge la ruta y el nombre de cada SKILL.md
+func ScanRepoLayout(repoDir string) (RepoLayout, error) {
+	layout := RepoLayout{Skills: make(map[string]string)}
+	err := filepath.WalkDir(repoDir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || d.Name() != "SKILL.md" {
+			return nil
+		}
+		meta, err := skillmeta.ParseFile(p)
+		if err != nil || meta.Name == "" {
+			return nil
+		}
+		rel, err := filepath.Rel(repoDir, filepath.Dir(p))
+		if err != nil {
+			return nil
+		}
+		layout.Skills[filepath.ToSlash(rel)] = meta.Name
+		return nil
+	})
+	if err != nil {
+		return RepoLayout{}, fmt.Errorf("error scanning repository: %w", err)
+	}
+	return layout, nil
+}
+
+// InspectRepoLayout clona el repo destino como CloneForPush (solo los SKILL.md) y devuelve su layout
+func InspectRepoLayout(remoteURL string) (RepoLayout, error) {
+	tempDir, err := CloneForPush(remoteURL)
+	if err != nil {
+		return RepoLayout{}, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	return ScanRepoLayout(tempDir)
+}
+
+// Find devuelve la ruta del skill con ese nombre si ya existe en el repo
+func (l RepoLayout) Find(name string) (string, bool) {
+	paths := make([]string, 0, 1)
+	for p, n := range l.Skills {
+		if n == name {
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) == 0 {
+		return "", false
+	}
+	sort.Strings(paths)
+	return paths[0], true
+}
+
+// Suggest propone rutas para un skill nuevo a partir de dónde están los existentes:
+// primero las carpetas con más skills (ej: ".curated", "skills/devops"), y "skills/<folder>" al final.
+func (l RepoLayout) Suggest(folder string) []string {
+	counts := make(map[string]int)
+	for p := range l.Skills {
+		counts[path.Dir(p)]++
+	}
+	parents := make([]string, 0, len(counts))
+	for parent := range counts {
+		parents = append(parents, parent)
+	}
+	sort.Slice(parents, func(i, j int) bool {
+		if counts[parents[i]] != counts[parents[j]] {
+			return counts[parents[i]] > counts[parents[j]]
+		}
+		return parents[i] < parents[j]
+	})
+
+	var suggestions []string
+	seen := make(map[string]bool)
+	add := func(candidate string) {
+		if seen[candidate] || l.CheckDestination("", candidate) != nil {
+			return
+		}
+		seen[candidate] = true
+		suggestions = append(suggestions, candidate)
+	}
+	for _, parent := range parents {
+		add(path.Join(parent, folder))
+	}
+	add(path.Join(DefaultSkillsPath, folder))
+	return suggestions
+}
+
+// CheckDestination comprueba que name puede subirse a dest sin pisar otro skill:
+// un skill que ya existe solo puede ir a su ruta, y dest no puede contener ni estar dentro de otro skill.
+func (l RepoLayout) CheckDestination(name, dest string) error {
+	dest, err := CleanDestination(dest)
+	if err != nil {
+		return err
+	}
+	if existing, ok := l.Find(name); ok && existing != dest {
+		return fmt.Errorf("skill '%s' already exists at %s", name, existing)
+	}
+	for p, other := range l.Skills {
+		if other == name && p == dest {
+			continue
+		}
+		switch {
+		case p == dest:
+			return fmt.Errorf("%s already contains skill '%s'", dest, other)
+		case strings.HasPrefix(dest, p+"/"):
+			return fmt.Errorf("%s is inside skill '%s' (%s)", dest, other, p)
+		case strings.HasPrefix(p, dest+"/"):
+			return fmt.Errorf("%s contains skill '%s' (%s)", dest, other, p)
+		}
+	}
+	return nil
+}
+
+// CleanDestination normaliza una ruta de destino y rechaza las que salen del repo
+func CleanDestination(dest string) (string, error) {
+	cleaned := path.Clean(filepath.ToSlash(strings.TrimSpace(dest)))
+	if path.IsAbs(cleaned) || filepath.IsAbs(dest) || cleaned == "." || cleaned == ".." ||
+		strings.HasPrefix(cleaned, "../") || cleaned == ".git" || strings.HasPrefix(cleaned, ".git/") {
+		return "", fmt.Errorf("invalid destination path %q", dest)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,53 @@
+package gitrepo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MergeDriver describe un merge driver de git
+type MergeDriver struct {
+	ID      string // Nombre usado en .gitattributes (merge=<ID>)
+	Name    string // Descripción legible
+	Command string // Comando con los placeholders %O %A %B de git
+	Pattern string // Patrón de .gitattributes al que se aplica
+}
+
+// RegisterMergeDriver configura el driver en la config local del repo y lo asocia al patrón en .gitattributes.
+// Es idempotente: no duplica la línea si ya existe.
+func RegisterMergeDriver(repoDir string, driver MergeDriver) error {
+	if err := runGit(repoDir, "rev-parse", "--git-dir"); err != nil {
+		return fmt.Errorf("%s is not a git repository", repoDir)
+	}
+	if err := runGit(repoDir, "config", "merge."+driver.ID+".name", driver.Name); err != nil {
+		return fmt.Errorf("error configuring merge driver: %w", err)
+	}
+	if err := runGit(repoDir, "config", "merge."+driver.ID+".driver", driver.Command); err != nil {
+		return fmt.Errorf("error configuring merge driver: %w", err)
+	}
+
+	attrPath := filepath.Join(repoDir, ".gitattributes")
+	line := driver.Pattern + " merge=" + driver.ID
+
+	content, err := os.ReadFile(attrPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error reading .gitattributes: %w", err)
+	}
+	for _, existing := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(existing) == line {
+			return nil
+		}
+	}
+
+	text := string(content)
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	text += line + "\n"
+	if err := os.WriteFile(attrPath, []byte(text), 0644); err != nil {
+		return fmt.Errorf("error writing .gitattributes: %w", err)
+	}
+	return nil
+}
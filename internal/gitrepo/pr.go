@@ -1,17 +1,53 @@
 package gitrepo
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
-	"time"
 
+	"skli/internal/config"
 	"skli/internal/db"
+	"skli/internal/forge"
+	"skli/internal/project"
+	"skli/internal/validate"
 )
 
+// PROptions configura la PR/MR creada tras el push
+type PROptions struct {
+	Labels    []string
+	Reviewers []string
+	Draft     bool
+	Tokens    map[string]string // Token de API por host
+	Fork      bool              // Publicar siempre la rama en un fork
+	Forks     map[string]string // Fork personal por repo original (URL web → remote del fork)
+	Templates Templates
+	Title     string            // Título literal para esta subida (--title)
+	Body      string            // Cuerpo literal para esta subida (--body-file)
+	Vars      map[string]string // Variables extra para las plantillas (--var clave=valor)
+}
+
+// NewPROptions construye las opciones a partir de la configuración del usuario
+func NewPROptions(cfg config.PullRequestConfig) PROptions {
+	return PROptions{
+		Labels:    cfg.Labels,
+		Reviewers: cfg.Reviewers,
+		Draft:     cfg.Draft,
+		Tokens:    cfg.Tokens,
+		Forks:     cfg.Forks,
+		Templates: Templates{
+			Branch:  cfg.Branch,
+			Commit:  cfg.Commit,
+			Title:   cfg.Title,
+			Body:    cfg.Body,
+			Signoff: cfg.Signoff,
+		},
+	}
+}
+
 // CheckGhInstalled verifica si la herramienta CLI 'gh' está instalada
 func CheckGhInstalled() bool {
 	_, err := exec.LookPath("gh")
@@ -23,39 +59,72 @@ func checkGlabInstalled() bool {
 	return err == nil
 }
 
-// CloneForPush clona el repositorio completo para realizar cambios y push
+// CloneForPush clona el repositorio para hacer cambios y push sin descargar el árbol entero:
+// clon parcial sin blobs (--filter=blob:none) con depth 1, como CloneAndScan, y sparse-checkout
+// que solo materializa los SKILL.md. Las carpetas a modificar se añaden con CheckoutPaths.
 func CloneForPush(remoteURL string) (string, error) {
+	return clonePartial(remoteURL, "--depth", "1")
+}
+
+// skillFilePattern es el patrón de sparse-checkout (sin cono) que selecciona los SKILL.md a cualquier profundidad
+const skillFilePattern = "SKILL.md"
+
+// clonePartial hace un clon sin blobs que solo tiene en disco los SKILL.md.
+// Los blobs del resto de ficheros se descargan al añadir su carpeta con CheckoutPaths.
+func clonePartial(remoteURL string, extraArgs ...string) (string, error) {
 	tempDir, err := os.MkdirTemp("", "skli-pr-*")
 	if err != nil {
 		return "", fmt.Errorf("error creating temp dir: %w", err)
 	}
 
-	if err := runGit(tempDir, "clone", remoteURL, "."); err != nil {
+	args := append([]string{"clone", "--filter=blob:none", "--sparse"}, extraArgs...)
+	args = append(args, remoteURL, ".")
+	if err := runGit(tempDir, args...); err != nil {
 		os.RemoveAll(tempDir)
 		return "", fmt.Errorf("error cloning repo: %w", err)
 	}
+	if err := runGit(tempDir, "sparse-checkout", "set", "--no-cone", skillFilePattern); err != nil {
+		os.RemoveAll(tempDir)
+		return "", fmt.Errorf("error configuring sparse-checkout: %w", err)
+	}
 
 	return tempDir, nil
 }
 
-// PrepareSkillBranch crea una rama para el skill y devuelve el nombre de la rama
-func PrepareSkillBranch(repoDir, skillName string) (string, error) {
-	timestamp := time.Now().Format("20060102-150405")
-	sanitizedName := strings.ReplaceAll(strings.ToLower(skillName), " ", "-")
-	sanitizedName = strings.Map(func(r rune) rune {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
-			return r
-		}
-		return -1
-	}, sanitizedName)
+// CheckoutPaths añade al sparse-checkout las carpetas indicadas (relativas a la raíz del repo)
+// para poder modificarlas; git descarga en un solo lote los blobs que faltan.
+func CheckoutPaths(repoDir string, paths ...string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	args := []string{"sparse-checkout", "add"}
+	for _, p := range paths {
+		args = append(args, "/"+escapeSparsePattern(strings.Trim(filepath.ToSlash(p), "/"))+"/")
+	}
+	if err := runGit(repoDir, args...); err != nil {
+		return fmt.Errorf("error checking out %s: %w", strings.Join(paths, ", "), err)
+	}
+	return nil
+}
 
-	branchName := fmt.Sprintf("feat/update-%s-%s", sanitizedName, timestamp)
+// escapeSparsePattern escapa los caracteres especiales de los patrones de gitignore
+func escapeSparsePattern(p string) string {
+	var b strings.Builder
+	for i, r := range p {
+		if strings.ContainsRune(`\*?[`, r) || (i == 0 && (r == '!' || r == '#')) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
 
+// PrepareSkillBranch crea la rama branchName a partir del commit actual
+func PrepareSkillBranch(repoDir, branchName string) error {
 	if err := runGit(repoDir, "checkout", "-b", branchName); err != nil {
-		return "", fmt.Errorf("error creating branch %s: %w", branchName, err)
+		return fmt.Errorf("error creating branch %s: %w", branchName, err)
 	}
-
-	return branchName, nil
+	return nil
 }
 
 // FindSkillInRepo busca la ruta relativa del skill dentro del repositorio clonado.
@@ -100,29 +169,6 @@ func CopySkillFiles(repoDir, localSkillPath, repoSkillPath string) error {
 	return nil
 }
 
-type gitProvider int
-
-const (
-	providerUnknown gitProvider = iota
-	providerGitHub
-	providerGitLab
-	providerBitbucket
-)
-
-func detectProvider(repoURL string) gitProvider {
-	u := strings.ToLower(repoURL)
-	switch {
-	case strings.Contains(u, "github.com"):
-		return providerGitHub
-	case strings.Contains(u, "gitlab.com"):
-		return providerGitLab
-	case strings.Contains(u, "bitbucket.org"):
-		return providerBitbucket
-	default:
-		return providerUnknown
-	}
-}
-
 func normalizeRepoWebURL(remoteURL string) string {
 	base := ParseGitURL(remoteURL).BaseURL
 	base = strings.TrimSpace(base)
@@ -130,13 +176,21 @@ func normalizeRepoWebURL(remoteURL string) string {
 	if strings.HasPrefix(base, "git@") {
 		parts := strings.SplitN(strings.TrimPrefix(base, "git@"), ":", 2)
 		if len(parts) == 2 {
+			// Azure DevOps: git@ssh.dev.azure.com:v3/org/proyecto/repo
+			if parts[0] == "ssh.dev.azure.com" {
+				segments := strings.Split(strings.TrimPrefix(parts[1], "v3/"), "/")
+				if len(segments) == 3 {
+					return fmt.Sprintf("https://dev.azure.com/%s/%s/_git/%s", segments[0], segments[1], segments[2])
+				}
+			}
 			return "https://" + parts[0] + "/" + strings.TrimSuffix(strings.Trim(parts[1], "/"), ".git")
 		}
 	}
 
+	// El puerto de una URL ssh:// es el del servidor SSH, no el de la web
 	if strings.HasPrefix(base, "ssh://") {
 		if u, err := url.Parse(base); err == nil {
-			return "https://" + u.Host + strings.TrimSuffix(u.Path, ".git")
+			return "https://" + u.Hostname() + strings.TrimSuffix(u.Path, ".git")
 		}
 	}
 
@@ -145,11 +199,17 @@ func normalizeRepoWebURL(remoteURL string) string {
 		if scheme == "" {
 			scheme = "https"
 		}
+		host := u.Host
 		path := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+		// Azure DevOps antiguo: https://org.visualstudio.com/proyecto/_git/repo
+		if org, ok := strings.CutSuffix(u.Hostname(), ".visualstudio.com"); ok {
+			host = "dev.azure.com"
+			path = org + "/" + strings.TrimPrefix(path, "DefaultCollection/")
+		}
 		if path != "" {
 			path = "/" + path
 		}
-		return scheme + "://" + u.Host + path
+		return scheme + "://" + host + path
 	}
 
 	return strings.TrimSuffix(strings.Trim(base, "/"), ".git")
@@ -172,102 +232,436 @@ func getDefaultBranch(repoDir string) string {
 	return "main"
 }
 
-func buildPRURL(provider gitProvider, repoURL, branchName, targetBranch, title string) string {
+func buildPRURL(provider forge.Provider, repoURL, branchName, targetBranch, title string) string {
 	repoWeb := normalizeRepoWebURL(repoURL)
 
 	switch provider {
-	case providerGitHub:
+	case forge.GitHub:
 		return fmt.Sprintf("%s/compare/%s...%s?expand=1", repoWeb, targetBranch, branchName)
-	case providerGitLab:
+	case forge.GitLab:
 		q := url.Values{}
 		q.Set("merge_request[source_branch]", branchName)
 		q.Set("merge_request[target_branch]", targetBranch)
 		q.Set("merge_request[title]", title)
 		return fmt.Sprintf("%s/-/merge_requests/new?%s", repoWeb, q.Encode())
-	case providerBitbucket:
+	case forge.Bitbucket:
 		q := url.Values{}
 		q.Set("source", branchName)
 		q.Set("dest", targetBranch)
 		return fmt.Sprintf("%s/pull-requests/new?%s", repoWeb, q.Encode())
+	case forge.Gitea:
+		return fmt.Sprintf("%s/compare/%s...%s", repoWeb, url.PathEscape(targetBranch), url.PathEscape(branchName))
+	case forge.Azure:
+		q := url.Values{}
+		q.Set("sourceRef", branchName)
+		q.Set("targetRef", targetBranch)
+		return fmt.Sprintf("%s/pullrequestcreate?%s", repoWeb, q.Encode())
 	default:
 		return repoWeb
 	}
 }
 
-// PushAndCreatePR hace commit, push y crea PR/MR cuando es posible.
-func PushAndCreatePR(repoDir, remoteURL, branchName, skillName, description string) (string, error) {
-	if err := runGit(repoDir, "add", "."); err != nil {
-		return "", fmt.Errorf("git add failed: %w", err)
+// PRInfo es el resultado de publicar la rama y abrir la PR/MR
+type PRInfo struct {
+	URL     string
+	Fork    string // Remote del fork al que se hizo push (vacío si fue al repo original)
+	Warning string // La PR existe pero no se pudieron aplicar labels o reviewers
+}
+
+// PushAndCreatePR hace push de la rama y crea PR/MR cuando es posible.
+// Si no hay permiso de push, se pide un fork o hay uno configurado, publica la rama en el fork
+// y abre una PR entre repos. Con token usa la API REST del provider; sin token prueba gh/glab
+// y, si no, devuelve la URL para crearla a mano.
+func PushAndCreatePR(repoDir, remoteURL, branchName, title, body string, opts PROptions) (PRInfo, error) {
+	client := newForgeClient(remoteURL, opts)
+
+	upstream := normalizeRepoWebURL(remoteURL)
+	forkURL := opts.Forks[upstream]
+	if !opts.Fork && forkURL == "" {
+		err := pushBranch(repoDir, "origin", branchName)
+		if err == nil {
+			url, warning, err := createPR(repoDir, remoteURL, branchName, title, body, opts, client, forkTarget{})
+			return PRInfo{URL: url, Warning: warning}, err
+		}
+		if !isPushPermissionError(err) {
+			return PRInfo{}, fmt.Errorf("git push failed: %w", err)
+		}
 	}
 
-	if err := runGit(repoDir, "diff", "--staged", "--quiet"); err == nil {
-		return "", fmt.Errorf("no changes to upload (local content is identical to remote)")
+	fork, err := pushToFork(repoDir, remoteURL, branchName, forkURL, client)
+	if err != nil {
+		return PRInfo{}, err
 	}
+	url, warning, err := createPR(repoDir, remoteURL, branchName, title, body, opts, client, fork)
+	return PRInfo{URL: url, Fork: fork.url, Warning: warning}, err
+}
 
-	msg := fmt.Sprintf("feat(%s): update skill content", skillName)
-	if err := runGit(repoDir, "commit", "-m", msg); err != nil {
-		return "", fmt.Errorf("git commit failed: %w", err)
+// newForgeClient crea el cliente de la API del provider si hay token para el host.
+// Token y provider se buscan por el nombre del host sin puerto (como en [hosts] y [pull_requests.tokens]);
+// la API usa el host de la web, que solo conserva el puerto de las URLs HTTPS.
+func newForgeClient(remoteURL string, opts PROptions) forge.Client {
+	provider := detectProvider(remoteURL)
+	token := forge.Token(provider, urlHost(remoteURL), opts.Tokens, knownHost(remoteURL))
+	if token == "" {
+		return nil
 	}
+	host, _ := repoHostAndPath(remoteURL)
+	return forge.NewClient(provider, host, token)
+}
 
-	if err := runGit(repoDir, "push", "origin", branchName); err != nil {
-		return "", fmt.Errorf("git push failed: %w", err)
+// pushBranch publica la rama. El clon de CloneForPush es superficial: si el remoto lo rechaza
+// porque no tiene el commit base (un fork desactualizado), se completa la historia y se reintenta.
+func pushBranch(repoDir, remote, branchName string) error {
+	err := runGit(repoDir, "push", remote, branchName)
+	if err == nil || !strings.Contains(err.Error(), "shallow") {
+		return err
+	}
+	if fetchErr := runGit(repoDir, "fetch", "-q", "--unshallow", "origin"); fetchErr != nil {
+		return err
 	}
+	return runGit(repoDir, "push", remote, branchName)
+}
 
-	title := fmt.Sprintf("Update skill: %s", skillName)
-	body := fmt.Sprintf("This PR updates the skill '%s'.\n\nAutomatically generated by skli.\n\n%s", skillName, description)
+// createPR abre la PR de branchName contra la rama por defecto del repo original.
+// Si la PR se crea pero fallan labels o reviewers devuelve su URL con el error como aviso:
+// fallar aquí haría que el usuario reintentara y abriera una PR duplicada.
+func createPR(repoDir, remoteURL, branchName, title, body string, opts PROptions, client forge.Client, fork forkTarget) (string, string, error) {
 	targetBranch := getDefaultBranch(repoDir)
 	provider := detectProvider(remoteURL)
+	_, repoPath := repoHostAndPath(remoteURL)
+
 	fallbackURL := buildPRURL(provider, remoteURL, branchName, targetBranch, title)
+	if fork.url != "" {
+		fallbackURL = buildForkPRURL(provider, remoteURL, fork, branchName, targetBranch, title)
+	}
+
+	if client != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), forge.DefaultTimeout)
+		defer cancel()
+		prURL, err := client.CreatePullRequest(ctx, repoPath, forge.PullRequest{
+			Title:     title,
+			Body:      body,
+			Head:      branchName,
+			HeadRepo:  fork.repoPath,
+			Base:      targetBranch,
+			Labels:    opts.Labels,
+			Reviewers: opts.Reviewers,
+			Draft:     opts.Draft,
+		})
+		if err != nil {
+			if prURL != "" {
+				return prURL, err.Error(), nil
+			}
+			return "", "", fmt.Errorf("%w (branch %s was pushed, open the PR at %s)", err, branchName, fallbackURL)
+		}
+		return prURL, "", nil
+	}
+
+	// gh y glab solo se usan para PRs dentro del mismo repo
+	if fork.url != "" {
+		return fallbackURL, "", nil
+	}
 
 	switch provider {
-	case providerGitHub:
+	case forge.GitHub:
 		if CheckGhInstalled() {
-			cmd := exec.Command("gh", "pr", "create", "--title", title, "--body", body, "--head", branchName, "--base", targetBranch)
+			args := []string{"pr", "create", "--title", title, "--body", body, "--head", branchName, "--base", targetBranch}
+			args = append(args, cliPROptions(opts)...)
+			cmd := exec.Command("gh", args...)
 			cmd.Dir = repoDir
 			output, err := cmd.CombinedOutput()
 			if err == nil {
-				return strings.TrimSpace(string(output)), nil
+				return strings.TrimSpace(string(output)), "", nil
 			}
 		}
-	case providerGitLab:
+	case forge.GitLab:
 		if checkGlabInstalled() {
-			cmd := exec.Command("glab", "mr", "create", "--title", title, "--description", body, "--source-branch", branchName, "--target-branch", targetBranch, "--yes")
+			args := []string{"mr", "create", "--title", title, "--description", body, "--source-branch", branchName, "--target-branch", targetBranch, "--yes"}
+			args = append(args, cliPROptions(opts)...)
+			cmd := exec.Command("glab", args...)
 			cmd.Dir = repoDir
 			output, err := cmd.CombinedOutput()
 			if err == nil {
-				return strings.TrimSpace(string(output)), nil
+				return strings.TrimSpace(string(output)), "", nil
 			}
 		}
 	}
 
-	return fallbackURL, nil
+	return fallbackURL, "", nil
+}
+
+// cliPROptions traduce las opciones a flags comunes de gh y glab
+func cliPROptions(opts PROptions) []string {
+	var args []string
+	for _, label := range opts.Labels {
+		args = append(args, "--label", label)
+	}
+	for _, reviewer := range opts.Reviewers {
+		args = append(args, "--reviewer", reviewer)
+	}
+	if opts.Draft {
+		args = append(args, "--draft")
+	}
+	return args
+}
+
+// repoHostAndPath separa la URL web del repo en host y ruta (ej: "github.com", "owner/repo")
+func repoHostAndPath(remoteURL string) (string, string) {
+	u, err := url.Parse(normalizeRepoWebURL(remoteURL))
+	if err != nil {
+		return "", ""
+	}
+	return u.Host, strings.Trim(u.Path, "/")
+}
+
+// UploadedSkill es un skill incluido en una PR con su ruta dentro del repo destino
+type UploadedSkill struct {
+	Name        string
+	Description string
+	RepoPath    string
+	Stats       DiffStats
+	TreeHash    string // Hash del árbol subido, para reconocerlo cuando llegue a la rama por defecto
+}
+
+// UploadResult describe el resultado de subir un lote de skills
+type UploadResult struct {
+	PRURL     string
+	Fork      string // Remote del fork si la rama se publicó en un fork
+	Branch    string
+	Skills    []UploadedSkill // Skills con cambios, un commit por skill
+	Unchanged []string        // Skills idénticos al remoto
+	Warnings  []string        // Avisos de la validación previa ("skill: warning: ...") y de la PR
+}
+
+// UploadPreview es lo que subiría UploadSkills, sin publicar nada (--dry-run)
+type UploadPreview struct {
+	Base      string // Rama del repo destino contra la que se compara
+	Branch    string
+	Title     string
+	Body      string
+	Skills    []UploadedSkill
+	Unchanged []string
+	Issues    map[string][]validate.Issue // Resultado de la validación por skill
+	Diff      string
+}
+
+// validateSkills ejecuta la validación obligatoria antes de subir; los errores abortan la subida
+func validateSkills(skills []db.InstalledSkill) ([]string, error) {
+	var warnings []string
+	for _, skill := range skills {
+		issues, err := validate.Check(skill.Name, project.Resolve(skill.Path))
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", skill.Name, issue))
+		}
+	}
+	return warnings, nil
 }
 
-// UploadSkill sube un skill local a un repositorio remoto y crea una PR/MR (o devuelve URL fallback).
-func UploadSkill(skill db.InstalledSkill, targetRemoteURL string) (string, error) {
+// UploadSkills sube varios skills locales a un repositorio remoto en una sola rama,
+// con un commit por skill, y crea una única PR/MR (o devuelve URL fallback).
+// dests fija la ruta en el repo de algunos skills (nombre → ruta); el resto se actualiza
+// donde ya esté o va a la ubicación que propone el layout del repo.
+func UploadSkills(skills []db.InstalledSkill, targetRemoteURL string, dests map[string]string, opts PROptions) (UploadResult, error) {
+	if len(skills) == 0 {
+		return UploadResult{}, fmt.Errorf("no skills to upload")
+	}
+	warnings, err := validateSkills(skills)
+	if err != nil {
+		return UploadResult{}, err
+	}
+
 	tempDir, err := CloneForPush(targetRemoteURL)
 	if err != nil {
-		return "", err
+		return UploadResult{}, err
 	}
 	defer os.RemoveAll(tempDir)
 
-	branchName, err := PrepareSkillBranch(tempDir, skill.Name)
+	result, data, err := commitSkills(tempDir, targetRemoteURL, skills, dests, opts)
 	if err != nil {
-		return "", err
+		return UploadResult{}, err
+	}
+	result.Warnings = warnings
+
+	title, body, err := renderPR(opts, data.forPR(result.Skills))
+	if err != nil {
+		return UploadResult{}, err
 	}
+	pr, err := PushAndCreatePR(tempDir, targetRemoteURL, result.Branch, title, body, opts)
+	if err != nil {
+		return UploadResult{}, err
+	}
+	result.PRURL, result.Fork = pr.URL, pr.Fork
+	if pr.Warning != "" {
+		result.Warnings = append(result.Warnings, pr.Warning)
+	}
+	return result, nil
+}
 
-	repoSkillPath := skill.RemotePath
-	foundPath, err := FindSkillInRepo(tempDir, skill.Name)
-	if err == nil {
-		repoSkillPath = foundPath
-	} else {
-		skillFolderName := filepath.Base(skill.Path)
-		repoSkillPath = filepath.Join("skills", skillFolderName)
+// PreviewUpload prepara la subida en un clon temporal igual que UploadSkills y devuelve
+// el diff contra la rama por defecto y el resultado de la validación, sin hacer push.
+func PreviewUpload(skills []db.InstalledSkill, targetRemoteURL string, dests map[string]string, opts PROptions) (UploadPreview, error) {
+	if len(skills) == 0 {
+		return UploadPreview{}, fmt.Errorf("no skills to upload")
+	}
+	preview := UploadPreview{Issues: make(map[string][]validate.Issue)}
+	for _, skill := range skills {
+		issues, err := validate.Skill(project.Resolve(skill.Path))
+		if err != nil {
+			return UploadPreview{}, err
+		}
+		if len(issues) > 0 {
+			preview.Issues[skill.Name] = issues
+		}
 	}
 
-	if err := CopySkillFiles(tempDir, skill.Path, repoSkillPath); err != nil {
-		return "", err
+	tempDir, err := CloneForPush(targetRemoteURL)
+	if err != nil {
+		return UploadPreview{}, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	preview.Base = getDefaultBranch(tempDir)
+	result, data, err := commitSkills(tempDir, targetRemoteURL, skills, dests, opts)
+	if err != nil {
+		return UploadPreview{}, err
+	}
+	preview.Branch, preview.Skills, preview.Unchanged = result.Branch, result.Skills, result.Unchanged
+
+	if preview.Title, preview.Body, err = renderPR(opts, data.forPR(result.Skills)); err != nil {
+		return UploadPreview{}, err
+	}
+
+	cmd := exec.Command("git", "diff", "origin/"+preview.Base+"..HEAD")
+	cmd.Dir = tempDir
+	output, err := cmd.Output()
+	if err != nil {
+		return UploadPreview{}, fmt.Errorf("git diff failed: %w", err)
+	}
+	preview.Diff = string(output)
+	return preview, nil
+}
+
+// commitSkills crea la rama en el clon y hace un commit por skill con cambios
+func commitSkills(tempDir, targetRemoteURL string, skills []db.InstalledSkill, dests map[string]string, opts PROptions) (UploadResult, TemplateData, error) {
+	layout, err := ScanRepoLayout(tempDir)
+	if err != nil {
+		return UploadResult{}, TemplateData{}, err
+	}
+
+	branchLabel := skills[0].Name
+	if len(skills) > 1 {
+		branchLabel = fmt.Sprintf("%d-skills", len(skills))
+	}
+	names := make([]string, 0, len(skills))
+	for _, skill := range skills {
+		names = append(names, skill.Name)
+	}
+	data := newTemplateData(tempDir, targetRemoteURL, branchLabel, names, opts.Vars)
+
+	branchName, err := renderBranchName(opts.Templates.Branch, data)
+	if err != nil {
+		return UploadResult{}, data, err
+	}
+	if err := PrepareSkillBranch(tempDir, branchName); err != nil {
+		return UploadResult{}, data, err
+	}
+
+	result := UploadResult{Branch: branchName}
+	usedPaths := make(map[string]string, len(skills))
+	for _, skill := range skills {
+		repoSkillPath, err := resolveRepoSkillPath(layout, skill, dests[skill.Name])
+		if err != nil {
+			return UploadResult{}, data, err
+		}
+		if other, ok := usedPaths[repoSkillPath]; ok {
+			return UploadResult{}, data, fmt.Errorf("skills '%s' and '%s' would both be uploaded to %s", other, skill.Name, repoSkillPath)
+		}
+		usedPaths[repoSkillPath] = skill.Name
+
+		if err := CheckoutPaths(tempDir, repoSkillPath); err != nil {
+			return UploadResult{}, data, err
+		}
+		if err := CopySkillFiles(tempDir, project.Resolve(skill.Path), repoSkillPath); err != nil {
+			return UploadResult{}, data, err
+		}
+		stats, changed, err := StageSkill(tempDir, repoSkillPath)
+		if err != nil {
+			return UploadResult{}, data, fmt.Errorf("%s: %w", skill.Name, err)
+		}
+		if !changed {
+			result.Unchanged = append(result.Unchanged, skill.Name)
+			continue
+		}
+
+		uploaded := UploadedSkill{
+			Name:        skill.Name,
+			Description: skill.Description,
+			RepoPath:    filepath.ToSlash(repoSkillPath),
+			Stats:       stats,
+		}
+		msg, err := renderCommitMessage(opts.Templates.Commit, data.forSkill(uploaded))
+		if err != nil {
+			return UploadResult{}, data, err
+		}
+		if err := Commit(tempDir, msg, opts.Templates.Signoff); err != nil {
+			return UploadResult{}, data, fmt.Errorf("%s: %w", skill.Name, err)
+		}
+		if uploaded.TreeHash, err = getTreeHash(tempDir, uploaded.RepoPath); err != nil {
+			return UploadResult{}, data, fmt.Errorf("%s: error reading tree hash: %w", skill.Name, err)
+		}
+		result.Skills = append(result.Skills, uploaded)
+	}
+
+	if len(result.Skills) == 0 {
+		return UploadResult{}, data, fmt.Errorf("no changes to upload (local content is identical to remote)")
+	}
+	return result, data, nil
+}
+
+// resolveRepoSkillPath usa el destino elegido, la ruta del skill si ya existe en el repo
+// o, si es nuevo, la ubicación más probable según el layout del repo
+func resolveRepoSkillPath(layout RepoLayout, skill db.InstalledSkill, dest string) (string, error) {
+	if dest != "" {
+		if err := layout.CheckDestination(skill.Name, dest); err != nil {
+			return "", err
+		}
+		cleaned, _ := CleanDestination(dest)
+		return filepath.FromSlash(cleaned), nil
+	}
+	if existing, ok := layout.Find(skill.Name); ok {
+		return filepath.FromSlash(existing), nil
+	}
+	suggestions := layout.Suggest(filepath.Base(skill.Path))
+	if len(suggestions) == 0 {
+		return "", fmt.Errorf("no free destination for '%s', choose one with --dest", skill.Name)
+	}
+	return filepath.FromSlash(suggestions[0]), nil
+}
+
+// describePR genera el título y el cuerpo de la PR listando todos los skills incluidos
+func describePR(skills []UploadedSkill) (string, string) {
+	if len(skills) == 1 {
+		sk := skills[0]
+		title := fmt.Sprintf("Update skill: %s", sk.Name)
+		body := fmt.Sprintf("This PR updates the skill '%s'.\n\nAutomatically generated by skli.\n\n%s", sk.Name, sk.Description)
+		return title, body
+	}
+
+	names := make([]string, 0, len(skills))
+	var b strings.Builder
+	fmt.Fprintf(&b, "This PR updates %d skills:\n\n", len(skills))
+	for _, sk := range skills {
+		names = append(names, sk.Name)
+		fmt.Fprintf(&b, "- **%s** (`%s`)", sk.Name, sk.RepoPath)
+		if sk.Description != "" {
+			fmt.Fprintf(&b, ": %s", sk.Description)
+		}
+		b.WriteString("\n")
 	}
+	b.WriteString("\nAutomatically generated by skli.\n")
 
-	return PushAndCreatePR(tempDir, targetRemoteURL, branchName, skill.Name, skill.Description)
+	return fmt.Sprintf("Update skills: %s", strings.Join(names, ", ")), b.String()
 }
@@ -0,0 +1,270 @@
+package gitrepo
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Templates son las plantillas Go de ramas, commits y PRs (vacías = formato por defecto)
+type Templates struct {
+	Branch  string
+	Commit  string
+	Title   string
+	Body    string
+	Signoff bool // Añadir "Signed-off-by" a los commits
+}
+
+// DiffStats resume los cambios de un commit o de toda la rama
+type DiffStats struct {
+	Files      int
+	Insertions int
+	Deletions  int
+}
+
+// String devuelve el resumen al estilo de git ("3 files changed, +10 -2")
+func (d DiffStats) String() string {
+	noun := "files"
+	if d.Files == 1 {
+		noun = "file"
+	}
+	return fmt.Sprintf("%d %s changed, +%d -%d", d.Files, noun, d.Insertions, d.Deletions)
+}
+
+func (d DiffStats) add(other DiffStats) DiffStats {
+	return DiffStats{
+		Files:      d.Files + other.Files,
+		Insertions: d.Insertions + other.Insertions,
+		Deletions:  d.Deletions + other.Deletions,
+	}
+}
+
+// TemplateData son las variables disponibles en las plantillas
+type TemplateData struct {
+	Name        string // Skill del commit, o el primero del lote
+	Description string
+	Slug        string // Nombre saneado para ramas ("<n>-skills" en lotes)
+	RepoPath    string // Ruta del skill en el repo (solo en commit)
+	Skills      []UploadedSkill
+	Names       []string
+	Stats       DiffStats // Del commit en la plantilla de commit; de toda la rama en título y cuerpo
+	User        string    // git user.name
+	Email       string    // git user.email
+	Date        string    // YYYY-MM-DD
+	Timestamp   string    // YYYYMMDD-HHMMSS
+	Repo        string    // URL web del repo destino
+	Vars        map[string]string
+}
+
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+	"slug":  slugify,
+}
+
+// newTemplateData prepara las variables comunes antes de crear la rama
+func newTemplateData(repoDir, remoteURL, label string, names []string, vars map[string]string) TemplateData {
+	now := time.Now()
+	user, email := gitAuthor(repoDir)
+	data := TemplateData{
+		Slug:      slugify(label),
+		Names:     names,
+		User:      user,
+		Email:     email,
+		Date:      now.Format("2006-01-02"),
+		Timestamp: now.Format("20060102-150405"),
+		Repo:      normalizeRepoWebURL(remoteURL),
+		Vars:      vars,
+	}
+	if len(names) > 0 {
+		data.Name = names[0]
+	}
+	if data.Vars == nil {
+		data.Vars = map[string]string{}
+	}
+	return data
+}
+
+// forSkill devuelve las variables para el commit de un skill
+func (d TemplateData) forSkill(skill UploadedSkill) TemplateData {
+	d.Name = skill.Name
+	d.Description = skill.Description
+	d.RepoPath = skill.RepoPath
+	d.Stats = skill.Stats
+	return d
+}
+
+// forPR devuelve las variables para el título y el cuerpo con los skills subidos
+func (d TemplateData) forPR(skills []UploadedSkill) TemplateData {
+	d.Skills = skills
+	d.Names = make([]string, 0, len(skills))
+	d.Stats = DiffStats{}
+	for _, sk := range skills {
+		d.Names = append(d.Names, sk.Name)
+		d.Stats = d.Stats.add(sk.Stats)
+	}
+	if len(skills) > 0 {
+		d.Name = skills[0].Name
+		d.Description = skills[0].Description
+	}
+	d.RepoPath = ""
+	return d
+}
+
+func renderTemplate(name, text string, data TemplateData) (string, error) {
+	tpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("error rendering %s template: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// renderBranchName genera el nombre de la rama y comprueba que git lo acepta
+func renderBranchName(tpl string, data TemplateData) (string, error) {
+	name := fmt.Sprintf("feat/update-%s-%s", data.Slug, data.Timestamp)
+	if tpl != "" {
+		rendered, err := renderTemplate("branch", tpl, data)
+		if err != nil {
+			return "", err
+		}
+		name = strings.TrimSpace(rendered)
+	}
+
+	if err := exec.Command("git", "check-ref-format", "--branch", name).Run(); err != nil {
+		return "", fmt.Errorf("invalid branch name %q", name)
+	}
+	return name, nil
+}
+
+// renderCommitMessage genera el mensaje del commit de un skill
+func renderCommitMessage(tpl string, data TemplateData) (string, error) {
+	if tpl == "" {
+		return fmt.Sprintf("feat(%s): update skill content", data.Name), nil
+	}
+	msg, err := renderTemplate("commit", tpl, data)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(msg) == "" {
+		return "", fmt.Errorf("commit template produced an empty message")
+	}
+	return msg, nil
+}
+
+// renderPR genera título y cuerpo. Los overrides (--title, --body-file) tienen prioridad sobre la config
+// y se usan tal cual: suelen traer ejemplos de código con "{{" que no son plantillas.
+func renderPR(opts PROptions, data TemplateData) (string, string, error) {
+	title, body := describePR(data.Skills)
+
+	if opts.Title != "" {
+		title = strings.TrimSpace(opts.Title)
+	} else if opts.Templates.Title != "" {
+		rendered, err := renderTemplate("title", opts.Templates.Title, data)
+		if err != nil {
+			return "", "", err
+		}
+		title = strings.TrimSpace(rendered)
+	}
+
+	if opts.Body != "" {
+		body = opts.Body
+	} else if opts.Templates.Body != "" {
+		rendered, err := renderTemplate("body", opts.Templates.Body, data)
+		if err != nil {
+			return "", "", err
+		}
+		body = rendered
+	}
+
+	if title == "" {
+		return "", "", fmt.Errorf("PR title is empty")
+	}
+	return title, body, nil
+}
+
+// StageSkill añade al índice solo los cambios bajo repoSkillPath y devuelve sus estadísticas.
+// Devuelve false si el skill no tiene cambios respecto al repo.
+func StageSkill(repoDir, repoSkillPath string) (DiffStats, bool, error) {
+	if err := runGit(repoDir, "add", "-A", "--", repoSkillPath); err != nil {
+		return DiffStats{}, false, fmt.Errorf("git add failed: %w", err)
+	}
+	if err := runGit(repoDir, "diff", "--staged", "--quiet"); err == nil {
+		return DiffStats{}, false, nil
+	}
+
+	cmd := exec.Command("git", "diff", "--staged", "--numstat")
+	cmd.Dir = repoDir
+	output, err := cmd.Output()
+	if err != nil {
+		return DiffStats{}, false, fmt.Errorf("git diff failed: %w", err)
+	}
+	return parseNumstat(string(output)), true, nil
+}
+
+// Commit hace commit de lo que hay en el índice
+func Commit(repoDir, message string, signoff bool) error {
+	args := []string{"commit", "-m", message}
+	if signoff {
+		args = append(args, "--signoff")
+	}
+	if err := runGit(repoDir, args...); err != nil {
+		return fmt.Errorf("git commit failed: %w", err)
+	}
+	return nil
+}
+
+// parseNumstat suma la salida de git diff --numstat (los binarios cuentan como fichero sin líneas)
+func parseNumstat(output string) DiffStats {
+	var stats DiffStats
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		stats.Files++
+		if n, err := strconv.Atoi(fields[0]); err == nil {
+			stats.Insertions += n
+		}
+		if n, err := strconv.Atoi(fields[1]); err == nil {
+			stats.Deletions += n
+		}
+	}
+	return stats
+}
+
+// gitAuthor devuelve el autor que usará git en los commits ("Nombre <email> fecha zona")
+func gitAuthor(repoDir string) (string, string) {
+	cmd := exec.Command("git", "var", "GIT_AUTHOR_IDENT")
+	cmd.Dir = repoDir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", ""
+	}
+	ident := string(output)
+	start, end := strings.Index(ident, "<"), strings.Index(ident, ">")
+	if start < 0 || end < start {
+		return strings.TrimSpace(ident), ""
+	}
+	return strings.TrimSpace(ident[:start]), ident[start+1 : end]
+}
+
+// slugify deja solo minúsculas, dígitos y guiones (ej: "My Skill" → "my-skill")
+func slugify(name string) string {
+	name = strings.ReplaceAll(strings.ToLower(name), " ", "-")
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+		return -1
+	}, name)
+}
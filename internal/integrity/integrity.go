@@ -0,0 +1,115 @@
+package integrity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Manifest asocia cada fichero de un skill (ruta relativa con "/") a su SHA-256 en hex
+type Manifest map[string]string
+
+// Drift describe las diferencias entre el manifiesto registrado y el contenido en disco
+type Drift struct {
+	Added    []string
+	Removed  []string
+	Modified []string
+}
+
+// Clean indica si no hay diferencias
+func (d Drift) Clean() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// Compute calcula el manifiesto de todos los ficheros bajo dir.
+// Los enlaces simbólicos se siguen: al instalar se copian como ficheros normales, y el manifiesto
+// calculado sobre el commit debe coincidir con el del skill instalado.
+// Si dir es un fichero (artefacto generado por un conversor) el manifiesto tiene solo su nombre.
+func Compute(dir string) (Manifest, error) {
+	manifest := Manifest{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			rel = filepath.Base(path)
+		}
+		rel = filepath.ToSlash(rel)
+
+		hash, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+		manifest[rel] = hash
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error hashing %s: %w", dir, err)
+	}
+
+	return manifest, nil
+}
+
+// Compare devuelve los ficheros añadidos, eliminados y modificados de actual respecto a expected
+func Compare(expected, actual Manifest) Drift {
+	var drift Drift
+	for name, hash := range expected {
+		got, ok := actual[name]
+		switch {
+		case !ok:
+			drift.Removed = append(drift.Removed, name)
+		case got != hash:
+			drift.Modified = append(drift.Modified, name)
+		}
+	}
+	for name := range actual {
+		if _, ok := expected[name]; !ok {
+			drift.Added = append(drift.Added, name)
+		}
+	}
+
+	sort.Strings(drift.Added)
+	sort.Strings(drift.Removed)
+	sort.Strings(drift.Modified)
+	return drift
+}
+
+// Check compara dir con el manifiesto esperado. Un directorio inexistente cuenta como todo eliminado.
+func Check(dir string, expected Manifest) (Drift, error) {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return Compare(expected, Manifest{}), nil
+	}
+	actual, err := Compute(dir)
+	if err != nil {
+		return Drift{}, err
+	}
+	return Compare(expected, actual), nil
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,104 @@
+package integrity
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		full := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestComputeUsesSlashPaths(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"SKILL.md":          "---\nname: a\n---\n",
+		"scripts/run.sh":    "echo hi\n",
+		"references/doc.md": "doc",
+	})
+
+	manifest, err := Compute(dir)
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+	if len(manifest) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", manifest)
+	}
+	// sha256("echo hi\n")
+	if got := manifest["scripts/run.sh"]; got != "ab08508fdf5ca4da5c4995987bc41c56c048aaa5eeb046417ae4049b7d40286e" {
+		t.Fatalf("unexpected hash for scripts/run.sh: %q", got)
+	}
+}
+
+func TestCheckReportsDrift(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"SKILL.md": "original",
+		"keep.md":  "same",
+		"gone.md":  "bye",
+	})
+	expected, err := Compute(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeFiles(t, dir, map[string]string{
+		"SKILL.md":    "tampered",
+		"new/evil.sh": "rm -rf /",
+	})
+	if err := os.Remove(filepath.Join(dir, "gone.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	drift, err := Check(dir, expected)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	want := Drift{
+		Added:    []string{"new/evil.sh"},
+		Removed:  []string{"gone.md"},
+		Modified: []string{"SKILL.md"},
+	}
+	if !reflect.DeepEqual(drift, want) {
+		t.Fatalf("unexpected drift: %+v", drift)
+	}
+	if drift.Clean() {
+		t.Fatalf("drift must not be clean")
+	}
+}
+
+func TestCheckMissingDirRemovesEverything(t *testing.T) {
+	expected := Manifest{"SKILL.md": "x", "a.md": "y"}
+	drift, err := Check(filepath.Join(t.TempDir(), "missing"), expected)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if len(drift.Removed) != 2 || len(drift.Added) != 0 || len(drift.Modified) != 0 {
+		t.Fatalf("unexpected drift: %+v", drift)
+	}
+}
+
+func TestComputeSingleFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"demo.mdc": "echo hi\n"})
+
+	manifest, err := Compute(filepath.Join(dir, "demo.mdc"))
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+	want := Manifest{"demo.mdc": "ab08508fdf5ca4da5c4995987bc41c56c048aaa5eeb046417ae4049b7d40286e"}
+	if !reflect.DeepEqual(manifest, want) {
+		t.Fatalf("unexpected manifest %+v", manifest)
+	}
+}
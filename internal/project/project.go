@@ -0,0 +1,105 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// LockFileName es el nombre del lock file que marca la raíz de un proyecto
+const LockFileName = "skli.lock"
+
+var (
+	mu       sync.RWMutex
+	override string
+)
+
+// SetRoot fija la raíz del proyecto (ej: flag --project) y desactiva la detección automática.
+// Un dir vacío vuelve a activar la detección.
+func SetRoot(dir string) error {
+	if dir == "" {
+		mu.Lock()
+		override = ""
+		mu.Unlock()
+		return nil
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("could not resolve project dir: %w", err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("invalid project dir: %s", dir)
+	}
+
+	mu.Lock()
+	override = abs
+	mu.Unlock()
+	return nil
+}
+
+// Root devuelve la raíz del proyecto actual.
+// Usa el override si existe; si no, busca hacia arriba desde el directorio de trabajo.
+func Root() string {
+	mu.RLock()
+	dir := override
+	mu.RUnlock()
+	if dir != "" {
+		return dir
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+	return FindRoot(cwd)
+}
+
+// FindRoot sube desde start hasta encontrar el skli.lock o el .git más cercano, como hace git.
+// Si no encuentra ninguno devuelve start.
+func FindRoot(start string) string {
+	start, err := filepath.Abs(start)
+	if err != nil {
+		return start
+	}
+
+	dir := start
+	for {
+		if exists(filepath.Join(dir, LockFileName)) || exists(filepath.Join(dir, ".git")) {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return start
+		}
+		dir = parent
+	}
+}
+
+// Resolve convierte una ruta relativa a la raíz del proyecto en absoluta.
+// Las rutas absolutas se devuelven limpias sin cambios.
+func Resolve(path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(Root(), path)
+}
+
+// Rel devuelve la ruta relativa a la raíz del proyecto.
+// Si la ruta está fuera del proyecto se devuelve absoluta.
+func Rel(path string) string {
+	abs := Resolve(path)
+	rel, err := filepath.Rel(Root(), abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return abs
+	}
+	return rel
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,107 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func withTempWorkdir(t *testing.T, dir string) {
+	t.Helper()
+	prev, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(prev)
+		_ = SetRoot("")
+	})
+}
+
+func TestFindRootPrefersNearestMarker(t *testing.T) {
+	tmp, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := filepath.Join(tmp, "repo")
+	sub := filepath.Join(repo, "pkg", "deep")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := FindRoot(sub); got != repo {
+		t.Fatalf("expected .git root %q, got %q", repo, got)
+	}
+
+	nested := filepath.Join(repo, "pkg")
+	if err := os.WriteFile(filepath.Join(nested, LockFileName), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := FindRoot(sub); got != nested {
+		t.Fatalf("expected nearest skli.lock %q, got %q", nested, got)
+	}
+}
+
+func TestFindRootFallsBackToStart(t *testing.T) {
+	tmp, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FindRoot(tmp); got != tmp {
+		t.Fatalf("expected start dir %q, got %q", tmp, got)
+	}
+}
+
+func TestRootResolveAndRel(t *testing.T) {
+	tmp, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(tmp, "a", "b")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, LockFileName), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	withTempWorkdir(t, sub)
+
+	if got := Root(); got != tmp {
+		t.Fatalf("expected root %q, got %q", tmp, got)
+	}
+	want := filepath.Join(tmp, "skills", "x")
+	if got := Resolve(filepath.Join("skills", "x")); got != want {
+		t.Fatalf("Resolve: expected %q, got %q", want, got)
+	}
+	if got := Rel(want); got != filepath.Join("skills", "x") {
+		t.Fatalf("Rel: unexpected %q", got)
+	}
+	if got := Rel(filepath.Dir(tmp)); got != filepath.Dir(tmp) {
+		t.Fatalf("Rel outside project should stay absolute, got %q", got)
+	}
+}
+
+func TestSetRootOverride(t *testing.T) {
+	tmp, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := t.TempDir()
+	withTempWorkdir(t, other)
+
+	if err := SetRoot(tmp); err != nil {
+		t.Fatalf("SetRoot: %v", err)
+	}
+	if got := Root(); got != tmp {
+		t.Fatalf("expected override %q, got %q", tmp, got)
+	}
+	if err := SetRoot(filepath.Join(tmp, "missing")); err == nil {
+		t.Fatalf("expected error for missing project dir")
+	}
+}
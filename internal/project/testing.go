@@ -0,0 +1,21 @@
+package project
+
+import "testing"
+
+// SetRootForTest fija dir como raíz del proyecto durante el test y restaura la anterior al terminar.
+// Los tests que la usan no pueden ejecutarse en paralelo: la raíz es global al proceso.
+func SetRootForTest(t testing.TB, dir string) {
+	t.Helper()
+	mu.RLock()
+	prev := override
+	mu.RUnlock()
+
+	if err := SetRoot(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		mu.Lock()
+		override = prev
+		mu.Unlock()
+	})
+}
@@ -0,0 +1,184 @@
+package provenance
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"skli/internal/config"
+	"skli/internal/gitrepo"
+)
+
+// Métodos de firma que se pueden exigir a un remote
+const (
+	MethodCommit = "commit" // El commit descargado está firmado
+	MethodTag    = "tag"    // Un tag firmado apunta al commit descargado
+	MethodFile   = "file"   // El repo incluye una firma separada del árbol de skills
+)
+
+// DefaultFile es la firma separada que se busca en la raíz del repo con MethodFile
+const DefaultFile = "skli.sig"
+
+// Namespace es el namespace de las firmas SSH separadas (ssh-keygen -Y sign -n skli)
+const Namespace = "skli"
+
+// ErrUnverified indica un commit sin una firma válida de los firmantes configurados
+var ErrUnverified = errors.New("signature not verified")
+
+// rule son los firmantes exigidos a un remote
+type rule struct {
+	remote string
+	method string
+	file   string
+	ssh    []string // Claves públicas SSH ("ssh-ed25519 AAAA... comentario")
+	gpg    []string // Fingerprints GPG en mayúsculas, sin espacios
+}
+
+type settings struct {
+	strict bool
+	rules  []rule
+}
+
+var current settings
+
+var (
+	sshKeyPattern = regexp.MustCompile(`^(?:ssh-|ecdsa-|sk-)\S+\s+[A-Za-z0-9+/]+=*(?:\s|$)`)
+	gpgKeyPattern = regexp.MustCompile(`^[0-9A-F]{16}(?:[0-9A-F]{24})?$`)
+)
+
+// Configure aplica la sección [provenance] de la config; la configuración vacía no exige firmas
+func Configure(cfg config.ProvenanceConfig) error {
+	rules := make([]rule, 0, len(cfg.Signers))
+	for _, s := range cfg.Signers {
+		if strings.TrimSpace(s.Remote) == "" {
+			return fmt.Errorf("signer without remote")
+		}
+		r := rule{remote: normalizeRemote(s.Remote), method: strings.ToLower(strings.TrimSpace(s.Method)), file: s.File}
+		switch r.method {
+		case "":
+			r.method = MethodCommit
+		case MethodCommit, MethodTag, MethodFile:
+		default:
+			return fmt.Errorf("remote %s: unknown method '%s' (use commit, tag or file)", s.Remote, s.Method)
+		}
+		if r.file == "" {
+			r.file = DefaultFile
+		}
+		for _, key := range s.Keys {
+			key = strings.TrimSpace(key)
+			fpr := strings.ToUpper(strings.ReplaceAll(strings.TrimPrefix(key, "0x"), " ", ""))
+			switch {
+			case sshKeyPattern.MatchString(key):
+				r.ssh = append(r.ssh, key)
+			case gpgKeyPattern.MatchString(fpr):
+				r.gpg = append(r.gpg, fpr)
+			default:
+				return fmt.Errorf("remote %s: '%s' is neither an SSH public key nor a GPG fingerprint", s.Remote, key)
+			}
+		}
+		if len(r.ssh)+len(r.gpg) == 0 {
+			return fmt.Errorf("remote %s has no keys", s.Remote)
+		}
+		rules = append(rules, r)
+	}
+
+	current = settings{strict: cfg.Strict, rules: rules}
+	return nil
+}
+
+// Strict indica si una firma no verificada impide instalar; sin strict solo se avisa
+func Strict() bool {
+	return current.strict
+}
+
+// Required indica si el remote tiene firmantes configurados
+func Required(repoURL string) bool {
+	_, ok := ruleFor(repoURL)
+	return ok
+}
+
+// Verify comprueba con los firmantes configurados para repoURL el commit descargado en repoDir.
+// skillsPath es la carpeta de skills dentro del repo (la que firma MethodFile).
+// Devuelve el firmante verificado, o vacío si el remote no exige firmas.
+func Verify(repoDir, repoURL, commit, skillsPath string) (string, error) {
+	r, ok := ruleFor(repoURL)
+	if !ok {
+		return "", nil
+	}
+
+	var signer Signer
+	var err error
+	switch r.method {
+	case MethodCommit:
+		signer, err = verifyCommit(repoDir, r, commit)
+	case MethodTag:
+		signer, err = verifyTag(repoDir, r, commit)
+	case MethodFile:
+		signer, err = verifyFile(repoDir, r, commit, skillsPath)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w: %s at %s: %v", ErrUnverified, r.method, shortHash(commit), err)
+	}
+	return signer.String(), nil
+}
+
+// SameKey indica si dos firmantes devueltos por Verify usan la misma clave
+func SameKey(a, b string) bool {
+	return ParseSigner(a).Key == ParseSigner(b).Key
+}
+
+// Signer es quien firmó: el principal SSH o el usuario GPG, y el fingerprint de su clave
+type Signer struct {
+	Name string
+	Key  string // "SHA256:..." en SSH, fingerprint de la clave principal en GPG
+}
+
+func (s Signer) String() string {
+	if s.Name == "" {
+		return s.Key
+	}
+	return s.Name + " " + s.Key
+}
+
+// ParseSigner interpreta un firmante guardado con Signer.String (la clave es el último campo)
+func ParseSigner(s string) Signer {
+	s = strings.TrimSpace(s)
+	i := strings.LastIndex(s, " ")
+	if i < 0 {
+		return Signer{Key: s}
+	}
+	return Signer{Name: s[:i], Key: s[i+1:]}
+}
+
+func ruleFor(repoURL string) (rule, bool) {
+	remote := normalizeRemote(repoURL)
+	for _, r := range current.rules {
+		if r.remote == remote {
+			return r, true
+		}
+	}
+	return rule{}, false
+}
+
+// normalizeRemote reduce una URL de repo a host/ruta para comparar https, ssh y la sintaxis scp
+func normalizeRemote(repoURL string) string {
+	u := strings.TrimSpace(gitrepo.ParseGitURL(strings.TrimSpace(repoURL)).BaseURL)
+	if i := strings.Index(u, "://"); i >= 0 {
+		u = u[i+3:]
+	} else if at := strings.Index(u, "@"); at >= 0 {
+		u = strings.Replace(u, ":", "/", 1)
+	}
+	if at := strings.Index(u, "@"); at >= 0 && at < strings.Index(u+"/", "/") {
+		u = u[at+1:]
+	}
+	u = strings.TrimSuffix(strings.TrimSuffix(u, "/"), ".git")
+	return strings.ToLower(u)
+}
+
+func shortHash(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
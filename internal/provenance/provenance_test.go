@@ -0,0 +1,239 @@
+package provenance
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"skli/internal/config"
+)
+
+func configure(t *testing.T, cfg config.ProvenanceConfig) error {
+	t.Helper()
+	t.Cleanup(func() { Configure(config.ProvenanceConfig{}) })
+	return Configure(cfg)
+}
+
+func requireTools(t *testing.T, tools ...string) {
+	t.Helper()
+	for _, tool := range tools {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s not available", tool)
+		}
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+}
+
+func runIn(t *testing.T, dir string, name string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("%s %v: %v\n%s", name, args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// sshKey genera una clave SSH y devuelve la ruta de la pública y su contenido
+func sshKey(t *testing.T, comment string) (string, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "key")
+	runIn(t, "", "ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", comment, "-f", path)
+	pub, _ := os.ReadFile(path + ".pub")
+	return path + ".pub", strings.TrimSpace(string(pub))
+}
+
+// signedRepo crea un repo con skills/demo y un commit firmado con la clave SSH signingKey (vacía = sin firmar)
+func signedRepo(t *testing.T, signingKey string) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "skills", "demo"), 0755)
+	os.WriteFile(filepath.Join(dir, "skills", "demo", "SKILL.md"), []byte("---\nname: demo\n---\n"), 0644)
+	runIn(t, dir, "git", "init", "-q")
+	runIn(t, dir, "git", "add", "-A")
+	args := []string{"-c", "user.name=Alice", "-c", "user.email=alice@acme.io", "commit", "-q", "-m", "skills"}
+	if signingKey != "" {
+		args = append([]string{"-c", "gpg.format=ssh", "-c", "user.signingkey=" + signingKey}, append(args, "-S")...)
+	}
+	runIn(t, dir, "git", args...)
+	return dir, runIn(t, dir, "git", "rev-parse", "HEAD")
+}
+
+func TestConfigureRejectsInvalidSigners(t *testing.T) {
+	pub := "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGv3 alice"
+	cases := map[string]config.Signer{
+		"without remote": {Keys: []string{pub}},
+		"no keys":        {Remote: "https://github.com/acme/skills"},
+		"unknown method": {Remote: "https://github.com/acme/skills", Method: "notary", Keys: []string{pub}},
+		"neither":        {Remote: "https://github.com/acme/skills", Keys: []string{"alice@acme.io"}},
+	}
+	for want, signer := range cases {
+		err := configure(t, config.ProvenanceConfig{Signers: []config.Signer{signer}})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%s: expected an error, got %v", want, err)
+		}
+	}
+	err := configure(t, config.ProvenanceConfig{Signers: []config.Signer{
+		{Remote: "https://github.com/acme/skills", Keys: []string{pub, "0x3E71 76E4 1964 86E7"}},
+	}})
+	if err != nil {
+		t.Fatalf("SSH keys and GPG key ids must be accepted: %v", err)
+	}
+}
+
+func TestRemotesMatchAcrossURLForms(t *testing.T) {
+	configure(t, config.ProvenanceConfig{Signers: []config.Signer{
+		{Remote: "https://github.com/Acme/Skills.git", Keys: []string{"3E7176E4196486E7"}},
+	}})
+	for _, url := range []string{"git@github.com:acme/skills", "ssh://git@github.com/acme/skills.git", "https://github.com/acme/skills/tree/main/skills"} {
+		if !Required(url) {
+			t.Fatalf("%s must match the configured remote", url)
+		}
+	}
+	if Required("https://github.com/acme/other") {
+		t.Fatal("other repos must not require signatures")
+	}
+	if signer, err := Verify(t.TempDir(), "https://github.com/acme/other", "abc", "skills"); signer != "" || err != nil {
+		t.Fatalf("repos without signers must not be verified, got %q %v", signer, err)
+	}
+}
+
+func TestVerifySignedCommit(t *testing.T) {
+	requireTools(t, "git", "ssh-keygen")
+	key, pub := sshKey(t, "alice@acme.io")
+	_, other := sshKey(t, "mallory")
+
+	repo, commit := signedRepo(t, key)
+	configure(t, config.ProvenanceConfig{Signers: []config.Signer{{Remote: "https://git.acme.io/skills", Keys: []string{other, pub}}}})
+	signer, err := Verify(repo, "https://git.acme.io/skills", commit, "skills")
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if s := ParseSigner(signer); s.Name != "alice@acme.io" || !strings.HasPrefix(s.Key, "SHA256:") {
+		t.Fatalf("unexpected signer %q", signer)
+	}
+
+	configure(t, config.ProvenanceConfig{Signers: []config.Signer{{Remote: "https://git.acme.io/skills", Keys: []string{other}}}})
+	if _, err := Verify(repo, "https://git.acme.io/skills", commit, "skills"); !errors.Is(err, ErrUnverified) || !strings.Contains(err.Error(), "configured key") {
+		t.Fatalf("commits signed by other keys must fail, got %v", err)
+	}
+
+	unsigned, commit := signedRepo(t, "")
+	if _, err := Verify(unsigned, "https://git.acme.io/skills", commit, "skills"); !errors.Is(err, ErrUnverified) || !strings.Contains(err.Error(), "not signed") {
+		t.Fatalf("unsigned commits must fail, got %v", err)
+	}
+}
+
+func TestVerifySignedTag(t *testing.T) {
+	requireTools(t, "git", "ssh-keygen")
+	key, pub := sshKey(t, "release")
+	origin, commit := signedRepo(t, "")
+	configure(t, config.ProvenanceConfig{Signers: []config.Signer{{Remote: origin, Method: "tag", Keys: []string{pub}}}})
+
+	// Clon como el de CloneAndScan: solo el commit y el remote origin
+	clone := t.TempDir()
+	runIn(t, clone, "git", "init", "-q")
+	runIn(t, clone, "git", "remote", "add", "origin", origin)
+	runIn(t, clone, "git", "fetch", "-q", "--depth", "1", "origin", "HEAD")
+
+	if _, err := Verify(clone, origin, commit, "skills"); !errors.Is(err, ErrUnverified) || !strings.Contains(err.Error(), "no annotated tag") {
+		t.Fatalf("commits without tags must fail, got %v", err)
+	}
+	runIn(t, origin, "git", "-c", "user.name=Alice", "-c", "user.email=alice@acme.io", "-c", "gpg.format=ssh", "-c", "user.signingkey="+key, "tag", "-s", "v1.0.0", "-m", "v1.0.0")
+	signer, err := Verify(clone, origin, commit, "skills")
+	if err != nil || ParseSigner(signer).Name != "release" {
+		t.Fatalf("signed tag must be verified, got %q %v", signer, err)
+	}
+}
+
+func TestVerifySignatureFile(t *testing.T) {
+	requireTools(t, "git", "ssh-keygen")
+	key, pub := sshKey(t, "ci")
+	repo, _ := signedRepo(t, "")
+	tree := runIn(t, repo, "git", "rev-parse", "HEAD:skills")
+	sign := exec.Command("ssh-keygen", "-Y", "sign", "-n", Namespace, "-f", strings.TrimSuffix(key, ".pub"))
+	sign.Stdin = strings.NewReader(tree + "\n")
+	signature, err := sign.Output()
+	if err != nil {
+		t.Fatalf("ssh-keygen -Y sign: %v", err)
+	}
+	os.WriteFile(filepath.Join(repo, DefaultFile), signature, 0644)
+	runIn(t, repo, "git", "add", DefaultFile)
+	runIn(t, repo, "git", "-c", "user.name=CI", "-c", "user.email=ci@acme.io", "commit", "-q", "-m", "sign")
+	commit := runIn(t, repo, "git", "rev-parse", "HEAD")
+
+	configure(t, config.ProvenanceConfig{Signers: []config.Signer{{Remote: "https://git.acme.io/skills", Method: "file", Keys: []string{pub}}}})
+	signer, err := Verify(repo, "https://git.acme.io/skills", commit, "skills")
+	if err != nil || ParseSigner(signer).Name != "ci" {
+		t.Fatalf("signature file must be verified, got %q %v", signer, err)
+	}
+
+	// Un cambio en los skills invalida la firma
+	os.WriteFile(filepath.Join(repo, "skills", "demo", "SKILL.md"), []byte("---\nname: demo\n---\nchanged\n"), 0644)
+	runIn(t, repo, "git", "-c", "user.name=CI", "-c", "user.email=ci@acme.io", "commit", "-q", "-am", "change")
+	commit = runIn(t, repo, "git", "rev-parse", "HEAD")
+	if _, err := Verify(repo, "https://git.acme.io/skills", commit, "skills"); !errors.Is(err, ErrUnverified) {
+		t.Fatalf("changed skills must invalidate the signature, got %v", err)
+	}
+}
+
+func TestVerifySignatureFileWithoutPrincipal(t *testing.T) {
+	requireTools(t, "git", "sh")
+	repo, _ := signedRepo(t, "")
+	os.WriteFile(filepath.Join(repo, DefaultFile), []byte("-----BEGIN SSH SIGNATURE-----\nAAAA\n-----END SSH SIGNATURE-----\n"), 0644)
+	runIn(t, repo, "git", "add", DefaultFile)
+	runIn(t, repo, "git", "-c", "user.name=CI", "-c", "user.email=ci@acme.io", "commit", "-q", "-m", "sign")
+	commit := runIn(t, repo, "git", "rev-parse", "HEAD")
+
+	// ssh-keygen que termina bien sin principal y con un aviso en stderr
+	bin := t.TempDir()
+	fake := "#!/bin/sh\necho 'warning: agent not running' >&2\nexit 0\n"
+	if err := os.WriteFile(filepath.Join(bin, "ssh-keygen"), []byte(fake), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	configure(t, config.ProvenanceConfig{Signers: []config.Signer{{Remote: "https://git.acme.io/skills", Method: "file", Keys: []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGv3 ci"}}}})
+	_, err := Verify(repo, "https://git.acme.io/skills", commit, "skills")
+	if !errors.Is(err, ErrUnverified) || !strings.Contains(err.Error(), "unknown key") {
+		t.Fatalf("expected an unknown key, got %v", err)
+	}
+}
+
+func TestVerifyGPGCommit(t *testing.T) {
+	requireTools(t, "git", "gpg")
+	home, err := os.MkdirTemp("", "gpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(home) })
+	os.Chmod(home, 0700)
+	t.Setenv("GNUPGHOME", home)
+	runIn(t, "", "gpg", "--batch", "--pinentry-mode", "loopback", "--passphrase", "", "--quick-gen-key", "Bob <bob@acme.io>", "ed25519", "sign", "never")
+	fpr := ""
+	for _, line := range strings.Split(runIn(t, "", "gpg", "--list-keys", "--with-colons"), "\n") {
+		if fields := strings.Split(line, ":"); fields[0] == "fpr" {
+			fpr = fields[9]
+			break
+		}
+	}
+
+	repo := t.TempDir()
+	runIn(t, repo, "git", "init", "-q")
+	runIn(t, repo, "git", "-c", "user.name=Bob", "-c", "user.email=bob@acme.io", "-c", "user.signingkey="+fpr, "commit", "-q", "-S", "--allow-empty", "-m", "skills")
+	commit := runIn(t, repo, "git", "rev-parse", "HEAD")
+
+	configure(t, config.ProvenanceConfig{Signers: []config.Signer{{Remote: "https://git.acme.io/skills", Keys: []string{fpr[len(fpr)-16:]}}}})
+	signer, err := Verify(repo, "https://git.acme.io/skills", commit, "skills")
+	if err != nil || ParseSigner(signer).Key != fpr || !strings.Contains(signer, "bob@acme.io") {
+		t.Fatalf("GPG signature must be verified by key id, got %q %v", signer, err)
+	}
+	if !SameKey(signer, "Bob "+fpr) {
+		t.Fatal("signers with the same key must match")
+	}
+}
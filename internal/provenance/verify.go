@@ -0,0 +1,205 @@
+package provenance
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Salida de ssh-keygen -Y verify (también la de git con firmas SSH)
+	sshGoodPattern = regexp.MustCompile(`Good "[^"]*" signature for (\S+) with \S+ key (\S+)`)
+	// Líneas de estado de gpg (--status-fd y git --raw)
+	gpgGoodPattern  = regexp.MustCompile(`(?m)^\[GNUPG:\] GOODSIG \S+ (.*)$`)
+	gpgValidPattern = regexp.MustCompile(`(?m)^\[GNUPG:\] VALIDSIG (\S+)(?: \S+){8} (\S+)`)
+)
+
+// verifyCommit comprueba la firma del commit
+func verifyCommit(repoDir string, r rule, commit string) (Signer, error) {
+	return verifyObject(repoDir, r, "verify-commit", commit)
+}
+
+// verifyTag busca en el remote los tags que apuntan al commit y acepta el primero con una firma válida
+func verifyTag(repoDir string, r rule, commit string) (Signer, error) {
+	out, err := git(repoDir, "", "ls-remote", "--tags", "origin")
+	if err != nil {
+		return Signer{}, fmt.Errorf("error listing tags: %w", err)
+	}
+	var tags []string
+	for _, line := range strings.Split(out, "\n") {
+		hash, ref, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		// Los tags anotados aparecen también "pelados" (^{}) con el commit al que apuntan
+		if ok && hash == commit && strings.HasSuffix(ref, "^{}") {
+			tags = append(tags, strings.TrimSuffix(ref, "^{}"))
+		}
+	}
+	if len(tags) == 0 {
+		return Signer{}, fmt.Errorf("no annotated tag points to the commit")
+	}
+
+	lastErr := fmt.Errorf("no valid tag signature")
+	for _, ref := range tags {
+		if _, err := git(repoDir, "", "fetch", "--depth", "1", "origin", ref+":"+ref); err != nil {
+			lastErr = fmt.Errorf("error fetching %s: %w", ref, err)
+			continue
+		}
+		signer, err := verifyObject(repoDir, r, "verify-tag", ref)
+		if err == nil {
+			return signer, nil
+		}
+		lastErr = fmt.Errorf("%s: %w", strings.TrimPrefix(ref, "refs/tags/"), err)
+	}
+	return Signer{}, lastErr
+}
+
+// verifyFile comprueba la firma separada del hash del árbol de skills. El mensaje firmado es la
+// salida de 'git rev-parse <commit>:<skillsPath>' (con el salto de línea).
+func verifyFile(repoDir string, r rule, commit, skillsPath string) (Signer, error) {
+	if skillsPath == "" || skillsPath == "." {
+		return Signer{}, fmt.Errorf("file signatures need the skills in a subfolder of the repo")
+	}
+	tree, err := git(repoDir, "", "rev-parse", commit+":"+strings.TrimSuffix(skillsPath, "/"))
+	if err != nil {
+		return Signer{}, fmt.Errorf("error reading the tree of %s: %w", skillsPath, err)
+	}
+	signature, err := git(repoDir, "", "show", commit+":"+r.file)
+	if err != nil {
+		return Signer{}, fmt.Errorf("signature file %s not found", r.file)
+	}
+
+	dir, err := os.MkdirTemp("", "skli-sig-*")
+	if err != nil {
+		return Signer{}, err
+	}
+	defer os.RemoveAll(dir)
+	sigFile := filepath.Join(dir, "signature")
+	if err := os.WriteFile(sigFile, []byte(signature), 0600); err != nil {
+		return Signer{}, err
+	}
+	message := strings.TrimSpace(tree) + "\n"
+
+	if strings.Contains(signature, "BEGIN SSH SIGNATURE") {
+		allowed, err := allowedSigners(dir, r)
+		if err != nil {
+			return Signer{}, err
+		}
+		// Solo stdout: un aviso en stderr no debe tomarse por el principal
+		out, err := exec.Command("ssh-keygen", "-Y", "find-principals", "-s", sigFile, "-f", allowed).Output()
+		principals := strings.Fields(string(out))
+		if err != nil || len(principals) == 0 {
+			return Signer{}, fmt.Errorf("signed by an unknown key")
+		}
+		principal := principals[0]
+		output, err := run(exec.Command("ssh-keygen", "-Y", "verify", "-f", allowed, "-I", principal, "-n", Namespace, "-s", sigFile), message)
+		if err != nil {
+			return Signer{}, fmt.Errorf("invalid signature: %s", strings.TrimSpace(output))
+		}
+		return parseSSH(output)
+	}
+
+	out, err := run(exec.Command("gpg", "--batch", "--status-fd", "1", "--verify", sigFile, "-"), message)
+	if err != nil {
+		return Signer{}, fmt.Errorf("invalid signature")
+	}
+	return parseGPG(r, out)
+}
+
+// verifyObject verifica con git un commit o un tag firmado con SSH o GPG
+func verifyObject(repoDir string, r rule, command, ref string) (Signer, error) {
+	dir, err := os.MkdirTemp("", "skli-sig-*")
+	if err != nil {
+		return Signer{}, err
+	}
+	defer os.RemoveAll(dir)
+	allowed, err := allowedSigners(dir, r)
+	if err != nil {
+		return Signer{}, err
+	}
+
+	out, err := git(repoDir, allowed, command, "--raw", ref)
+	if err != nil {
+		if strings.Contains(out, "no signature found") || strings.TrimSpace(out) == "" {
+			return Signer{}, fmt.Errorf("not signed")
+		}
+		if strings.Contains(out, "NO_PUBKEY") {
+			return Signer{}, fmt.Errorf("signed with a GPG key that is not in your keyring")
+		}
+		return Signer{}, fmt.Errorf("not signed by a configured key")
+	}
+	if strings.Contains(out, "[GNUPG:]") {
+		return parseGPG(r, out)
+	}
+	return parseSSH(out)
+}
+
+// parseSSH extrae el principal y la clave de una firma SSH válida. El fichero de firmantes
+// solo contiene las claves configuradas, así que una firma válida ya es de una de ellas.
+func parseSSH(out string) (Signer, error) {
+	m := sshGoodPattern.FindStringSubmatch(out)
+	if m == nil {
+		return Signer{}, fmt.Errorf("not signed by a configured key")
+	}
+	return Signer{Name: m[1], Key: m[2]}, nil
+}
+
+// parseGPG extrae el usuario y la clave de una firma GPG válida y comprueba que sea de un fingerprint configurado
+func parseGPG(r rule, out string) (Signer, error) {
+	valid := gpgValidPattern.FindStringSubmatch(out)
+	if valid == nil {
+		return Signer{}, fmt.Errorf("invalid signature")
+	}
+	signer := Signer{Key: valid[2]}
+	if good := gpgGoodPattern.FindStringSubmatch(out); good != nil {
+		signer.Name = strings.TrimSpace(good[1])
+	}
+	for _, fpr := range r.gpg {
+		if strings.HasSuffix(valid[1], fpr) || strings.HasSuffix(valid[2], fpr) {
+			return signer, nil
+		}
+	}
+	return Signer{}, fmt.Errorf("signed by GPG key %s, which is not configured", valid[2])
+}
+
+// allowedSigners escribe en dir el fichero de firmantes SSH permitidos con las claves de la regla
+func allowedSigners(dir string, r rule) (string, error) {
+	var b strings.Builder
+	for i, key := range r.ssh {
+		fields := strings.Fields(key)
+		principal := fmt.Sprintf("key-%d", i+1)
+		if len(fields) > 2 {
+			principal = strings.ReplaceAll(strings.Join(fields[2:], "-"), ",", "-")
+		}
+		fmt.Fprintf(&b, "%s namespaces=\"git,%s\" %s %s\n", principal, Namespace, fields[0], fields[1])
+	}
+	path := filepath.Join(dir, "allowed_signers")
+	if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// git ejecuta git en dir; con allowed usa ese fichero de firmantes SSH. Devuelve la salida combinada.
+func git(dir, allowed string, args ...string) (string, error) {
+	if allowed != "" {
+		args = append([]string{"-c", "gpg.ssh.allowedSignersFile=" + allowed}, args...)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	return run(cmd, "")
+}
+
+// run ejecuta cmd con stdin y devuelve la salida combinada
+func run(cmd *exec.Cmd, stdin string) (string, error) {
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	err := cmd.Run()
+	return out.String(), err
+}
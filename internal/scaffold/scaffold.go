@@ -0,0 +1,261 @@
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"skli/internal/gitrepo"
+	"skli/internal/project"
+	"skli/internal/skillmeta"
+	"skli/internal/skills"
+	"skli/internal/validate"
+)
+
+//go:embed all:templates
+var builtinFS embed.FS
+
+// Plantillas incluidas en el binario, en el orden en que se ofrecen
+var builtins = []struct{ name, description string }{
+	{"basic", "SKILL.md with the usual sections"},
+	{"scripts", "SKILL.md plus a scripts/ folder with a helper script"},
+	{"references", "SKILL.md plus a references/ folder read on demand"},
+}
+
+// DefaultTemplate es la plantilla usada cuando no se indica ninguna
+const DefaultTemplate = "basic"
+
+// Template es una carpeta de la que se genera un skill nuevo.
+// Los ficheros *.tmpl se procesan con text/template y pierden la extensión; el resto se copia tal cual.
+type Template struct {
+	Name        string
+	Description string
+	Source      string // URL del repo de plantillas; vacío en las incluidas
+	files       fs.FS
+}
+
+// Options son los datos del skill a crear
+type Options struct {
+	Name        string
+	Description string
+	Tags        []string
+}
+
+// templateData son los valores disponibles en las plantillas
+type templateData struct {
+	Name        string
+	Title       string // Nombre legible: "my-skill" → "My Skill"
+	Description string
+	Tags        []string
+}
+
+// BuiltIn devuelve las plantillas incluidas en skli
+func BuiltIn() []Template {
+	out := make([]Template, 0, len(builtins))
+	for _, b := range builtins {
+		sub, _ := fs.Sub(builtinFS, path.Join("templates", b.name))
+		out = append(out, Template{Name: b.name, Description: b.description, files: sub})
+	}
+	return out
+}
+
+// Catalog son las plantillas incluidas más las de los repos de plantillas configurados
+type Catalog struct {
+	Templates []Template
+	Warnings  []string // Repos de plantillas que no se pudieron cargar
+	tempDirs  []string
+}
+
+var cloneAndScanFn = gitrepo.CloneAndScan
+
+// LoadCatalog clona los repos de plantillas: cada skill de un repo es una plantilla que se llama
+// como su carpeta y sustituye a la incluida del mismo nombre. Hay que llamar a Close al terminar.
+func LoadCatalog(repos []string) *Catalog {
+	c := &Catalog{Templates: BuiltIn()}
+	for _, repo := range repos {
+		res, err := cloneAndScanFn(repo, "")
+		if err != nil {
+			c.Warnings = append(c.Warnings, fmt.Sprintf("template repo %s: %v", repo, err))
+			continue
+		}
+		c.tempDirs = append(c.tempDirs, res.TempDir)
+		for _, sk := range res.Skills {
+			c.add(Template{
+				Name:        gitrepo.GetSkillFolderName(sk),
+				Description: sk.Description,
+				Source:      repo,
+				files:       os.DirFS(filepath.Join(res.TempDir, res.SkillsPath, sk.Path)),
+			})
+		}
+	}
+	return c
+}
+
+func (c *Catalog) add(tpl Template) {
+	for i, existing := range c.Templates {
+		if existing.Name == tpl.Name {
+			c.Templates[i] = tpl
+			return
+		}
+	}
+	c.Templates = append(c.Templates, tpl)
+}
+
+// Find busca una plantilla por nombre; vacío devuelve la plantilla por defecto
+func (c *Catalog) Find(name string) (Template, error) {
+	if name == "" {
+		name = DefaultTemplate
+	}
+	names := make([]string, 0, len(c.Templates))
+	for _, tpl := range c.Templates {
+		if tpl.Name == name {
+			return tpl, nil
+		}
+		names = append(names, tpl.Name)
+	}
+	return Template{}, fmt.Errorf("unknown template '%s' (available: %s)", name, strings.Join(names, ", "))
+}
+
+// Close borra los clones de los repos de plantillas
+func (c *Catalog) Close() {
+	for _, dir := range c.tempDirs {
+		os.RemoveAll(dir)
+	}
+	c.tempDirs = nil
+}
+
+// Create genera el skill en <skillsRoot>/<name> a partir de la plantilla y lo valida.
+// Devuelve la ruta relativa al proyecto y los avisos; si la validación falla el skill
+// se deja en disco para corregirlo y se devuelve un *validate.Error.
+func Create(tpl Template, skillsRoot string, opts Options) (string, []validate.Issue, error) {
+	data, err := opts.templateData()
+	if err != nil {
+		return "", nil, err
+	}
+	if strings.TrimSpace(skillsRoot) == "" {
+		skillsRoot = skills.DefaultRoot
+	}
+
+	dir := filepath.Join(project.Resolve(skillsRoot), data.Name)
+	if _, err := os.Stat(dir); err == nil {
+		return "", nil, fmt.Errorf("%s already exists", project.Rel(dir))
+	}
+	if err := render(tpl.files, dir, data); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, fmt.Errorf("error creating skill from template '%s': %w", tpl.Name, err)
+	}
+
+	// Las plantillas de repos traen un SKILL.md normal: se rellenan sus campos conservando el resto
+	if _, err := fs.Stat(tpl.files, "SKILL.md.tmpl"); err != nil {
+		keys := []skillmeta.Key{{Name: "name", Value: data.Name}, {Name: "description", Value: data.Description}}
+		if len(data.Tags) > 0 {
+			keys = append(keys, skillmeta.Key{Name: "tags", Value: data.Tags})
+		}
+		if err := skillmeta.SetKeys(filepath.Join(dir, "SKILL.md"), keys...); err != nil {
+			os.RemoveAll(dir)
+			return "", nil, fmt.Errorf("error writing SKILL.md: %w", err)
+		}
+	}
+
+	issues, err := validate.Check(data.Name, dir)
+	return project.Rel(dir), issues, err
+}
+
+func (o Options) templateData() (templateData, error) {
+	name := strings.TrimSpace(o.Name)
+	if !validate.ValidName(name) {
+		return templateData{}, fmt.Errorf("invalid skill name '%s': use lowercase letters, digits and hyphens (max %d)", name, validate.MaxNameLength)
+	}
+	description := strings.Join(strings.Fields(o.Description), " ")
+	if description == "" {
+		return templateData{}, fmt.Errorf("a description is required")
+	}
+	if len(description) > validate.MaxDescriptionLength {
+		return templateData{}, fmt.Errorf("description is longer than %d characters", validate.MaxDescriptionLength)
+	}
+	return templateData{Name: name, Title: title(name), Description: description, Tags: ParseTags(strings.Join(o.Tags, ","))}, nil
+}
+
+// ParseTags separa etiquetas por comas o espacios, sin "#", en minúsculas y sin repetir
+func ParseTags(input string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, tag := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		tag = strings.ToLower(strings.TrimLeft(tag, "#"))
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func title(name string) string {
+	words := strings.Split(name, "-")
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+func render(files fs.FS, dir string, data templateData) error {
+	return fs.WalkDir(files, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(p))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+
+		content, err := fs.ReadFile(files, p)
+		if err != nil {
+			return err
+		}
+		if strings.HasSuffix(p, ".tmpl") {
+			target = strings.TrimSuffix(target, ".tmpl")
+			if content, err = execute(p, content, data); err != nil {
+				return err
+			}
+		}
+
+		mode := os.FileMode(0644)
+		if bytes.HasPrefix(content, []byte("#!")) {
+			mode = 0755 // Scripts con shebang
+		}
+		return os.WriteFile(target, content, mode)
+	})
+}
+
+func execute(name string, text []byte, data templateData) ([]byte, error) {
+	tpl, err := template.New(name).Funcs(template.FuncMap{"yaml": yamlValue}).Parse(string(text))
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := tpl.Execute(&out, data); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// yamlValue escribe un valor en una línea de YAML, citando lo necesario (listas en estilo [a, b])
+func yamlValue(v any) (string, error) {
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return "", err
+	}
+	if node.Kind == yaml.SequenceNode {
+		node.Style = yaml.FlowStyle
+	}
+	out, err := yaml.Marshal(&node)
+	return strings.TrimSpace(string(out)), err
+}
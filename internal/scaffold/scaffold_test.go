@@ -0,0 +1,131 @@
+package scaffold
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"skli/internal/gitrepo"
+	"skli/internal/project"
+	"skli/internal/skillmeta"
+	"skli/internal/skills"
+)
+
+func TestBuiltInTemplatesCreateValidSkills(t *testing.T) {
+	root := t.TempDir()
+	project.SetRootForTest(t, root)
+	description := "Reviews Go code: error handling, naming and tests. " + strings.Repeat("Long text ", 12)
+
+	for _, tpl := range BuiltIn() {
+		name := "demo-" + tpl.Name
+		rel, issues, err := Create(tpl, "skills", Options{Name: name, Description: description, Tags: []string{"Go", "#review, go"}})
+		if err != nil {
+			t.Fatalf("%s: Create: %v", tpl.Name, err)
+		}
+		if len(issues) > 0 {
+			t.Fatalf("%s: generated skill must validate cleanly, got %v", tpl.Name, issues)
+		}
+		if rel != filepath.Join("skills", name) {
+			t.Fatalf("%s: unexpected path %q", tpl.Name, rel)
+		}
+
+		meta, err := skillmeta.ParseDir(filepath.Join(root, rel))
+		if err != nil {
+			t.Fatalf("%s: %v", tpl.Name, err)
+		}
+		if meta.Name != name || meta.Description != strings.TrimSpace(description) {
+			t.Fatalf("%s: unexpected frontmatter %+v", tpl.Name, meta)
+		}
+		if strings.Join(meta.Tags, ",") != "go,review" {
+			t.Fatalf("%s: tags must be normalized, got %v", tpl.Name, meta.Tags)
+		}
+		matches, _ := filepath.Glob(filepath.Join(root, rel, "*", "*.tmpl"))
+		if len(matches) > 0 {
+			t.Fatalf("%s: template suffixes must be dropped: %v", tpl.Name, matches)
+		}
+	}
+
+	info, err := os.Stat(filepath.Join(root, "skills", "demo-scripts", "scripts", "run.sh"))
+	if err != nil || info.Mode()&0100 == 0 {
+		t.Fatalf("scripts must be executable: %v, %v", info, err)
+	}
+
+	local, err := skills.ScanLocalUnmanaged(nil, "skills")
+	if err != nil || len(local) != len(BuiltIn()) {
+		t.Fatalf("new skills must show up as local skills, got %+v (%v)", local, err)
+	}
+	if !local[0].HasTag("review") {
+		t.Fatalf("local skills must carry the tags, got %+v", local[0])
+	}
+}
+
+func TestCreateRejectsBadInput(t *testing.T) {
+	project.SetRootForTest(t, t.TempDir())
+	basic, _ := (&Catalog{Templates: BuiltIn()}).Find("")
+
+	cases := []Options{
+		{Name: "My Skill", Description: "x"},
+		{Name: "demo", Description: "  "},
+	}
+	for _, opts := range cases {
+		if _, _, err := Create(basic, "skills", opts); err == nil {
+			t.Fatalf("expected an error for %+v", opts)
+		}
+	}
+
+	if _, _, err := Create(basic, "skills", Options{Name: "demo", Description: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Create(basic, "skills", Options{Name: "demo", Description: "x"}); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected an error for an existing skill, got %v", err)
+	}
+}
+
+func TestCatalogLoadsTemplateRepos(t *testing.T) {
+	root := t.TempDir()
+	project.SetRootForTest(t, root)
+	repo := t.TempDir()
+	tplDir := filepath.Join(repo, "skills", "basic")
+	if err := os.MkdirAll(filepath.Join(tplDir, "examples"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(tplDir, "SKILL.md"), []byte("---\nname: basic\ndescription: Team template\nlicense: MIT\n---\n# Team conventions\n"), 0644)
+	os.WriteFile(filepath.Join(tplDir, "examples", "notes.md"), []byte("{{ not a template }}\n"), 0644)
+
+	prev := cloneAndScanFn
+	t.Cleanup(func() { cloneAndScanFn = prev })
+	cloneAndScanFn = func(url, _ string) (gitrepo.ScanResult, error) {
+		if url != "https://example.com/team/templates.git" {
+			return gitrepo.ScanResult{}, errors.New("not found")
+		}
+		return gitrepo.ScanResult{TempDir: repo, SkillsPath: "skills", Skills: []gitrepo.SkillInfo{{Name: "basic", Description: "Team template", Path: "basic"}}}, nil
+	}
+
+	catalog := LoadCatalog([]string{"https://example.com/team/templates.git", "https://example.com/missing.git"})
+	if len(catalog.Warnings) != 1 || len(catalog.Templates) != len(BuiltIn()) {
+		t.Fatalf("unexpected catalog: %+v", catalog)
+	}
+	tpl, err := catalog.Find("basic")
+	if err != nil || tpl.Source == "" {
+		t.Fatalf("repo templates must replace built-ins with the same name, got %+v (%v)", tpl, err)
+	}
+
+	rel, issues, err := Create(tpl, "skills", Options{Name: "team-skill", Description: "Team skill", Tags: []string{"team"}})
+	if err != nil || len(issues) > 0 {
+		t.Fatalf("Create: %v %v", issues, err)
+	}
+	meta, err := skillmeta.ParseDir(filepath.Join(root, rel))
+	if err != nil || meta.Name != "team-skill" || meta.License != "MIT" || !meta.HasTag("team") {
+		t.Fatalf("frontmatter must be filled keeping the template keys: %+v (%v)", meta, err)
+	}
+	notes, _ := os.ReadFile(filepath.Join(root, rel, "examples", "notes.md"))
+	if string(notes) != "{{ not a template }}\n" {
+		t.Fatalf("non .tmpl files must be copied verbatim, got %q", notes)
+	}
+
+	if _, err := catalog.Find("nope"); err == nil {
+		t.Fatal("expected an error for an unknown template")
+	}
+}
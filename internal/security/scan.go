@@ -0,0 +1,214 @@
+package security
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// maxScanSize limita el contenido de texto que se analiza por fichero
+const maxScanSize = 1 << 20
+
+// scriptExts son extensiones de scripts y ejecutables que un agente podría lanzar
+var scriptExts = map[string]bool{
+	".sh": true, ".bash": true, ".zsh": true, ".fish": true, ".ksh": true,
+	".ps1": true, ".psm1": true, ".bat": true, ".cmd": true, ".vbs": true,
+	".exe": true, ".dll": true, ".so": true, ".dylib": true, ".bin": true,
+}
+
+// binaryMagic son las cabeceras de ejecutables ELF, PE (Windows) y Mach-O
+var binaryMagic = [][]byte{
+	[]byte("\x7fELF"), []byte("MZ"),
+	{0xfe, 0xed, 0xfa, 0xce}, {0xfe, 0xed, 0xfa, 0xcf}, {0xce, 0xfa, 0xed, 0xfe}, {0xcf, 0xfa, 0xed, 0xfe}, {0xca, 0xfe, 0xba, 0xbe},
+}
+
+var (
+	urlPattern = regexp.MustCompile(`(?i)\bhttps?://[^\s<>"'()\[\]{}` + "`" + `]+`)
+
+	// Bloques largos en base64 (se exigen mayúsculas y minúsculas para no confundirlos con hashes)
+	base64Pattern = regexp.MustCompile(`[A-Za-z0-9+/]{160,}={0,2}`)
+	escapePattern = regexp.MustCompile(`(?:\\x[0-9A-Fa-f]{2}){8,}|(?:\\u[0-9A-Fa-f]{4}){6,}`)
+	decodePattern = regexp.MustCompile(`(?i)\bbase64\s+(?:-d|--decode)\b|\batob\(|\bfromCharCode\(`)
+
+	injectionPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(?:ignore|disregard|forget|override)\s+(?:all\s+|any\s+)?(?:the\s+|your\s+)?(?:previous|prior|above|earlier|preceding|system)\s+(?:instructions|prompts?|rules|messages|guidelines)`),
+		regexp.MustCompile(`(?i)\b(?:do\s+not|don't|never)\s+(?:tell|inform|mention\s+(?:this\s+)?to|reveal\s+(?:this\s+)?to|let)\s+the\s+user\b`),
+		regexp.MustCompile(`(?i)\bwithout\s+(?:telling|asking|informing|notifying)\s+the\s+user\b`),
+		regexp.MustCompile(`(?i)\b(?:reveal|print|show|output|leak)\s+(?:your|the)\s+system\s+prompt\b`),
+		regexp.MustCompile(`(?i)\byou\s+are\s+now\s+(?:in\s+)?(?:developer|dan|jailbreak|unrestricted|god)\b`),
+		regexp.MustCompile(`(?i)^\s*(?:new|updated)\s+(?:system\s+)?instructions\s*:`),
+	}
+)
+
+// invisible indica caracteres que no se ven al revisar el texto: de anchura cero,
+// de control de dirección (bidi) y etiquetas Unicode
+func invisible(r rune) bool {
+	switch {
+	case r >= 0x200b && r <= 0x200f, r >= 0x202a && r <= 0x202e, r >= 0x2060 && r <= 0x2064,
+		r >= 0x2066 && r <= 0x2069, r >= 0xe0000 && r <= 0xe007f:
+		return true
+	}
+	return r == 0xfeff
+}
+
+// Scan analiza los ficheros del skill en dir (carpeta o fichero convertido). repoURL es el
+// remoto del que viene: las URLs a su host no se avisan. Las reglas ignoradas en la config no se comprueban.
+func Scan(dir, repoURL string) ([]Finding, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	root := dir
+	if !info.IsDir() {
+		root = filepath.Dir(dir)
+	}
+	host := repoHost(repoURL)
+
+	var findings []Finding
+	add := func(f Finding) {
+		if !current.ignored[f.Rule] {
+			findings = append(findings, f)
+		}
+	}
+
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == dir && d.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, p)
+		rel = filepath.ToSlash(rel)
+
+		if strings.HasPrefix(d.Name(), ".") {
+			kind := "hidden file"
+			if d.IsDir() {
+				kind = "hidden folder"
+			}
+			add(Finding{Rule: "hidden-file", Path: rel, Message: kind + ", easy to miss when reviewing the skill"})
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		content, err := readHead(p, maxScanSize)
+		if err != nil {
+			return err
+		}
+		if msg := executable(p, info.Mode(), content); msg != "" {
+			add(Finding{Rule: "executable", Path: rel, Message: msg})
+		}
+		if bytes.IndexByte(content[:min(len(content), 8000)], 0) >= 0 {
+			return nil // Binario: solo interesa si es ejecutable
+		}
+		for _, f := range scanText(rel, string(content), host) {
+			add(f)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error scanning skill: %w", err)
+	}
+	return findings, nil
+}
+
+// executable describe por qué el fichero se puede ejecutar; vacío si no lo parece
+func executable(path string, mode fs.FileMode, content []byte) string {
+	for _, magic := range binaryMagic {
+		// "MZ" es muy corto: solo cuenta en ficheros binarios
+		if bytes.HasPrefix(content, magic) && (len(magic) > 2 || bytes.IndexByte(content[:min(len(content), 8000)], 0) >= 0) {
+			return "compiled executable"
+		}
+	}
+	if bytes.HasPrefix(content, []byte("#!")) {
+		line, _, _ := bytes.Cut(content, []byte("\n"))
+		return fmt.Sprintf("script with interpreter %s", strings.TrimSpace(string(line[2:])))
+	}
+	if ext := strings.ToLower(filepath.Ext(path)); scriptExts[ext] {
+		return fmt.Sprintf("%s script or executable", ext)
+	}
+	if mode&0111 != 0 {
+		return "file has the executable bit"
+	}
+	return ""
+}
+
+// scanText busca en cada línea contenido ofuscado, frases de inyección de prompt y URLs a hosts desconocidos
+func scanText(rel, content, repoHost string) []Finding {
+	var findings []Finding
+	hosts := make(map[string]bool) // Cada host desconocido se avisa una vez por fichero
+	for i, line := range strings.Split(content, "\n") {
+		n := i + 1
+		if msg := obfuscated(line); msg != "" {
+			findings = append(findings, Finding{Rule: "obfuscation", Path: rel, Line: n, Message: msg})
+		}
+		for _, pattern := range injectionPatterns {
+			if m := pattern.FindString(line); m != "" {
+				findings = append(findings, Finding{Rule: "prompt-injection", Path: rel, Line: n,
+					Message: fmt.Sprintf("instruction-like phrase %q", strings.TrimSpace(m))})
+				break
+			}
+		}
+		for _, raw := range urlPattern.FindAllString(line, -1) {
+			u, err := url.Parse(strings.TrimRight(raw, ".,;:!?"))
+			if err != nil || u.Hostname() == "" {
+				continue
+			}
+			host := normalizeHost(u.Hostname())
+			if hosts[host] || allowedHost(host, repoHost) {
+				continue
+			}
+			hosts[host] = true
+			findings = append(findings, Finding{Rule: "unknown-host", Path: rel, Line: n,
+				Message: fmt.Sprintf("URL to unknown host %s", host)})
+		}
+	}
+	return findings
+}
+
+// obfuscated describe el contenido ofuscado de la línea; vacío si no lo hay
+func obfuscated(line string) string {
+	if strings.IndexFunc(line, invisible) >= 0 {
+		return "invisible Unicode characters"
+	}
+	for _, m := range base64Pattern.FindAllString(line, -1) {
+		if strings.ContainsAny(m, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") && strings.ContainsAny(m, "abcdefghijklmnopqrstuvwxyz") {
+			return fmt.Sprintf("encoded blob of %d characters", len(m))
+		}
+	}
+	if escapePattern.MatchString(line) {
+		return "long sequence of escaped characters"
+	}
+	if m := decodePattern.FindString(line); m != "" {
+		return fmt.Sprintf("decodes hidden content (%s)", strings.TrimSpace(m))
+	}
+	return ""
+}
+
+// readHead lee hasta limit bytes del fichero
+func readHead(path string, limit int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(io.LimitReader(f, limit)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
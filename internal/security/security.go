@@ -0,0 +1,183 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"skli/internal/config"
+)
+
+// Policy indica qué hacer con un skill que tiene hallazgos al instalarlo o sincronizarlo
+type Policy string
+
+const (
+	PolicyWarn    Policy = "warn"    // Instalar y mostrar los hallazgos (por defecto)
+	PolicyConfirm Policy = "confirm" // Instalar solo si el usuario los acepta
+	PolicyBlock   Policy = "block"   // No instalar
+)
+
+var (
+	// ErrBlocked indica un skill que la política block no deja instalar
+	ErrBlocked = errors.New("blocked by security policy")
+	// ErrUnconfirmed indica un skill con hallazgos que la política confirm exige aceptar
+	ErrUnconfirmed = errors.New("security findings need confirmation")
+)
+
+// Rule describe una comprobación; el ID es el valor de Finding.Rule
+type Rule struct {
+	ID          string
+	Description string
+}
+
+// Rules son todas las comprobaciones de Scan
+var Rules = []Rule{
+	{"executable", "Executables, shell scripts and files with the executable bit"},
+	{"obfuscation", "Encoded blobs, escape sequences and invisible Unicode characters"},
+	{"prompt-injection", "Phrases that try to override the agent's instructions"},
+	{"unknown-host", "URLs to hosts other than the skill's repo and the allowed ones"},
+	{"hidden-file", "Files and folders whose name starts with a dot"},
+}
+
+// Finding es algo sospechoso encontrado en un skill
+type Finding struct {
+	Rule    string // Identificador de la comprobación (ej: "unknown-host")
+	Path    string // Fichero relativo al skill con "/"
+	Line    int    // Línea del fichero (desde 1); 0 si afecta al fichero entero
+	Message string
+}
+
+func (f Finding) String() string {
+	if f.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", f.Path, f.Line, f.Message)
+	}
+	return fmt.Sprintf("%s: %s", f.Path, f.Message)
+}
+
+// defaultHosts son hosts de forjas y documentación habituales en los skills.
+// Cuentan también sus subdominios.
+var defaultHosts = []string{
+	"github.com", "githubusercontent.com", "gitlab.com", "bitbucket.org", "codeberg.org",
+	"gitea.com", "dev.azure.com", "example.com", "example.org", "example.net", "localhost",
+}
+
+type settings struct {
+	policy  Policy
+	hosts   []string
+	ignored map[string]bool
+}
+
+var current = settings{policy: PolicyWarn, hosts: defaultHosts}
+
+// Configure aplica la sección [security] de la config. Con la configuración vacía se
+// vuelve a la política warn y a los hosts por defecto.
+func Configure(cfg config.SecurityConfig) error {
+	policy, err := ParsePolicy(cfg.Policy)
+	if err != nil {
+		return err
+	}
+	ignored := make(map[string]bool, len(cfg.Ignore))
+	for _, id := range cfg.Ignore {
+		id = strings.ToLower(strings.TrimSpace(id))
+		if !knownRule(id) {
+			return fmt.Errorf("unknown rule '%s' in ignore", id)
+		}
+		ignored[id] = true
+	}
+	hosts := append([]string(nil), defaultHosts...)
+	for _, host := range cfg.AllowedHosts {
+		hosts = append(hosts, normalizeHost(host))
+	}
+
+	current = settings{policy: policy, hosts: hosts, ignored: ignored}
+	return nil
+}
+
+// ParsePolicy interpreta el nombre de una política; vacío es warn
+func ParsePolicy(name string) (Policy, error) {
+	switch p := Policy(strings.ToLower(strings.TrimSpace(name))); p {
+	case "":
+		return PolicyWarn, nil
+	case PolicyWarn, PolicyConfirm, PolicyBlock:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown security policy '%s' (use warn, confirm or block)", name)
+}
+
+// CurrentPolicy devuelve la política configurada
+func CurrentPolicy() Policy {
+	return current.policy
+}
+
+// Enforce aplica la política a los hallazgos de un skill. confirmed indica que el usuario
+// ya los aceptó, lo que solo cambia el resultado con la política confirm.
+func Enforce(findings []Finding, confirmed bool) error {
+	if len(findings) == 0 {
+		return nil
+	}
+	switch current.policy {
+	case PolicyBlock:
+		return fmt.Errorf("%w: %s", ErrBlocked, Summary(findings))
+	case PolicyConfirm:
+		if !confirmed {
+			return fmt.Errorf("%w: %s", ErrUnconfirmed, Summary(findings))
+		}
+	}
+	return nil
+}
+
+// Summary resume los hallazgos en una línea: cuántos hay y de qué reglas
+func Summary(findings []Finding) string {
+	seen := make(map[string]bool)
+	var rules []string
+	for _, f := range findings {
+		if !seen[f.Rule] {
+			seen[f.Rule] = true
+			rules = append(rules, f.Rule)
+		}
+	}
+	sort.Strings(rules)
+	noun := "findings"
+	if len(findings) == 1 {
+		noun = "finding"
+	}
+	return fmt.Sprintf("%d %s (%s)", len(findings), noun, strings.Join(rules, ", "))
+}
+
+// allowedHost indica si host es uno de los permitidos, el del repo o un subdominio suyo
+func allowedHost(host, repoHost string) bool {
+	for _, allowed := range append(current.hosts, repoHost) {
+		if allowed != "" && (host == allowed || strings.HasSuffix(host, "."+allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// repoHost devuelve el host de una URL de repo, incluida la sintaxis scp (git@host:owner/repo)
+func repoHost(repoURL string) string {
+	if u, err := url.Parse(repoURL); err == nil && u.Host != "" {
+		return normalizeHost(u.Hostname())
+	}
+	if at := strings.Index(repoURL, "@"); at >= 0 {
+		if host, _, ok := strings.Cut(repoURL[at+1:], ":"); ok {
+			return normalizeHost(host)
+		}
+	}
+	return ""
+}
+
+func normalizeHost(host string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(host)), "www.")
+}
+
+func knownRule(id string) bool {
+	for _, r := range Rules {
+		if r.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -2,75 +2,489 @@ package skillmeta
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
-// Metadata contiene campos relevantes del frontmatter de SKILL.md.
+// Metadata contiene el frontmatter de SKILL.md.
 type Metadata struct {
 	Name        string
 	Description string
+	Fields
+	Extra map[string]any // Claves no estándar del frontmatter tal como las decodifica YAML
+}
+
+// Fields son los campos opcionales estándar del frontmatter. Se guardan tal cual en skli.lock.
+type Fields struct {
+	Version    string   `toml:"version,omitempty"`
+	Tags       []string `toml:"tags,omitempty"` // En minúsculas
+	Author     string   `toml:"author,omitempty"`
+	License    string   `toml:"license,omitempty"`
+	Editors    []string `toml:"editors,omitempty"`    // Editores compatibles, en minúsculas; vacío = todos
+	Deprecated string   `toml:"deprecated,omitempty"` // Aviso del autor, o "deprecated" si solo se marcó con true
+	Homepage   string   `toml:"homepage,omitempty"`
+
+	Variables []Variable `toml:"variables,omitempty"` // Valores que se piden al instalar el skill
+}
+
+// Variable es un valor que el usuario da al instalar el skill y que sustituye a los
+// marcadores {{ skli.<name> }} de sus ficheros.
+type Variable struct {
+	Name        string `toml:"name"`
+	Description string `toml:"description,omitempty"`
+	Default     string `toml:"default,omitempty"`
+	Required    bool   `toml:"required,omitempty"` // Sin default: hay que darle valor para instalar
+}
+
+// SupportsEditor indica si el skill declara compatibilidad con el editor (por id o nombre).
+// Los skills sin lista de editores se consideran compatibles con todos.
+func (f Fields) SupportsEditor(editor string) bool {
+	if len(f.Editors) == 0 || editor == "" {
+		return true
+	}
+	editor = strings.ToLower(strings.TrimSpace(editor))
+	for _, e := range f.Editors {
+		if e == editor {
+			return true
+		}
+	}
+	return false
+}
+
+// HasTag indica si el skill tiene la etiqueta (sin distinguir mayúsculas)
+func (f Fields) HasTag(tag string) bool {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	for _, t := range f.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseError es un error del frontmatter con la línea del fichero en la que ocurre.
+type ParseError struct {
+	File string // Vacío si se parseó desde un reader
+	Line int    // Línea de SKILL.md (desde 1); 0 si YAML no la indica
+	Msg  string
 }
 
-// ParseFile lee metadata desde un SKILL.md.
-func ParseFile(skillFile string, maxLines int) (Metadata, error) {
-	if maxLines <= 0 {
-		maxLines = 40
+func (e *ParseError) Error() string {
+	loc := e.File
+	if e.Line > 0 {
+		if loc != "" {
+			loc += ":"
+		}
+		loc += "line " + strconv.Itoa(e.Line)
+	}
+	if loc == "" {
+		return e.Msg
 	}
+	return loc + ": " + e.Msg
+}
 
+// ParseFile lee el frontmatter de un SKILL.md.
+func ParseFile(skillFile string) (Metadata, error) {
 	f, err := os.Open(skillFile)
 	if err != nil {
 		return Metadata{}, err
 	}
 	defer f.Close()
 
-	meta := Metadata{}
-	inFrontmatter := false
-	lineCount := 0
+	meta, err := Parse(f)
+	var perr *ParseError
+	if errors.As(err, &perr) {
+		perr.File = skillFile
+	}
+	return meta, err
+}
 
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		lineCount++
-		line := strings.TrimSpace(scanner.Text())
+// ParseDir lee metadata desde <skillDir>/SKILL.md y usa el nombre de carpeta como fallback.
+func ParseDir(skillDir string) (Metadata, error) {
+	meta, err := ParseFile(filepath.Join(skillDir, "SKILL.md"))
+	if err != nil {
+		return Metadata{}, err
+	}
+	if meta.Name == "" {
+		meta.Name = filepath.Base(skillDir)
+	}
+	return meta, nil
+}
 
-		if line == "---" {
-			if !inFrontmatter {
-				inFrontmatter = true
-				continue
+// Parse lee el bloque de frontmatter (entre dos líneas "---" al principio) y lo decodifica como YAML.
+// Un fichero sin frontmatter devuelve Metadata vacío.
+func Parse(r io.Reader) (Metadata, error) {
+	block, start, err := frontmatter(r)
+	if err != nil || block == nil {
+		return Metadata{}, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(block, &doc); err != nil {
+		return Metadata{}, yamlError(err, start)
+	}
+	if len(doc.Content) == 0 {
+		return Metadata{}, nil // Frontmatter vacío
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return Metadata{}, &ParseError{Line: root.Line + start, Msg: "frontmatter must be a mapping of key: value pairs"}
+	}
+
+	meta := Metadata{Extra: make(map[string]any)}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		var err error
+		switch key.Value {
+		case "name":
+			meta.Name, err = scalar(key.Value, value)
+		case "description":
+			meta.Description, err = scalar(key.Value, value)
+		case "version":
+			meta.Version, err = scalar(key.Value, value)
+		case "license":
+			meta.License, err = scalar(key.Value, value)
+		case "homepage":
+			meta.Homepage, err = scalar(key.Value, value)
+		case "author":
+			meta.Author, err = author(value)
+		case "tags":
+			meta.Tags, err = list(key.Value, value)
+		case "editors":
+			meta.Editors, err = list(key.Value, value)
+		case "deprecated":
+			meta.Deprecated, err = deprecated(value)
+		case "variables":
+			meta.Variables, err = variables(value)
+		default:
+			var v any
+			if err := value.Decode(&v); err != nil {
+				return Metadata{}, yamlError(err, start)
 			}
-			break
+			meta.Extra[key.Value] = v
 		}
-
-		if inFrontmatter {
-			if strings.HasPrefix(line, "name:") {
-				meta.Name = strings.TrimSpace(strings.TrimPrefix(line, "name:"))
+		if err != nil {
+			var perr *ParseError
+			if errors.As(err, &perr) {
+				perr.Line += start
 			}
-			if strings.HasPrefix(line, "description:") {
-				meta.Description = strings.TrimSpace(strings.TrimPrefix(line, "description:"))
+			return Metadata{}, err
+		}
+	}
+	return meta, nil
+}
+
+func scalar(key string, node *yaml.Node) (string, error) {
+	if node.Kind != yaml.ScalarNode {
+		return "", &ParseError{Line: node.Line, Msg: fmt.Sprintf("'%s' must be a string", key)}
+	}
+	return strings.TrimSpace(node.Value), nil
+}
+
+// list acepta una lista YAML o una cadena separada por comas y normaliza a minúsculas
+func list(key string, node *yaml.Node) ([]string, error) {
+	var items []string
+	switch node.Kind {
+	case yaml.ScalarNode:
+		items = strings.Split(node.Value, ",")
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return nil, &ParseError{Line: item.Line, Msg: fmt.Sprintf("'%s' must be a list of strings", key)}
 			}
+			items = append(items, item.Value)
 		}
+	default:
+		return nil, &ParseError{Line: node.Line, Msg: fmt.Sprintf("'%s' must be a list of strings", key)}
+	}
 
-		if lineCount >= maxLines {
-			break
+	var out []string
+	for _, item := range items {
+		if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+// author acepta una cadena o un mapa con name y email
+func author(node *yaml.Node) (string, error) {
+	if node.Kind != yaml.MappingNode {
+		return scalar("author", node)
+	}
+	var a struct{ Name, Email string }
+	if err := node.Decode(&a); err != nil {
+		return "", &ParseError{Line: node.Line, Msg: "'author' must be a string or a mapping with name and email"}
+	}
+	if a.Email == "" {
+		return a.Name, nil
+	}
+	return strings.TrimSpace(a.Name + " <" + a.Email + ">"), nil
+}
+
+// deprecated acepta un booleano o el aviso para los usuarios
+func deprecated(node *yaml.Node) (string, error) {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!bool" {
+		var b bool
+		if err := node.Decode(&b); err != nil || !b {
+			return "", nil
+		}
+		return "deprecated", nil
+	}
+	return scalar("deprecated", node)
+}
+
+// variableName son los nombres válidos de variable, usables en los marcadores
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// variables acepta una lista de nombres o de mapas con name, description y default.
+// Las variables sin default son obligatorias.
+func variables(node *yaml.Node) ([]Variable, error) {
+	if node.Kind != yaml.SequenceNode {
+		return nil, &ParseError{Line: node.Line, Msg: "'variables' must be a list"}
+	}
+	var out []Variable
+	seen := make(map[string]bool)
+	for _, item := range node.Content {
+		var v Variable
+		switch item.Kind {
+		case yaml.ScalarNode:
+			v = Variable{Name: strings.TrimSpace(item.Value), Required: true}
+		case yaml.MappingNode:
+			var raw struct {
+				Name        string  `yaml:"name"`
+				Description string  `yaml:"description"`
+				Default     *string `yaml:"default"`
+			}
+			if err := item.Decode(&raw); err != nil {
+				return nil, &ParseError{Line: item.Line, Msg: "each variable must have a name and optional description and default strings"}
+			}
+			v = Variable{Name: strings.TrimSpace(raw.Name), Description: strings.TrimSpace(raw.Description), Required: raw.Default == nil}
+			if raw.Default != nil {
+				v.Default = *raw.Default
+			}
+		default:
+			return nil, &ParseError{Line: item.Line, Msg: "each variable must be a name or a mapping"}
+		}
+		if !variableName.MatchString(v.Name) {
+			return nil, &ParseError{Line: item.Line, Msg: fmt.Sprintf("invalid variable name '%s': use letters, digits and underscores", v.Name)}
+		}
+		if seen[v.Name] {
+			return nil, &ParseError{Line: item.Line, Msg: fmt.Sprintf("variable '%s' is declared twice", v.Name)}
 		}
+		seen[v.Name] = true
+		out = append(out, v)
 	}
+	return out, nil
+}
+
+// frontmatter devuelve el contenido entre los dos "---" y el número de líneas que lo preceden
+// en el fichero, para traducir las líneas de YAML a líneas de SKILL.md.
+func frontmatter(r io.Reader) ([]byte, int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
 
+	line, openLine := 0, 0
+	opened := false
+	var block bytes.Buffer
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if strings.TrimSpace(text) == "---" {
+			if !opened {
+				opened, openLine = true, line
+				continue
+			}
+			// Un frontmatter vacío ("---" seguido de "---") devuelve un bloque vacío, no nil
+			return append([]byte{}, block.Bytes()...), openLine, nil
+		}
+		if !opened {
+			if strings.TrimSpace(text) == "" {
+				continue // Líneas en blanco antes del frontmatter
+			}
+			return nil, 0, nil
+		}
+		block.WriteString(text)
+		block.WriteByte('\n')
+	}
 	if err := scanner.Err(); err != nil {
-		return Metadata{}, err
+		return nil, 0, err
+	}
+	if opened {
+		return nil, 0, &ParseError{Line: line, Msg: "frontmatter is not closed with '---'"}
 	}
+	return nil, 0, nil
+}
 
-	return meta, nil
+// HasFrontmatter indica si skillFile abre un bloque de frontmatter, con la misma regla que Parse:
+// se ignoran las líneas en blanco y el BOM del principio. Un bloque sin cerrar cuenta como
+// frontmatter para que Parse informe del error.
+func HasFrontmatter(skillFile string) bool {
+	f, err := os.Open(skillFile)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	block, _, err := frontmatter(f)
+	var perr *ParseError
+	return block != nil || errors.As(err, &perr)
 }
 
-// ParseDir lee metadata desde <skillDir>/SKILL.md y usa el nombre de carpeta como fallback.
-func ParseDir(skillDir string, maxLines int) (Metadata, error) {
-	meta, err := ParseFile(filepath.Join(skillDir, "SKILL.md"), maxLines)
+// SplitFrontmatter separa data con la misma regla que Parse en: lo que precede al bloque (incluida la
+// línea "---" que lo abre), el YAML del bloque y el resto desde la línea "---" que lo cierra.
+// ok es false si data no tiene un frontmatter cerrado.
+func SplitFrontmatter(data []byte) (head, block, rest []byte, ok bool) {
+	opened := false
+	start := 0
+	for offset := 0; offset < len(data); {
+		next := len(data)
+		if i := bytes.IndexByte(data[offset:], '\n'); i >= 0 {
+			next = offset + i + 1
+		}
+		line := string(data[offset:next])
+		if offset == 0 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		switch line = strings.TrimSpace(line); {
+		case line == "---" && !opened:
+			opened, start = true, next
+		case line == "---":
+			return data[:start], data[start:offset], data[offset:], true
+		case !opened && line != "":
+			return nil, nil, nil, false
+		}
+		offset = next
+	}
+	return nil, nil, nil, false
+}
+
+// Key es una clave del frontmatter con el valor a escribir
+type Key struct {
+	Name  string
+	Value any
+}
+
+// SetKeys reescribe las claves indicadas del frontmatter de skillFile (añadiéndolo si no tiene)
+// y conserva el resto de claves, su orden, el cuerpo del fichero y sus permisos.
+func SetKeys(skillFile string, keys ...Key) error {
+	info, err := os.Stat(skillFile)
 	if err != nil {
-		return Metadata{}, err
+		return err
 	}
-	if meta.Name == "" {
-		meta.Name = filepath.Base(skillDir)
+	data, err := os.ReadFile(skillFile)
+	if err != nil {
+		return err
 	}
-	return meta, nil
+	block, start, err := frontmatter(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	body := data
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	if block != nil {
+		var doc yaml.Node
+		if err := yaml.Unmarshal(block, &doc); err != nil {
+			perr := yamlError(err, start).(*ParseError)
+			perr.File = skillFile
+			return perr
+		}
+		if len(doc.Content) > 0 {
+			if doc.Content[0].Kind != yaml.MappingNode {
+				return &ParseError{File: skillFile, Line: start + 1, Msg: "frontmatter must be a mapping of key: value pairs"}
+			}
+			root = doc.Content[0]
+		}
+		body = afterFrontmatter(data)
+	}
+
+	for _, key := range keys {
+		var node yaml.Node
+		if err := node.Encode(key.Value); err != nil {
+			return fmt.Errorf("error encoding '%s': %w", key.Name, err)
+		}
+		if node.Kind == yaml.SequenceNode {
+			node.Style = yaml.FlowStyle
+		}
+		setKey(root, key.Name, &node)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("---\n")
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return fmt.Errorf("error encoding frontmatter: %w", err)
+	}
+	enc.Close()
+	out.WriteString("---\n")
+	out.Write(body)
+	return os.WriteFile(skillFile, out.Bytes(), info.Mode().Perm())
+}
+
+func setKey(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
+
+// Body devuelve el contenido de un SKILL.md sin el frontmatter
+func Body(data []byte) []byte {
+	block, _, err := frontmatter(bytes.NewReader(data))
+	if err != nil || block == nil {
+		return data
+	}
+	return afterFrontmatter(data)
+}
+
+// afterFrontmatter devuelve el contenido que sigue a la línea "---" que cierra el frontmatter
+func afterFrontmatter(data []byte) []byte {
+	delimiters := 0
+	for len(data) > 0 {
+		line := data
+		rest := []byte(nil)
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			line, rest = data[:i], data[i+1:]
+		}
+		if strings.TrimSpace(strings.TrimPrefix(string(line), "\ufeff")) == "---" {
+			if delimiters++; delimiters == 2 {
+				return rest
+			}
+		}
+		data = rest
+	}
+	return nil
+}
+
+var yamlLinePattern = regexp.MustCompile(`line (\d+): `)
+
+// yamlError convierte un error de yaml.v3 ("yaml: line 3: ...") en un ParseError con la línea del fichero
+func yamlError(err error, offset int) error {
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	if te, ok := err.(*yaml.TypeError); ok && len(te.Errors) > 0 {
+		msg = te.Errors[0]
+	}
+	m := yamlLinePattern.FindStringSubmatchIndex(msg)
+	if m == nil {
+		return &ParseError{Msg: msg}
+	}
+	n, _ := strconv.Atoi(msg[m[2]:m[3]])
+	return &ParseError{Line: n + offset, Msg: msg[:m[0]] + msg[m[1]:]}
 }
@@ -0,0 +1,328 @@
+package skillmeta
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMultilineDescriptions(t *testing.T) {
+	input := `---
+name: "quoted-name"
+description: >
+  Folded description
+  across lines.
+allowed-tools:
+  - Read
+  - Bash
+---
+# Body
+`
+	meta, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if meta.Name != "quoted-name" {
+		t.Fatalf("unexpected name %q", meta.Name)
+	}
+	if meta.Description != "Folded description across lines." {
+		t.Fatalf("unexpected description %q", meta.Description)
+	}
+	tools, ok := meta.Extra["allowed-tools"].([]any)
+	if !ok || len(tools) != 2 || tools[0] != "Read" {
+		t.Fatalf("unknown keys must be kept, got %#v", meta.Extra)
+	}
+}
+
+func TestParseLiteralAndQuotedMultiline(t *testing.T) {
+	meta, err := Parse(strings.NewReader("---\nname: demo\ndescription: |\n  first\n  second\n---\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Description != "first\nsecond" {
+		t.Fatalf("unexpected literal description %q", meta.Description)
+	}
+
+	meta, err = Parse(strings.NewReader("---\nname: demo\ndescription: \"one\n  two\"\n---\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Description != "one two" {
+		t.Fatalf("unexpected quoted description %q", meta.Description)
+	}
+}
+
+func TestParseHasNoLineLimit(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("---\n")
+	for i := 0; i < 100; i++ {
+		b.WriteString("# comment\n")
+	}
+	b.WriteString("name: late\ndescription: after many lines\n---\n")
+
+	meta, err := Parse(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Name != "late" || meta.Description != "after many lines" {
+		t.Fatalf("fields after line 40 must be read, got %+v", meta)
+	}
+}
+
+func TestParseErrorsReportFileLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		line  int
+	}{
+		{"invalid yaml", "---\nname: demo\ndescription: a: b\n---\n", 3},
+		{"tab indentation", "\n---\nname: demo\n\tdescription: x\n---\n", 4},
+		{"name not a string", "---\nname:\n  - a\n---\n", 3},
+		{"not a mapping", "---\n- a\n- b\n---\n", 2},
+		{"unterminated", "---\nname: demo\n", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("expected a ParseError, got %v", err)
+			}
+			if perr.Line != tt.line {
+				t.Fatalf("expected line %d, got %d (%v)", tt.line, perr.Line, err)
+			}
+		})
+	}
+}
+
+func TestParseWithoutFrontmatter(t *testing.T) {
+	meta, err := Parse(strings.NewReader("# Just markdown\nname: not-metadata\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Name != "" {
+		t.Fatalf("text outside a frontmatter must be ignored, got %+v", meta)
+	}
+}
+
+func TestParseDirFallsBackToFolderName(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "folder-name")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\ndescription: x\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := ParseDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Name != "folder-name" {
+		t.Fatalf("unexpected name %q", meta.Name)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\nname: [\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = ParseDir(dir)
+	if err == nil || !strings.Contains(err.Error(), "SKILL.md:line ") {
+		t.Fatalf("file errors must name the file and line, got %v", err)
+	}
+}
+
+func TestParseStandardFields(t *testing.T) {
+	input := `---
+name: demo
+description: test
+version: 1.2.0
+tags: [Go, testing]
+author:
+  name: Jane Doe
+  email: jane@example.com
+license: MIT
+editors: cursor, VSCode
+deprecated: use other-skill instead
+homepage: https://example.com/demo
+---
+`
+	meta, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := Fields{
+		Version:    "1.2.0",
+		Tags:       []string{"go", "testing"},
+		Author:     "Jane Doe <jane@example.com>",
+		License:    "MIT",
+		Editors:    []string{"cursor", "vscode"},
+		Deprecated: "use other-skill instead",
+		Homepage:   "https://example.com/demo",
+	}
+	if !reflect.DeepEqual(meta.Fields, want) {
+		t.Fatalf("unexpected fields:\n got %+v\nwant %+v", meta.Fields, want)
+	}
+	if len(meta.Extra) != 0 {
+		t.Fatalf("standard fields must not end up in Extra: %+v", meta.Extra)
+	}
+
+	if !meta.SupportsEditor("Cursor") || meta.SupportsEditor("windsurf") {
+		t.Fatalf("unexpected editor compatibility for %v", meta.Editors)
+	}
+	if (Fields{}).SupportsEditor("windsurf") != true {
+		t.Fatalf("skills without editors must support every editor")
+	}
+	if !meta.HasTag("GO") || meta.HasTag("rust") {
+		t.Fatalf("unexpected tag matching for %v", meta.Tags)
+	}
+}
+
+func TestParseDeprecatedFlag(t *testing.T) {
+	meta, err := Parse(strings.NewReader("---\nname: demo\ndeprecated: true\n---\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Deprecated != "deprecated" {
+		t.Fatalf("unexpected deprecated %q", meta.Deprecated)
+	}
+
+	meta, err = Parse(strings.NewReader("---\nname: demo\ndeprecated: false\n---\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Deprecated != "" {
+		t.Fatalf("deprecated: false must leave the field empty, got %q", meta.Deprecated)
+	}
+
+	_, err = Parse(strings.NewReader("---\nname: demo\ntags:\n  - {a: b}\n---\n"))
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Line != 4 {
+		t.Fatalf("expected an error on line 4, got %v", err)
+	}
+}
+
+func TestParseVariables(t *testing.T) {
+	src := `---
+name: demo
+variables:
+  - ticket_prefix
+  - name: cloud_account
+    description: AWS account used for deploys
+    default: ""
+  - {name: channel, default: "#team"}
+---
+`
+	meta, err := Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Variable{
+		{Name: "ticket_prefix", Required: true},
+		{Name: "cloud_account", Description: "AWS account used for deploys"},
+		{Name: "channel", Default: "#team"},
+	}
+	if !reflect.DeepEqual(meta.Variables, want) {
+		t.Fatalf("unexpected variables %+v", meta.Variables)
+	}
+
+	for _, bad := range []string{"variables: team", "variables: [team-name]", "variables: [a, a]"} {
+		_, err := Parse(strings.NewReader("---\nname: demo\n" + bad + "\n---\n"))
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.Line != 3 {
+			t.Fatalf("%s: expected an error on line 3, got %v", bad, err)
+		}
+	}
+}
+
+func TestSetKeysKeepsOtherKeysAndBody(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "SKILL.md")
+	input := "---\nname: template\nlicense: MIT\n---\n# Body\n\n---\nnot frontmatter\n"
+	if err := os.WriteFile(file, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := SetKeys(file,
+		Key{Name: "name", Value: "my-skill"},
+		Key{Name: "description", Value: "Does: things"},
+		Key{Name: "tags", Value: []string{"go", "cli"}},
+	)
+	if err != nil {
+		t.Fatalf("SetKeys: %v", err)
+	}
+
+	data, _ := os.ReadFile(file)
+	want := "---\nname: my-skill\nlicense: MIT\ndescription: 'Does: things'\ntags: [go, cli]\n---\n# Body\n\n---\nnot frontmatter\n"
+	if string(data) != want {
+		t.Fatalf("unexpected file:\n%s\nwant:\n%s", data, want)
+	}
+	meta, err := ParseFile(file)
+	if err != nil || meta.Description != "Does: things" || !meta.HasTag("cli") {
+		t.Fatalf("rewritten file must parse back: %+v, %v", meta, err)
+	}
+}
+
+func TestSetKeysAddsMissingFrontmatter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "SKILL.md")
+	if err := os.WriteFile(file, []byte("# Body\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetKeys(file, Key{Name: "name", Value: "demo"}); err != nil {
+		t.Fatalf("SetKeys: %v", err)
+	}
+	data, _ := os.ReadFile(file)
+	if string(data) != "---\nname: demo\n---\n# Body\n" {
+		t.Fatalf("unexpected file:\n%s", data)
+	}
+}
+
+func TestSetKeysKeepsFileMode(t *testing.T) {
+	for _, mode := range []os.FileMode{0600, 0755} {
+		file := filepath.Join(t.TempDir(), "SKILL.md")
+		if err := os.WriteFile(file, []byte("---\nname: a\n---\n"), mode); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(file, mode); err != nil {
+			t.Fatal(err)
+		}
+		if err := SetKeys(file, Key{Name: "name", Value: "b"}); err != nil {
+			t.Fatalf("SetKeys: %v", err)
+		}
+		if info, _ := os.Stat(file); info.Mode().Perm() != mode {
+			t.Fatalf("expected mode %v, got %v", mode, info.Mode().Perm())
+		}
+	}
+}
+
+func TestHasFrontmatterMatchesParse(t *testing.T) {
+	cases := map[string]bool{
+		"---\nname: a\n---\n":     true,
+		"\n\n---\nname: a\n---\n": true,
+		"\ufeff---\n---\nbody\n":  true,
+		"---\nname: a\n":          true, // Sin cerrar: Parse informa del error
+		"# title\n---\nname: a\n": false,
+		"":                        false,
+	}
+	for content, want := range cases {
+		file := filepath.Join(t.TempDir(), "SKILL.md")
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if got := HasFrontmatter(file); got != want {
+			t.Fatalf("%q: expected %v, got %v", content, want, got)
+		}
+	}
+}
+
+func TestSplitFrontmatter(t *testing.T) {
+	head, block, rest, ok := SplitFrontmatter([]byte("\n---\nname: a\n---\nbody\n"))
+	if !ok || string(head) != "\n---\n" || string(block) != "name: a\n" || string(rest) != "---\nbody\n" {
+		t.Fatalf("unexpected split %q %q %q %v", head, block, rest, ok)
+	}
+	for _, content := range []string{"# title\n---\nname: a\n---\n", "---\nname: a\n", ""} {
+		if _, _, _, ok := SplitFrontmatter([]byte(content)); ok {
+			t.Fatalf("%q has no closed frontmatter", content)
+		}
+	}
+}
@@ -3,16 +3,21 @@ package skills
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"skli/internal/db"
+	"skli/internal/gitrepo"
+	"skli/internal/integrity"
+	"skli/internal/project"
 	"skli/internal/skillmeta"
 )
 
 const DefaultRoot = "skills"
 
-// IsSafeDeletePath valida que el path a eliminar sea un subdirectorio del root de skills y esté dentro del workspace.
+// IsSafeDeletePath valida que el path a eliminar sea un subdirectorio del root de skills y esté dentro del proyecto.
+// Las rutas relativas se resuelven contra la raíz del proyecto.
 func IsSafeDeletePath(pathToDelete, skillsRoot string) error {
 	pathToDelete = filepath.Clean(strings.TrimSpace(pathToDelete))
 	if pathToDelete == "" || pathToDelete == "." || pathToDelete == string(os.PathSeparator) {
@@ -24,14 +29,8 @@ func IsSafeDeletePath(pathToDelete, skillsRoot string) error {
 	}
 	skillsRoot = filepath.Clean(skillsRoot)
 
-	absRoot, err := filepath.Abs(skillsRoot)
-	if err != nil {
-		return fmt.Errorf("could not resolve skills root: %w", err)
-	}
-	absTarget, err := filepath.Abs(pathToDelete)
-	if err != nil {
-		return fmt.Errorf("could not resolve path to delete: %w", err)
-	}
+	absRoot := project.Resolve(skillsRoot)
+	absTarget := project.Resolve(pathToDelete)
 
 	rel, err := filepath.Rel(absRoot, absTarget)
 	if err != nil {
@@ -41,15 +40,10 @@ func IsSafeDeletePath(pathToDelete, skillsRoot string) error {
 		return fmt.Errorf("path outside skills root: %s", pathToDelete)
 	}
 
-	// Asegurar que el path esté dentro del workspace actual para evitar ataques de path traversal
-	cwd, err := os.Getwd()
-	if err == nil {
-		if absCwd, err := filepath.Abs(cwd); err == nil {
-			if relCwd, err := filepath.Rel(absCwd, absTarget); err == nil {
-				if relCwd == "." || relCwd == "" || relCwd == ".." || strings.HasPrefix(relCwd, ".."+string(os.PathSeparator)) {
-					return fmt.Errorf("path outside workspace: %s", pathToDelete)
-				}
-			}
+	// Asegurar que el path esté dentro del proyecto para evitar ataques de path traversal
+	if relRoot, err := filepath.Rel(project.Root(), absTarget); err == nil {
+		if relRoot == "." || relRoot == "" || relRoot == ".." || strings.HasPrefix(relRoot, ".."+string(os.PathSeparator)) {
+			return fmt.Errorf("path outside project: %s", pathToDelete)
 		}
 	}
 
@@ -87,22 +81,24 @@ func ScanLocalUnmanaged(existing []db.InstalledSkill, skillsRoot string) ([]db.I
 		existingMap[sk.Path] = true
 	}
 
-	_ = filepath.Walk(skillsRoot, func(path string, info os.FileInfo, err error) error {
+	absRoot := project.Resolve(skillsRoot)
+	_ = filepath.Walk(absRoot, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil
 		}
 		if !info.IsDir() && info.Name() == "SKILL.md" {
 			dir := filepath.Dir(path)
-			rootClean := filepath.Clean(skillsRoot)
-			if filepath.Clean(dir) == rootClean {
+			if filepath.Clean(dir) == absRoot {
 				return nil
 			}
-			relPath, _ := filepath.Rel(".", dir)
+			relPath := project.Rel(dir)
 			if !existingMap[relPath] {
+				meta, _ := skillmeta.ParseFile(path) // Los campos opcionales solo se usan para mostrar y filtrar
 				newSkills = append(newSkills, db.InstalledSkill{
 					Name:        filepath.Base(dir),
 					Description: "Local skill (unmanaged)",
 					Path:        relPath,
+					Fields:      meta.Fields,
 				})
 			}
 		}
@@ -145,6 +141,28 @@ func FindByName(name, skillsRoot string) (db.InstalledSkill, error) {
 	return matches[0], nil
 }
 
+// FindInstalledByName busca por nombre un skill instalado desde un repo remoto.
+func FindInstalledByName(name, skillsRoot string) (db.InstalledSkill, error) {
+	skill, err := FindByName(name, skillsRoot)
+	if err != nil {
+		return db.InstalledSkill{}, err
+	}
+	if skill.RemoteRepo == "" {
+		return db.InstalledSkill{}, fmt.Errorf("skill '%s' is local only; use 'skli upload' instead", skill.Name)
+	}
+	return skill, nil
+}
+
+// IsModified indica si un skill instalado difiere del manifiesto registrado en el lockfile.
+// Sin manifiesto no se puede saber, así que se considera sin cambios.
+func IsModified(skill db.InstalledSkill) bool {
+	if skill.RemoteRepo == "" || skill.Files == nil {
+		return false
+	}
+	drift, err := integrity.Check(project.Resolve(skill.Path), skill.Files)
+	return err == nil && !drift.Clean()
+}
+
 // Delete elimina el directorio del skill y su entrada en el lockfile.
 func Delete(skill db.InstalledSkill, skillsRoot string) error {
 	// Usar el directorio padre del skill como root para la validación de seguridad
@@ -153,7 +171,7 @@ func Delete(skill db.InstalledSkill, skillsRoot string) error {
 		return err
 	}
 
-	if err := os.RemoveAll(skill.Path); err != nil {
+	if err := os.RemoveAll(project.Resolve(skill.Path)); err != nil {
 		return fmt.Errorf("error deleting '%s': %w", skill.Name, err)
 	}
 	if err := db.DeleteInstalledSkill(skill.Path); err != nil {
@@ -191,7 +209,7 @@ func PrepareLocalForUpload(localSkillPath string) (db.InstalledSkill, error) {
 		return db.InstalledSkill{}, fmt.Errorf("invalid path: %s", localSkillPath)
 	}
 
-	meta, err := skillmeta.ParseDir(localSkillPath, 40)
+	meta, err := skillmeta.ParseDir(localSkillPath)
 	if err != nil {
 		return db.InstalledSkill{}, fmt.Errorf("could not read SKILL.md: %w", err)
 	}
@@ -200,5 +218,62 @@ func PrepareLocalForUpload(localSkillPath string) (db.InstalledSkill, error) {
 		Name:        meta.Name,
 		Description: meta.Description,
 		Path:        localSkillPath,
+		Fields:      meta.Fields,
 	}, nil
 }
+
+// RegisterUploads registra en el lockfile los skills locales subidos en result, apuntando al repo destino.
+// Quedan pendientes de la rama de la PR hasta que sync la detecte fusionada en la rama por defecto.
+// Los skills sin cambios no se registran porque la subida no dice dónde están en el repo.
+func RegisterUploads(locals []db.InstalledSkill, remoteURL string, result gitrepo.UploadResult) ([]db.InstalledSkill, error) {
+	byName := make(map[string]db.InstalledSkill, len(locals))
+	for _, sk := range locals {
+		byName[sk.Name] = sk
+	}
+
+	entries := make([]db.InstalledSkill, 0, len(result.Skills))
+	for _, up := range result.Skills {
+		local, ok := byName[up.Name]
+		if !ok {
+			return nil, fmt.Errorf("uploaded skill '%s' not found locally", up.Name)
+		}
+		rel := project.Rel(local.Path)
+		if filepath.IsAbs(rel) {
+			return nil, fmt.Errorf("cannot register '%s': %s is outside the project", up.Name, local.Path)
+		}
+		manifest, err := integrity.Compute(project.Resolve(rel))
+		if err != nil {
+			return nil, fmt.Errorf("error hashing '%s': %w", up.Name, err)
+		}
+		entries = append(entries, db.InstalledSkill{
+			Name:          up.Name,
+			Description:   up.Description,
+			Path:          rel,
+			RemoteRepo:    remoteURL,
+			RemoteRoot:    path.Dir(up.RepoPath),
+			RemotePath:    path.Base(up.RepoPath),
+			TreeHash:      up.TreeHash,
+			Files:         manifest,
+			Fields:        local.Fields,
+			PendingBranch: result.Branch,
+			PendingRepo:   result.Fork,
+			PRURL:         result.PRURL,
+		})
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	err := db.Update(func(lock *db.LockFile) error {
+		for _, e := range entries {
+			// Si la carpeta ya estaba registrada con otro origen, la subida la sustituye
+			lock.RemovePath(e.Path)
+			lock.Upsert(e)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error updating skli.lock: %w", err)
+	}
+	return entries, nil
+}
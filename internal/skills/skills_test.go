@@ -4,6 +4,10 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"skli/internal/db"
+	"skli/internal/gitrepo"
+	"skli/internal/integrity"
 )
 
 func TestIsSafeDeletePath(t *testing.T) {
@@ -40,3 +44,107 @@ func TestIsSafeDeletePath(t *testing.T) {
 		}
 	})
 }
+
+func TestIsModified(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\nname: demo\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := integrity.Compute(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skill := db.InstalledSkill{Name: "demo", Path: dir, RemoteRepo: "https://example.com/repo.git", Files: manifest}
+
+	if IsModified(skill) {
+		t.Fatalf("untouched skill must not be reported as modified")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.md"), []byte("extra"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !IsModified(skill) {
+		t.Fatalf("added file must be reported as modified")
+	}
+
+	skill.Files = nil
+	if IsModified(skill) {
+		t.Fatalf("skill without manifest cannot be reported as modified")
+	}
+}
+
+func TestRegisterUploadsWritesPendingEntry(t *testing.T) {
+	dir := t.TempDir()
+	prev, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(prev) })
+
+	skillDir := filepath.Join(dir, "skills", "demo")
+	if err := os.MkdirAll(skillDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: demo\ndescription: test\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	locals := []db.InstalledSkill{{Name: "demo", Path: skillDir}}
+	result := gitrepo.UploadResult{
+		PRURL:  "https://example.com/pr/1",
+		Fork:   "https://example.com/me/repo.git",
+		Branch: "feat/demo",
+		Skills: []gitrepo.UploadedSkill{{Name: "demo", Description: "test", RepoPath: "team/skills/demo", TreeHash: "abc"}},
+	}
+	if _, err := RegisterUploads(locals, "https://example.com/org/repo.git", result); err != nil {
+		t.Fatalf("RegisterUploads: %v", err)
+	}
+
+	lock, err := db.LoadLockFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lock.Skills) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(lock.Skills))
+	}
+	got := lock.Skills[0]
+	if got.Path != filepath.Join("skills", "demo") || got.RemoteRoot != "team/skills" || got.RemotePath != "demo" {
+		t.Fatalf("unexpected location: %+v", got)
+	}
+	if !got.IsPending() || got.PendingBranch != "feat/demo" || got.PendingRepo != result.Fork || got.PRURL != result.PRURL {
+		t.Fatalf("entry must be pending on the PR branch: %+v", got)
+	}
+	if got.TreeHash != "abc" || got.CommitHash != "" || got.Files == nil {
+		t.Fatalf("unexpected hashes: %+v", got)
+	}
+
+	// Ya gestionado, deja de aparecer como local
+	unmanaged, err := ScanLocalUnmanaged(lock.Skills, DefaultRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unmanaged) != 0 {
+		t.Fatalf("registered skill still reported as unmanaged: %+v", unmanaged)
+	}
+}
+
+func TestRegisterUploadsRejectsSkillsOutsideProject(t *testing.T) {
+	project := t.TempDir()
+	prev, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(project); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(prev) })
+
+	outside := t.TempDir()
+	locals := []db.InstalledSkill{{Name: "demo", Path: outside}}
+	result := gitrepo.UploadResult{Skills: []gitrepo.UploadedSkill{{Name: "demo", RepoPath: "skills/demo"}}}
+	if _, err := RegisterUploads(locals, "https://example.com/org/repo.git", result); err == nil {
+		t.Fatalf("expected error registering a skill outside the project")
+	}
+}
@@ -0,0 +1,180 @@
+package skillvars
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"skli/internal/skillmeta"
+)
+
+// placeholder son los marcadores {{ skli.<variable> }}. El prefijo evita tocar otras
+// sintaxis con llaves dobles (Go, Helm, GitHub Actions...).
+var placeholder = regexp.MustCompile(`\{\{\s*skli\.([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// MissingError indica variables obligatorias sin valor
+type MissingError struct {
+	Names []string
+}
+
+func (e *MissingError) Error() string {
+	return fmt.Sprintf("missing value for %s (use --set name=value)", strings.Join(e.Names, ", "))
+}
+
+// Resolve devuelve el valor de cada variable declarada: el dado, o su default si no lo es.
+// Los valores de variables no declaradas se ignoran.
+func Resolve(decls []skillmeta.Variable, given map[string]string) (map[string]string, error) {
+	if len(decls) == 0 {
+		return nil, nil
+	}
+	values := make(map[string]string, len(decls))
+	var missing []string
+	for _, v := range decls {
+		if value, ok := given[v.Name]; ok {
+			values[v.Name] = value
+		} else if v.Required {
+			missing = append(missing, v.Name)
+		} else {
+			values[v.Name] = v.Default
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &MissingError{Names: missing}
+	}
+	return values, nil
+}
+
+// Render sustituye los marcadores de las variables en los ficheros de texto de path
+// (la carpeta del skill o el artefacto de un conversor). Los marcadores de variables
+// sin valor se dejan tal cual. En el frontmatter de SKILL.md los valores se escapan
+// como cadenas YAML (ver renderFrontmatter).
+func Render(path string, values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if bytes.IndexByte(data, 0) >= 0 || !placeholder.Match(data) {
+			return nil // Binario o sin marcadores
+		}
+
+		var rendered []byte
+		if head, block, rest, ok := skillmeta.SplitFrontmatter(data); ok && d.Name() == "SKILL.md" {
+			block, err = renderFrontmatter(block, values)
+			if err != nil {
+				return fmt.Errorf("%s: %w", p, err)
+			}
+			rendered = append(append(append([]byte{}, head...), block...), replace(rest, values, raw)...)
+		} else {
+			rendered = replace(data, values, raw)
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(p, rendered, info.Mode().Perm())
+	})
+}
+
+// replace sustituye los marcadores con valor pasando cada valor por escape
+func replace(data []byte, values map[string]string, escape func(string) string) []byte {
+	return placeholder.ReplaceAllFunc(data, func(m []byte) []byte {
+		name := string(placeholder.FindSubmatch(m)[1])
+		if value, ok := values[name]; ok {
+			return []byte(escape(value))
+		}
+		return m
+	})
+}
+
+func raw(value string) string { return value }
+
+// frontmatterEntry separa una línea del frontmatter en la clave o el guion de lista y el valor
+var frontmatterEntry = regexp.MustCompile(`^(\s*(?:- +)?(?:[A-Za-z0-9_.-]+: +)?)(.*?)(\s*)$`)
+
+// renderFrontmatter sustituye los marcadores del YAML del frontmatter sin que un valor con ':', '#'
+// o comillas cambie su estructura. Los marcadores solo se admiten como valor de una clave o de un
+// elemento de lista: un valor entre comillas recibe el valor escapado y uno sin comillas pasa a ser
+// una cadena entre comillas dobles.
+func renderFrontmatter(block []byte, values map[string]string) ([]byte, error) {
+	lines := strings.SplitAfter(string(block), "\n")
+	for i, line := range lines {
+		if !placeholder.MatchString(line) {
+			continue
+		}
+		m := frontmatterEntry.FindStringSubmatch(line)
+		prefix, value, suffix := m[1], m[2], m[3]
+		if strings.TrimSpace(prefix) == "" {
+			return nil, fmt.Errorf("frontmatter line %d: placeholders are only allowed in the value of a key or list item", i+1)
+		}
+
+		switch {
+		case len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`):
+			value = string(replace([]byte(value), values, func(v string) string {
+				quoted := strconv.Quote(v)
+				return quoted[1 : len(quoted)-1]
+			}))
+		case len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'"):
+			value = string(replace([]byte(value), values, func(v string) string {
+				return strings.ReplaceAll(v, "'", "''")
+			}))
+		case placeholder.FindStringIndex(value)[0] > 0 && strings.ContainsAny(value[:1], `"'[{|>&*!%@`+"`"):
+			return nil, fmt.Errorf("frontmatter line %d: placeholders are only allowed in plain or quoted values", i+1)
+		default:
+			comment := ""
+			if j := strings.Index(value, " #"); j >= 0 {
+				value, comment = strings.TrimRight(value[:j], " "), value[j:]
+			}
+			value = strconv.Quote(string(replace([]byte(value), values, raw))) + comment
+		}
+		lines[i] = prefix + value + suffix
+	}
+	return []byte(strings.Join(lines, "")), nil
+}
+
+// ParseAssignments convierte los "nombre=valor" de --set en un mapa
+func ParseAssignments(assignments []string) (map[string]string, error) {
+	values := make(map[string]string, len(assignments))
+	for _, a := range assignments {
+		name, value, ok := strings.Cut(a, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --set %q, expected name=value", a)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// Declared devuelve las variables de los skills sin repetir nombre, ordenadas por nombre.
+// Si dos skills declaran la misma variable se usa la primera declaración.
+func Declared(fields ...skillmeta.Fields) []skillmeta.Variable {
+	seen := make(map[string]bool)
+	var out []skillmeta.Variable
+	for _, f := range fields {
+		for _, v := range f.Variables {
+			if !seen[v.Name] {
+				seen[v.Name] = true
+				out = append(out, v)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
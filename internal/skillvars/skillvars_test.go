@@ -0,0 +1,111 @@
+package skillvars
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"skli/internal/skillmeta"
+)
+
+func TestResolve(t *testing.T) {
+	decls := []skillmeta.Variable{
+		{Name: "ticket_prefix", Required: true},
+		{Name: "channel", Default: "#team"},
+		{Name: "account"},
+	}
+
+	values, err := Resolve(decls, map[string]string{"ticket_prefix": "SK", "other": "x"})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	want := map[string]string{"ticket_prefix": "SK", "channel": "#team", "account": ""}
+	if !reflect.DeepEqual(values, want) {
+		t.Fatalf("unexpected values %v", values)
+	}
+
+	_, err = Resolve(decls, nil)
+	var missing *MissingError
+	if !errors.As(err, &missing) || !reflect.DeepEqual(missing.Names, []string{"ticket_prefix"}) {
+		t.Fatalf("expected ticket_prefix to be missing, got %v", err)
+	}
+
+	if values, err := Resolve(nil, map[string]string{"a": "b"}); err != nil || values != nil {
+		t.Fatalf("skills without variables must not record values, got %v (%v)", values, err)
+	}
+}
+
+func TestRender(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "scripts"), 0755)
+	os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("Prefix: {{ skli.ticket_prefix }}-123\nKeep {{skli.unknown}} and ${{ vars.X }}\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "scripts", "run.sh"), []byte("#!/bin/sh\necho {{skli.ticket_prefix}}\n"), 0755)
+	os.WriteFile(filepath.Join(dir, "logo.bin"), []byte("\x00{{ skli.ticket_prefix }}"), 0644)
+
+	if err := Render(dir, map[string]string{"ticket_prefix": "SK"}); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	skill, _ := os.ReadFile(filepath.Join(dir, "SKILL.md"))
+	if string(skill) != "Prefix: SK-123\nKeep {{skli.unknown}} and ${{ vars.X }}\n" {
+		t.Fatalf("unexpected SKILL.md %q", skill)
+	}
+	script, _ := os.ReadFile(filepath.Join(dir, "scripts", "run.sh"))
+	info, _ := os.Stat(filepath.Join(dir, "scripts", "run.sh"))
+	if string(script) != "#!/bin/sh\necho SK\n" || info.Mode().Perm() != 0755 {
+		t.Fatalf("scripts must be rendered keeping their mode: %q %v", script, info.Mode())
+	}
+	binary, _ := os.ReadFile(filepath.Join(dir, "logo.bin"))
+	if string(binary) != "\x00{{ skli.ticket_prefix }}" {
+		t.Fatalf("binary files must not be touched, got %q", binary)
+	}
+}
+
+func TestRenderEscapesFrontmatterValues(t *testing.T) {
+	dir := t.TempDir()
+	skill := "---\nname: demo\ndescription: Deploy to {{ skli.project }}\nauthor: \"{{ skli.owner }}\"\nhomepage: '{{ skli.owner }}'\ntags:\n  - {{ skli.tag }}\n---\nProject {{ skli.project }}\n"
+	os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(skill), 0644)
+
+	values := map[string]string{"project": "acme: prod # eu", "owner": `Ann "O'Neil"`, "tag": "a: b"}
+	if err := Render(dir, values); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	meta, err := skillmeta.ParseDir(dir)
+	if err != nil {
+		t.Fatalf("rendered frontmatter must stay valid YAML: %v", err)
+	}
+	if meta.Description != "Deploy to acme: prod # eu" || meta.Author != `Ann "O'Neil"` || meta.Homepage != `Ann "O'Neil"` || len(meta.Tags) != 1 || meta.Tags[0] != "a: b" {
+		t.Fatalf("values must keep their text in the frontmatter, got %+v", meta)
+	}
+	body, _ := os.ReadFile(filepath.Join(dir, "SKILL.md"))
+	if !strings.HasSuffix(string(body), "---\nProject acme: prod # eu\n") {
+		t.Fatalf("the body must get the raw value, got %q", body)
+	}
+
+	for _, frontmatter := range []string{"{{ skli.tag }}: x", "tags: [{{ skli.tag }}]"} {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\nname: demo\n"+frontmatter+"\n---\n"), 0644)
+		if err := Render(dir, values); err == nil {
+			t.Fatalf("%q: expected an error for a placeholder outside a plain or quoted value", frontmatter)
+		}
+	}
+}
+
+func TestParseAssignments(t *testing.T) {
+	values, err := ParseAssignments([]string{"channel=#team", " prefix =a=b", "empty="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"channel": "#team", "prefix": "a=b", "empty": ""}
+	if !reflect.DeepEqual(values, want) {
+		t.Fatalf("unexpected values %v", values)
+	}
+	for _, bad := range []string{"channel", "=x"} {
+		if _, err := ParseAssignments([]string{bad}); err == nil {
+			t.Fatalf("expected an error for %q", bad)
+		}
+	}
+}
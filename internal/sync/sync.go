@@ -1,14 +1,21 @@
 package sync
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
 
+	"skli/internal/convert"
 	"skli/internal/db"
 	"skli/internal/gitrepo"
+	"skli/internal/integrity"
+	"skli/internal/project"
+	"skli/internal/provenance"
+	"skli/internal/security"
+	"skli/internal/skillvars"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -21,10 +28,15 @@ var (
 
 	getRemoteHashFn = gitrepo.GetRemoteHash
 	cloneAndScanFn  = gitrepo.CloneAndScan
-	saveInstalledFn = db.SaveInstalledSkill
+	updateLockFn    = db.Update
 	removeAllFn     = os.RemoveAll
 	statFn          = os.Stat
 	copyDirFn       = copyDir
+	manifestFn      = integrity.Compute
+	scanFn          = security.Scan
+	verifyFn        = provenance.Verify
+	treeHashesFn    = gitrepo.RemoteTreeHashes
+	branchExistsFn  = gitrepo.RemoteBranchExists
 )
 
 // SyncResult contiene el resultado de la sincronización
@@ -32,11 +44,19 @@ type SyncResult struct {
 	SkillName string
 	Updated   bool
 	Skipped   bool // Sin cambios (hash igual)
+	Pending   bool // Subido por skli y a la espera de que se fusione su PR
+	Merged    bool // La PR se ha fusionado en esta ejecución y el skill pasa a seguir la rama por defecto
+	PRURL     string
+	Findings  []security.Finding // Hallazgos de seguridad de la versión instalada
+	Unsigned  error              // Firma del commit no verificada; sin modo estricto se sincroniza igualmente
 	Error     error
+
+	entry *db.InstalledSkill // Entrada a guardar en el lock al terminar (nil si no cambia)
 }
 
-// SyncAllSkills sincroniza todos los skills instalados desde sus repos de origen
-func SyncAllSkills() ([]SyncResult, error) {
+// SyncAllSkills sincroniza todos los skills instalados desde sus repos de origen.
+// confirmed acepta los hallazgos de seguridad que la política confirm pide confirmar.
+func SyncAllSkills(confirmed bool) ([]SyncResult, error) {
 	grouped, err := db.GetSkillsByRepo()
 	if err != nil {
 		return nil, fmt.Errorf("error getting skills from skli.lock: %w", err)
@@ -74,13 +94,35 @@ func SyncAllSkills() ([]SyncResult, error) {
 		}
 	}()
 
-	// Procesar cada repo en paralelo
+	// Los skills de un repo se escanean desde su RemoteRoot, así que se agrupan también por raíz
+	type group struct {
+		repo   string
+		skills []db.InstalledSkill
+	}
+	var groups []group
 	for repoURL, skills := range grouped {
+		byRoot := make(map[string][]db.InstalledSkill)
+		var roots []string
+		for _, sk := range skills {
+			if _, ok := byRoot[sk.RemoteRoot]; !ok {
+				roots = append(roots, sk.RemoteRoot)
+			}
+			byRoot[sk.RemoteRoot] = append(byRoot[sk.RemoteRoot], sk)
+		}
+		for _, root := range roots {
+			groups = append(groups, group{repo: repoURL, skills: byRoot[root]})
+		}
+	}
+	totalRepos = len(groups)
+
+	// Procesar cada grupo en paralelo
+	for _, g := range groups {
+		repoURL, skills := g.repo, g.skills
 		wg.Add(1)
 		go func(repoURL string, skills []db.InstalledSkill) {
 			defer wg.Done()
 
-			results := syncRepo(repoURL, skills)
+			results := syncRepo(repoURL, skills, confirmed)
 
 			mu.Lock()
 			allResults = append(allResults, results...)
@@ -93,12 +135,47 @@ func SyncAllSkills() ([]SyncResult, error) {
 	done <- true
 	fmt.Print("\r\033[K") // Limpiar línea
 
+	// Una única escritura consistente del lock para toda la ejecución
+	if err := saveEntries(allResults); err != nil {
+		return allResults, fmt.Errorf("error saving skli.lock: %w", err)
+	}
+
 	return allResults, nil
 }
 
+// saveEntries guarda en una sola transacción las entradas actualizadas por los repos
+func saveEntries(results []SyncResult) error {
+	var entries []db.InstalledSkill
+	for _, r := range results {
+		if r.entry != nil {
+			entries = append(entries, *r.entry)
+		}
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	return updateLockFn(func(lock *db.LockFile) error {
+		for _, e := range entries {
+			lock.Upsert(e)
+		}
+		return nil
+	})
+}
+
 // syncRepo sincroniza todos los skills de un repo específico
-func syncRepo(repoURL string, skills []db.InstalledSkill) []SyncResult {
-	var results []SyncResult
+func syncRepo(repoURL string, skills []db.InstalledSkill, confirmed bool) (results []SyncResult) {
+	results, skills, merged := resolvePending(repoURL, skills)
+	if len(skills) == 0 {
+		return results
+	}
+	if len(merged) > 0 {
+		defer func() {
+			for i := range results {
+				results[i].Merged = merged[results[i].SkillName]
+			}
+		}()
+	}
 
 	// Usamos el RemoteRoot del primer skill como referencia para el repo
 	// (Asumimos que todos los skills del mismo repo se buscan en la misma ruta)
@@ -129,7 +206,7 @@ func syncRepo(repoURL string, skills []db.InstalledSkill) []SyncResult {
 		}
 
 		// Verificar si la carpeta del skill existe localmente
-		if _, err := statFn(s.Path); os.IsNotExist(err) {
+		if _, err := statFn(project.Resolve(s.Path)); os.IsNotExist(err) {
 			allUpToDate = false
 			break
 		}
@@ -162,6 +239,22 @@ func syncRepo(repoURL string, skills []db.InstalledSkill) []SyncResult {
 	// Usar el path detectado para mayor consistencia
 	skillsPath = scanRes.SkillsPath
 
+	// Verificar la firma del commit antes de copiar nada; en modo estricto no se sincroniza
+	signer, unsigned := verifyFn(scanRes.TempDir, repoURL, scanRes.CommitHash, skillsPath)
+	if unsigned != nil && provenance.Strict() {
+		for _, s := range skills {
+			results = append(results, SyncResult{SkillName: s.Name, Error: unsigned})
+		}
+		return results
+	}
+	if unsigned != nil {
+		defer func() {
+			for i := range results {
+				results[i].Unsigned = unsigned
+			}
+		}()
+	}
+
 	// Crear un mapa de skills remotos para búsqueda rápida
 	remoteMap := make(map[string]gitrepo.SkillInfo)
 	for _, rs := range scanRes.Skills {
@@ -188,38 +281,78 @@ func syncRepo(repoURL string, skills []db.InstalledSkill) []SyncResult {
 		}
 
 		if hashUnchanged {
-			if _, err := statFn(installed.Path); err == nil {
+			if _, err := statFn(project.Resolve(installed.Path)); err == nil {
 				// Actualizar el CommitHash para que no vuelva a descargar la próxima vez si no hay cambios nuevos
-				if installed.CommitHash != scanRes.CommitHash {
+				result := SyncResult{
+					SkillName: installed.Name,
+					Skipped:   true,
+				}
+				if installed.CommitHash != scanRes.CommitHash || installed.TreeHash == "" {
 					installed.CommitHash = scanRes.CommitHash
-					saveInstalledFn(installed)
+					installed.Signer = signer
+					if installed.TreeHash == "" {
+						installed.TreeHash = remote.TreeHash
+					}
+					result.entry = &installed
 				}
 
-				results = append(results, SyncResult{
-					SkillName: installed.Name,
-					Skipped:   true,
-				})
+				results = append(results, result)
 				continue
 			}
 		}
 
 		// Copiar la versión más reciente
 		// Usamos el skillsPath del repo
-		var src string
-		if skillsPath == "." {
-			src = filepath.Join(scanRes.TempDir, remote.Path)
-		} else {
-			src = filepath.Join(scanRes.TempDir, skillsPath, remote.Path)
+		src := gitrepo.SkillSource(scanRes.TempDir, skillsPath, remote)
+
+		// El destino ya está guardado en installed.Path (ej: ".cursor/skills/nombre-skill"), relativo a la raíz del proyecto
+		dest := project.Resolve(installed.Path)
+
+		// La nueva versión se instala con los mismos valores; si declara variables nuevas
+		// obligatorias se deja la instalada
+		values, err := skillvars.Resolve(remote.Variables, installed.Values)
+		if err != nil {
+			results = append(results, SyncResult{
+				SkillName: installed.Name,
+				Error:     fmt.Errorf("new version needs values: %w", err),
+			})
+			continue
 		}
 
-		// El destino ya está guardado en installed.Path (ej: ".cursor/skills/nombre-skill")
-		dest := installed.Path
+		// La política de seguridad se aplica a la nueva versión antes de tocar la instalada
+		findings, err := scanFn(src, repoURL)
+		if err == nil {
+			err = security.Enforce(findings, confirmed)
+		}
+		if err != nil {
+			if errors.Is(err, security.ErrUnconfirmed) {
+				err = fmt.Errorf("%w; run 'skli sync --yes' to accept them", err)
+			}
+			results = append(results, SyncResult{
+				SkillName: installed.Name,
+				Findings:  findings,
+				Error:     err,
+			})
+			continue
+		}
+
+		// Las variables se sustituyen antes de copiar o convertir, como al instalar y en verify
+		rendered, cleanup, err := renderSource(src, values)
+		if err != nil {
+			results = append(results, SyncResult{
+				SkillName: installed.Name,
+				Error:     fmt.Errorf("error rendering: %w", err),
+			})
+			continue
+		}
 
 		// Eliminar la versión anterior
 		removeAllFn(dest)
 
-		// Copiar la nueva versión
-		if err := copyDirFn(src, dest); err != nil {
+		// Copiar la nueva versión (o regenerar el artefacto con el conversor con el que se instaló)
+		err = installSkill(installed.Converter, rendered, dest)
+		cleanup()
+		if err != nil {
 			results = append(results, SyncResult{
 				SkillName: installed.Name,
 				Error:     fmt.Errorf("error copying: %w", err),
@@ -227,26 +360,127 @@ func syncRepo(repoURL string, skills []db.InstalledSkill) []SyncResult {
 			continue
 		}
 
-		// Actualizar metadatos en el lock file con el nuevo hash y el mismo path base
-		saveInstalledFn(db.InstalledSkill{
-			Name:        remote.Name,
-			Description: remote.Description,
-			Path:        installed.Path,
-			RemoteRepo:  repoURL,
-			RemoteRoot:  skillsPath,
-			RemotePath:  remote.Path,
-			CommitHash:  scanRes.CommitHash,
-		})
+		manifest, err := manifestFn(dest)
+		if err != nil {
+			results = append(results, SyncResult{
+				SkillName: installed.Name,
+				Error:     fmt.Errorf("error hashing: %w", err),
+			})
+			continue
+		}
 
+		// Actualizar metadatos en el lock file con el nuevo hash y el mismo path base
 		results = append(results, SyncResult{
 			SkillName: installed.Name,
 			Updated:   true,
+			Findings:  findings,
+			entry: &db.InstalledSkill{
+				Name:        remote.Name,
+				Description: remote.Description,
+				Path:        installed.Path,
+				RemoteRepo:  repoURL,
+				RemoteRoot:  skillsPath,
+				RemotePath:  remote.Path,
+				CommitHash:  scanRes.CommitHash,
+				TreeHash:    remote.TreeHash,
+				Files:       manifest,
+				Fields:      remote.Fields,
+				Values:      values,
+				Editor:      installed.Editor,
+				Converter:   installed.Converter,
+				Signer:      signer,
+			},
 		})
 	}
 
 	return results
 }
 
+// renderSource devuelve una copia de la carpeta del skill con los valores sustituidos, y la función
+// que la borra. La carpeta descargada se comparte entre las entradas del lock del mismo remoto,
+// que pueden tener valores distintos, así que no se renderiza en su sitio.
+func renderSource(src string, values map[string]string) (string, func(), error) {
+	if len(values) == 0 {
+		return src, func() {}, nil
+	}
+	tempDir, err := os.MkdirTemp("", "skli-render-*")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(tempDir) }
+	rendered := filepath.Join(tempDir, filepath.Base(src))
+	if err := copyDir(src, rendered); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	if err := skillvars.Render(rendered, values); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return rendered, cleanup, nil
+}
+
+// installSkill copia la carpeta del skill en dest o, si se instaló convertido, genera el artefacto
+func installSkill(converter, src, dest string) error {
+	if converter == "" {
+		return copyDirFn(src, dest)
+	}
+	return convert.Export(converter, src, dest)
+}
+
+// resolvePending comprueba los skills registrados al subirlos. Los que siguen esperando la PR
+// se devuelven como resultado; los fusionados pasan a la lista de skills a sincronizar
+// y se devuelven también por nombre.
+// Una PR se da por fusionada cuando la rama por defecto tiene el mismo árbol que se subió,
+// o cuando la rama de la PR ya no existe y el skill está en la rama por defecto.
+func resolvePending(repoURL string, skills []db.InstalledSkill) ([]SyncResult, []db.InstalledSkill, map[string]bool) {
+	var results []SyncResult
+	active := make([]db.InstalledSkill, 0, len(skills))
+	merged := make(map[string]bool)
+
+	for _, s := range skills {
+		if !s.IsPending() {
+			active = append(active, s)
+			continue
+		}
+
+		treePath := s.RemoteTreePath()
+		hashes, err := treeHashesFn(repoURL, "", []string{treePath})
+		if err != nil {
+			results = append(results, SyncResult{SkillName: s.Name, Error: fmt.Errorf("error checking default branch: %w", err)})
+			continue
+		}
+		hash, onDefault := hashes[treePath]
+
+		if !onDefault || hash != s.TreeHash {
+			branchRepo := s.PendingRepo
+			if branchRepo == "" {
+				branchRepo = repoURL
+			}
+			open, err := branchExistsFn(branchRepo, s.PendingBranch)
+			if err != nil {
+				results = append(results, SyncResult{SkillName: s.Name, Error: err})
+				continue
+			}
+			if open {
+				results = append(results, SyncResult{SkillName: s.Name, Pending: true, PRURL: s.PRURL})
+				continue
+			}
+			if !onDefault {
+				results = append(results, SyncResult{SkillName: s.Name,
+					Error: fmt.Errorf("branch %s is gone and %s is not on the default branch (PR closed without merging?)", s.PendingBranch, treePath)})
+				continue
+			}
+		}
+
+		s.PendingBranch, s.PendingRepo, s.PRURL = "", "", ""
+		s.CommitHash = ""
+		merged[s.Name] = true
+		active = append(active, s)
+	}
+	return results, active, merged
+}
+
 func copyDir(src, dest string) error {
 	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
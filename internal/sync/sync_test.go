@@ -0,0 +1,369 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"skli/internal/config"
+	"skli/internal/db"
+	"skli/internal/gitrepo"
+	"skli/internal/integrity"
+	"skli/internal/project"
+	"skli/internal/provenance"
+	"skli/internal/security"
+	"skli/internal/skillmeta"
+)
+
+const testRepo = "https://example.com/org/repo.git"
+
+// stubRemote sustituye las consultas al remoto durante el test
+func stubRemote(t *testing.T, hashes map[string]string, branches map[string]bool) {
+	t.Helper()
+	prevHashes, prevBranch, prevRemote, prevClone, prevStat := treeHashesFn, branchExistsFn, getRemoteHashFn, cloneAndScanFn, statFn
+	prevRemove, prevCopy, prevManifest, prevScan, prevVerify := removeAllFn, copyDirFn, manifestFn, scanFn, verifyFn
+	t.Cleanup(func() {
+		treeHashesFn, branchExistsFn, getRemoteHashFn, cloneAndScanFn, statFn = prevHashes, prevBranch, prevRemote, prevClone, prevStat
+		removeAllFn, copyDirFn, manifestFn, scanFn, verifyFn = prevRemove, prevCopy, prevManifest, prevScan, prevVerify
+	})
+
+	treeHashesFn = func(_, _ string, paths []string) (map[string]string, error) {
+		out := make(map[string]string)
+		for _, p := range paths {
+			if h, ok := hashes[p]; ok {
+				out[p] = h
+			}
+		}
+		return out, nil
+	}
+	branchExistsFn = func(repo, branch string) (bool, error) {
+		return branches[repo+"#"+branch], nil
+	}
+	getRemoteHashFn = func(string) (string, error) { return "head", nil }
+	cloneAndScanFn = func(string, string) (gitrepo.ScanResult, error) {
+		var skills []gitrepo.SkillInfo
+		for p, h := range hashes {
+			skills = append(skills, gitrepo.SkillInfo{Name: "demo", Path: p[len("skills/"):], TreeHash: h})
+		}
+		return gitrepo.ScanResult{TempDir: t.TempDir(), SkillsPath: "skills", CommitHash: "head", Skills: skills}, nil
+	}
+	statFn = func(string) (os.FileInfo, error) { return nil, nil }
+	removeAllFn = func(string) error { return nil }
+	copyDirFn = func(string, string) error { return nil }
+	manifestFn = func(string) (integrity.Manifest, error) { return integrity.Manifest{}, nil }
+	scanFn = func(string, string) ([]security.Finding, error) { return nil, nil }
+	verifyFn = func(string, string, string, string) (string, error) { return "", nil }
+}
+
+func pendingSkill() db.InstalledSkill {
+	return db.InstalledSkill{
+		Name:          "demo",
+		Path:          "skills/demo",
+		RemoteRepo:    testRepo,
+		RemoteRoot:    "skills",
+		RemotePath:    "demo",
+		TreeHash:      "uploaded",
+		PendingBranch: "feat/demo",
+		PRURL:         "https://example.com/pr/1",
+	}
+}
+
+func TestSyncRepoKeepsPendingWhileBranchExists(t *testing.T) {
+	stubRemote(t, nil, map[string]bool{testRepo + "#feat/demo": true})
+
+	results := syncRepo(testRepo, []db.InstalledSkill{pendingSkill()}, false)
+	if len(results) != 1 || !results[0].Pending || results[0].entry != nil {
+		t.Fatalf("expected a pending result without lock changes, got %+v", results)
+	}
+	if results[0].PRURL != "https://example.com/pr/1" {
+		t.Fatalf("pending result must carry the PR URL, got %q", results[0].PRURL)
+	}
+}
+
+func TestSyncRepoTracksDefaultBranchOnceMerged(t *testing.T) {
+	stubRemote(t, map[string]string{"skills/demo": "uploaded"}, nil)
+
+	results := syncRepo(testRepo, []db.InstalledSkill{pendingSkill()}, false)
+	if len(results) != 1 || !results[0].Merged || results[0].entry == nil {
+		t.Fatalf("expected a merged result with a lock update, got %+v", results)
+	}
+	entry := results[0].entry
+	if entry.IsPending() || entry.PRURL != "" || entry.CommitHash != "head" {
+		t.Fatalf("merged entry must follow the default branch: %+v", entry)
+	}
+}
+
+func TestSyncRepoTreatsReviewerEditsAsMerged(t *testing.T) {
+	// La rama ya no existe y el skill está en la rama por defecto con otro contenido
+	stubRemote(t, map[string]string{"skills/demo": "edited"}, nil)
+
+	results := syncRepo(testRepo, []db.InstalledSkill{pendingSkill()}, false)
+	if len(results) != 1 || !results[0].Merged || !results[0].Updated {
+		t.Fatalf("expected the skill to be merged and updated, got %+v", results)
+	}
+	if entry := results[0].entry; entry.IsPending() || entry.TreeHash != "edited" {
+		t.Fatalf("merged entry must take the default branch content: %+v", entry)
+	}
+}
+
+func TestSyncRepoReportsClosedPR(t *testing.T) {
+	stubRemote(t, nil, nil)
+
+	results := syncRepo(testRepo, []db.InstalledSkill{pendingSkill()}, false)
+	if len(results) != 1 || results[0].Error == nil || results[0].Merged {
+		t.Fatalf("expected an error for a PR closed without merging, got %+v", results)
+	}
+}
+
+func TestSyncRepoUsesForkForPendingBranch(t *testing.T) {
+	fork := "https://example.com/me/repo.git"
+	stubRemote(t, nil, map[string]bool{fork + "#feat/demo": true})
+
+	skill := pendingSkill()
+	skill.PendingRepo = fork
+	results := syncRepo(testRepo, []db.InstalledSkill{skill}, false)
+	if len(results) != 1 || !results[0].Pending {
+		t.Fatalf("expected the branch to be looked up in the fork, got %+v", results)
+	}
+}
+
+func TestSyncRepoPendingLookupErrors(t *testing.T) {
+	stubRemote(t, nil, nil)
+	branchExistsFn = func(string, string) (bool, error) { return false, errors.New("offline") }
+
+	results := syncRepo(testRepo, []db.InstalledSkill{pendingSkill()}, false)
+	if len(results) != 1 || results[0].Error == nil {
+		t.Fatalf("expected the lookup error to be reported, got %+v", results)
+	}
+}
+
+func TestSyncRepoRegeneratesConvertedSkills(t *testing.T) {
+	root := t.TempDir()
+	project.SetRootForTest(t, root)
+
+	stubRemote(t, map[string]string{"skills/demo": "new"}, nil)
+	repo := t.TempDir()
+	os.MkdirAll(filepath.Join(repo, "skills", "demo"), 0755)
+	os.WriteFile(filepath.Join(repo, "skills", "demo", "SKILL.md"), []byte("---\nname: demo\ndescription: Demo skill\n---\n# New body\n"), 0644)
+	cloneAndScanFn = func(string, string) (gitrepo.ScanResult, error) {
+		return gitrepo.ScanResult{TempDir: repo, SkillsPath: "skills", CommitHash: "head",
+			Skills: []gitrepo.SkillInfo{{Name: "demo", Description: "Demo skill", Path: "demo", TreeHash: "new"}}}, nil
+	}
+	manifestFn = integrity.Compute
+
+	installed := db.InstalledSkill{Name: "demo", Path: ".cursor/rules/demo.mdc", RemoteRepo: testRepo,
+		RemoteRoot: "skills", RemotePath: "demo", TreeHash: "old", Converter: "cursor-mdc"}
+	results := syncRepo(testRepo, []db.InstalledSkill{installed}, false)
+	if len(results) != 1 || !results[0].Updated || results[0].Error != nil {
+		t.Fatalf("expected an update, got %+v", results)
+	}
+	entry := results[0].entry
+	if entry.Converter != "cursor-mdc" || entry.Path != installed.Path || entry.Files["demo.mdc"] == "" {
+		t.Fatalf("entry must keep the converter and hash the artefact: %+v", entry)
+	}
+	data, err := os.ReadFile(filepath.Join(root, ".cursor", "rules", "demo.mdc"))
+	if err != nil || !strings.Contains(string(data), "alwaysApply: false") || !strings.Contains(string(data), "# New body") {
+		t.Fatalf("artefact must be regenerated with the converter, got %q (%v)", data, err)
+	}
+}
+
+func TestSyncRepoRendersVariablesWithLockValues(t *testing.T) {
+	root := t.TempDir()
+	project.SetRootForTest(t, root)
+
+	stubRemote(t, map[string]string{"skills/demo": "new"}, nil)
+	repo := t.TempDir()
+	os.MkdirAll(filepath.Join(repo, "skills", "demo"), 0755)
+	os.WriteFile(filepath.Join(repo, "skills", "demo", "SKILL.md"), []byte("---\nname: demo\n---\nOpen tickets as {{ skli.prefix }}-N in {{ skli.channel }}\n"), 0644)
+	variables := []skillmeta.Variable{{Name: "prefix", Required: true}, {Name: "channel", Default: "#general"}}
+	cloneAndScanFn = func(string, string) (gitrepo.ScanResult, error) {
+		info := gitrepo.SkillInfo{Name: "demo", Path: "demo", TreeHash: "new"}
+		info.Variables = variables
+		return gitrepo.ScanResult{TempDir: repo, SkillsPath: "skills", CommitHash: "head", Skills: []gitrepo.SkillInfo{info}}, nil
+	}
+	copyDirFn = copyDir
+	manifestFn = integrity.Compute
+
+	installed := db.InstalledSkill{Name: "demo", Path: "skills/demo", RemoteRepo: testRepo, RemoteRoot: "skills",
+		RemotePath: "demo", TreeHash: "old", Values: map[string]string{"prefix": "SK"}}
+	results := syncRepo(testRepo, []db.InstalledSkill{installed}, false)
+	if len(results) != 1 || !results[0].Updated || results[0].Error != nil {
+		t.Fatalf("expected an update, got %+v", results)
+	}
+	if values := results[0].entry.Values; values["prefix"] != "SK" || values["channel"] != "#general" {
+		t.Fatalf("entry must keep the values and record new defaults: %v", values)
+	}
+	data, _ := os.ReadFile(filepath.Join(root, "skills", "demo", "SKILL.md"))
+	if !strings.Contains(string(data), "Open tickets as SK-N in #general") {
+		t.Fatalf("updated content must be rendered with the lock values, got %q", data)
+	}
+
+	installed.Values = nil
+	os.WriteFile(filepath.Join(root, "skills", "demo", "SKILL.md"), []byte("local"), 0644)
+	results = syncRepo(testRepo, []db.InstalledSkill{installed}, false)
+	if len(results) != 1 || results[0].Error == nil || !strings.Contains(results[0].Error.Error(), "prefix") {
+		t.Fatalf("expected an error for a variable without value, got %+v", results)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "skills", "demo", "SKILL.md")); string(data) != "local" {
+		t.Fatalf("installed skill must be kept when values are missing, got %q", data)
+	}
+}
+
+func TestSyncRepoAppliesSecurityPolicy(t *testing.T) {
+	root := t.TempDir()
+	project.SetRootForTest(t, root)
+
+	stubRemote(t, map[string]string{"skills/demo": "new"}, nil)
+	repo := t.TempDir()
+	os.MkdirAll(filepath.Join(repo, "skills", "demo"), 0755)
+	os.WriteFile(filepath.Join(repo, "skills", "demo", "SKILL.md"), []byte("---\nname: demo\n---\n"), 0644)
+	os.WriteFile(filepath.Join(repo, "skills", "demo", "setup.sh"), []byte("#!/bin/sh\necho hi\n"), 0755)
+	cloneAndScanFn = func(string, string) (gitrepo.ScanResult, error) {
+		return gitrepo.ScanResult{TempDir: repo, SkillsPath: "skills", CommitHash: "head",
+			Skills: []gitrepo.SkillInfo{{Name: "demo", Path: "demo", TreeHash: "new"}}}, nil
+	}
+	scanFn = security.Scan
+	copyDirFn = copyDir
+	manifestFn = integrity.Compute
+	os.MkdirAll(filepath.Join(root, "skills", "demo"), 0755)
+	os.WriteFile(filepath.Join(root, "skills", "demo", "SKILL.md"), []byte("old"), 0644)
+	installed := db.InstalledSkill{Name: "demo", Path: "skills/demo", RemoteRepo: testRepo, RemoteRoot: "skills", RemotePath: "demo", TreeHash: "old"}
+
+	t.Cleanup(func() { security.Configure(config.SecurityConfig{}) })
+	security.Configure(config.SecurityConfig{Policy: "confirm"})
+	results := syncRepo(testRepo, []db.InstalledSkill{installed}, false)
+	if len(results) != 1 || !errors.Is(results[0].Error, security.ErrUnconfirmed) || len(results[0].Findings) != 1 {
+		t.Fatalf("expected the update to wait for confirmation, got %+v", results)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "skills", "demo", "SKILL.md")); string(data) != "old" {
+		t.Fatalf("installed version must be kept until confirmed, got %q", data)
+	}
+
+	results = syncRepo(testRepo, []db.InstalledSkill{installed}, true)
+	if len(results) != 1 || !results[0].Updated || len(results[0].Findings) != 1 {
+		t.Fatalf("confirmed findings must be installed and reported, got %+v", results)
+	}
+
+	security.Configure(config.SecurityConfig{Policy: "block"})
+	results = syncRepo(testRepo, []db.InstalledSkill{installed}, true)
+	if len(results) != 1 || !errors.Is(results[0].Error, security.ErrBlocked) {
+		t.Fatalf("block must refuse the update, got %+v", results)
+	}
+}
+
+func TestSyncRepoEnforcesStrictSignatures(t *testing.T) {
+	stubRemote(t, map[string]string{"skills/demo": "new"}, nil)
+	unsigned := fmt.Errorf("%w: commit at head: not signed", provenance.ErrUnverified)
+	verifyFn = func(string, string, string, string) (string, error) { return "", unsigned }
+	installed := db.InstalledSkill{Name: "demo", Path: "skills/demo", RemoteRepo: testRepo, RemoteRoot: "skills", RemotePath: "demo", TreeHash: "old"}
+
+	t.Cleanup(func() { provenance.Configure(config.ProvenanceConfig{}) })
+	provenance.Configure(config.ProvenanceConfig{})
+	results := syncRepo(testRepo, []db.InstalledSkill{installed}, false)
+	if len(results) != 1 || !results[0].Updated || !errors.Is(results[0].Unsigned, provenance.ErrUnverified) {
+		t.Fatalf("without strict the update must go on with a warning, got %+v", results)
+	}
+
+	provenance.Configure(config.ProvenanceConfig{Strict: true})
+	results = syncRepo(testRepo, []db.InstalledSkill{installed}, false)
+	if len(results) != 1 || results[0].Updated || !errors.Is(results[0].Error, provenance.ErrUnverified) {
+		t.Fatalf("strict must refuse unsigned updates, got %+v", results)
+	}
+
+	verifyFn = func(string, string, string, string) (string, error) { return "alice SHA256:abc", nil }
+	results = syncRepo(testRepo, []db.InstalledSkill{installed}, false)
+	if len(results) != 1 || !results[0].Updated || results[0].entry.Signer != "alice SHA256:abc" {
+		t.Fatalf("the verified signer must be recorded, got %+v", results)
+	}
+}
+
+func TestCheckSignaturesDetectsChangedSigner(t *testing.T) {
+	stubRemote(t, nil, nil)
+	prevFetch := fetchPathsFn
+	t.Cleanup(func() { fetchPathsFn = prevFetch })
+	fetches := 0
+	fetchPathsFn = func(string, string, []string) (string, error) {
+		fetches++
+		return t.TempDir(), nil
+	}
+	verifyFn = func(string, string, string, string) (string, error) { return "mallory SHA256:other", nil }
+
+	t.Cleanup(func() { provenance.Configure(config.ProvenanceConfig{}) })
+	provenance.Configure(config.ProvenanceConfig{Signers: []config.Signer{
+		{Remote: testRepo, Keys: []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGv3 alice"}},
+	}})
+	skill := db.InstalledSkill{Name: "demo", Path: "skills/demo", RemoteRepo: testRepo, RemoteRoot: "skills", RemotePath: "demo", CommitHash: "head"}
+	signed, other := skill, skill
+	signed.Signer = "alice SHA256:abc"
+	other.Name, other.RemotePath = "other", "other"
+	unrelated := db.InstalledSkill{Name: "plain", RemoteRepo: "https://example.com/org/plain", CommitHash: "head", Signer: "bob SHA256:def"}
+
+	results := []VerifyResult{{Skill: signed}, {Skill: other}, {Skill: unrelated}}
+	checkSignatures(results)
+	if results[0].Signature == nil || !strings.Contains(results[0].Signature.Error(), "the lock records") {
+		t.Fatalf("a different key must be reported, got %v", results[0].Signature)
+	}
+	if results[1].Signature != nil {
+		t.Fatalf("skills installed before signing must accept any configured key, got %v", results[1].Signature)
+	}
+	if results[2].Signature == nil || !strings.Contains(results[2].Signature.Error(), "no signers configured") {
+		t.Fatalf("signed skills of repos without signers must be reported, got %v", results[2].Signature)
+	}
+	if fetches != 1 {
+		t.Fatalf("each repo and commit must be fetched once, got %d", fetches)
+	}
+}
+
+func TestSyncAndVerifyRenderBeforeConverting(t *testing.T) {
+	prevFetch := fetchPathsFn
+	t.Cleanup(func() { fetchPathsFn = prevFetch })
+
+	repo := t.TempDir()
+	src := filepath.Join(repo, "skills", "demo")
+	if err := os.MkdirAll(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "---\nname: demo\ndescription: Deploy {{ skli.project }}\n---\nUse {{ skli.project }}\n"
+	if err := os.WriteFile(filepath.Join(src, "SKILL.md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fetchPathsFn = func(string, string, []string) (string, error) {
+		tmp := t.TempDir()
+		return tmp, copyDir(repo, tmp)
+	}
+	values := map[string]string{"project": "acme: prod # eu"}
+
+	// Sync: copia renderizada y después el conversor
+	rendered, cleanup, err := renderSource(src, values)
+	if err != nil {
+		t.Fatalf("renderSource: %v", err)
+	}
+	synced := filepath.Join(t.TempDir(), "demo.mdc")
+	err = installSkill("cursor-mdc", rendered, synced)
+	cleanup()
+	if err != nil {
+		t.Fatalf("installSkill: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(src, "SKILL.md")); string(data) != content {
+		t.Fatalf("the shared download must not be rendered in place, got %q", data)
+	}
+
+	// Verify: regenera el artefacto del commit del lock
+	tempDir, _, installedPath, err := fetchSkill(db.InstalledSkill{
+		Name: "demo", Path: ".cursor/rules/demo.mdc", RemoteRepo: testRepo, RemoteRoot: "skills", RemotePath: "demo",
+		CommitHash: "c1", Values: values, Converter: "cursor-mdc",
+	})
+	if err != nil {
+		t.Fatalf("fetchSkill: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	want, _ := os.ReadFile(synced)
+	got, _ := os.ReadFile(installedPath)
+	if len(want) == 0 || string(want) != string(got) {
+		t.Fatalf("verify must rebuild the same bytes as sync:\n%s\n---\n%s", want, got)
+	}
+}
@@ -0,0 +1,205 @@
+package sync
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"skli/internal/convert"
+	"skli/internal/db"
+	"skli/internal/gitrepo"
+	"skli/internal/integrity"
+	"skli/internal/project"
+	"skli/internal/provenance"
+	"skli/internal/skillvars"
+)
+
+var fetchPathsFn = gitrepo.FetchPaths
+
+// VerifyResult contiene el resultado de verificar un skill instalado
+type VerifyResult struct {
+	Skill      db.InstalledSkill
+	Drift      integrity.Drift
+	NoManifest bool  // El lock no tiene manifiesto para este skill
+	Fixed      bool  // Restaurado desde el commit del lock
+	Signature  error // La firma del commit del lock ya no se verifica con los firmantes configurados
+	Error      error
+}
+
+// OK indica si el skill coincide con lo registrado en el lock
+func (r VerifyResult) OK() bool {
+	return r.Error == nil && r.Signature == nil && (r.Fixed || (!r.NoManifest && r.Drift.Clean()))
+}
+
+// VerifyAll compara el contenido de cada skill instalado con el manifiesto del lock.
+// Con fix restaura los skills alterados desde el commit registrado en el lock.
+func VerifyAll(fix bool) ([]VerifyResult, error) {
+	lock, err := db.LoadLockFile()
+	if err != nil {
+		return nil, fmt.Errorf("error reading skli.lock: %w", err)
+	}
+
+	results := make([]VerifyResult, 0, len(lock.Skills))
+	for _, s := range lock.Skills {
+		result := VerifyResult{Skill: s, NoManifest: s.Files == nil}
+		if !result.NoManifest {
+			result.Drift, result.Error = integrity.Check(project.Resolve(s.Path), s.Files)
+		}
+		results = append(results, result)
+	}
+	checkSignatures(results)
+
+	if !fix {
+		return results, nil
+	}
+
+	var recorded []db.InstalledSkill
+	for i := range results {
+		r := &results[i]
+		// Con la firma rota no se restaura: el commit del lock ya no es de confianza
+		if r.OK() || r.Error != nil || r.Signature != nil {
+			continue
+		}
+		manifest, err := restoreSkill(r.Skill)
+		if err != nil {
+			r.Error = fmt.Errorf("could not restore: %w", err)
+			continue
+		}
+		r.Fixed = true
+		if r.NoManifest {
+			r.Skill.Files = manifest
+			recorded = append(recorded, r.Skill)
+		}
+	}
+
+	if len(recorded) > 0 {
+		err := updateLockFn(func(lock *db.LockFile) error {
+			for _, s := range recorded {
+				lock.Upsert(s)
+			}
+			return nil
+		})
+		if err != nil {
+			return results, fmt.Errorf("error saving skli.lock: %w", err)
+		}
+	}
+
+	return results, nil
+}
+
+// checkSignatures vuelve a verificar el commit de los skills de remotes con firmantes configurados
+// y de los que se instalaron con firma. Cada repo y commit se descarga una sola vez.
+func checkSignatures(results []VerifyResult) {
+	checked := map[string]signatureCheck{}
+	for i := range results {
+		r := &results[i]
+		s := r.Skill
+		if s.RemoteRepo == "" || s.CommitHash == "" {
+			continue
+		}
+		if !provenance.Required(s.RemoteRepo) {
+			if s.Signer != "" {
+				r.Signature = fmt.Errorf("installed with a signature from %s, but %s has no signers configured", s.Signer, s.RemoteRepo)
+			}
+			continue
+		}
+
+		key := s.RemoteRepo + "@" + s.CommitHash + ":" + s.RemoteRoot
+		c, ok := checked[key]
+		if !ok {
+			c = verifySkillSignature(s)
+			checked[key] = c
+		}
+		switch {
+		case c.err != nil:
+			r.Signature = c.err
+		case s.Signer != "" && !provenance.SameKey(s.Signer, c.signer):
+			r.Signature = fmt.Errorf("signed by %s, but the lock records %s", c.signer, s.Signer)
+		}
+	}
+}
+
+type signatureCheck struct {
+	signer string
+	err    error
+}
+
+// verifySkillSignature descarga el commit del lock y verifica su firma
+func verifySkillSignature(s db.InstalledSkill) signatureCheck {
+	tempDir, err := fetchPathsFn(s.RemoteRepo, s.CommitHash, []string{s.RemoteTreePath()})
+	if err != nil {
+		return signatureCheck{err: err}
+	}
+	defer removeAllFn(tempDir)
+	signer, err := verifyFn(tempDir, s.RemoteRepo, s.CommitHash, s.RemoteRoot)
+	return signatureCheck{signer: signer, err: err}
+}
+
+// SourceManifest calcula el manifiesto del skill tal y como se instaló desde el commit del lock,
+// sin tocar lo instalado. Es la fuente de los manifiestos de 'skli lock upgrade'.
+func SourceManifest(s db.InstalledSkill) (integrity.Manifest, error) {
+	tempDir, _, installedPath, err := fetchSkill(s)
+	if err != nil {
+		return nil, err
+	}
+	defer removeAllFn(tempDir)
+	return manifestFn(installedPath)
+}
+
+// restoreSkill vuelve a copiar el skill desde el commit del lock y comprueba que coincide con su manifiesto
+func restoreSkill(s db.InstalledSkill) (integrity.Manifest, error) {
+	tempDir, src, restoredPath, err := fetchSkill(s)
+	if err != nil {
+		return nil, err
+	}
+	defer removeAllFn(tempDir)
+
+	restored, err := manifestFn(restoredPath)
+	if err != nil {
+		return nil, err
+	}
+	if s.Files != nil {
+		if drift := integrity.Compare(s.Files, restored); !drift.Clean() {
+			return nil, fmt.Errorf("content at commit %s does not match the recorded manifest", s.CommitHash)
+		}
+	}
+
+	dest := project.Resolve(s.Path)
+	removeAllFn(dest)
+	if err := installSkill(s.Converter, src, dest); err != nil {
+		return nil, err
+	}
+
+	return restored, nil
+}
+
+// fetchSkill descarga el skill del commit del lock y lo prepara como se instaló. Devuelve la carpeta
+// temporal (la borra el llamador), la carpeta del skill y la ruta con el contenido que se instala.
+func fetchSkill(s db.InstalledSkill) (tempDir, src, installedPath string, err error) {
+	if s.RemoteRepo == "" || s.CommitHash == "" {
+		return "", "", "", fmt.Errorf("no source commit recorded")
+	}
+
+	treePath := s.RemoteTreePath()
+	tempDir, err = fetchPathsFn(s.RemoteRepo, s.CommitHash, []string{treePath})
+	if err != nil {
+		return "", "", "", err
+	}
+
+	src = filepath.Join(tempDir, filepath.FromSlash(treePath))
+	// La copia temporal se renderiza con los valores del lock antes de convertirla, en el mismo
+	// orden que install y sync, para compararla con lo instalado
+	if err := skillvars.Render(src, s.Values); err != nil {
+		removeAllFn(tempDir)
+		return "", "", "", err
+	}
+	// Los artefactos convertidos se regeneran aparte para comparar su manifiesto antes de tocar dest
+	installedPath = src
+	if s.Converter != "" {
+		installedPath = filepath.Join(tempDir, ".skli-converted", filepath.Base(s.Path))
+		if err := convert.Export(s.Converter, src, installedPath); err != nil {
+			removeAllFn(tempDir)
+			return "", "", "", err
+		}
+	}
+	return tempDir, src, installedPath, nil
+}
@@ -1,8 +1,10 @@
 package tui
 
 import (
+	"skli/internal/scaffold"
 	"skli/internal/tui/screens/config"
 	"skli/internal/tui/screens/manage"
+	"skli/internal/tui/screens/newskill"
 	"skli/internal/tui/screens/remote"
 	"skli/internal/tui/screens/scanning"
 
@@ -16,13 +18,15 @@ type RootModel struct {
 	remotes         []string
 	skillsRoot      string
 	manageMode      manage.Mode
+	editors         []string          // Destinos fijados con --editor: se salta la pantalla de editores
+	values          map[string]string // Valores de variables dados con --set
 	quitting        bool
 	windowWidth     int
 	windowHeight    int
 }
 
 // NewRootModel crea el modelo principal
-func NewRootModel(initialURL, skillsRoot, configLocalPath string, configMode bool, manageMode manage.Mode, remotes []string) RootModel {
+func NewRootModel(initialURL, skillsRoot, configLocalPath string, configMode bool, manageMode manage.Mode, remotes []string, editors []string, values map[string]string) RootModel {
 	var activeScreen tea.Model
 
 	switch {
@@ -42,6 +46,16 @@ func NewRootModel(initialURL, skillsRoot, configLocalPath string, configMode boo
 		remotes:         remotes,
 		skillsRoot:      skillsRoot,
 		manageMode:      manageMode,
+		editors:         editors,
+		values:          values,
+	}
+}
+
+// NewRootModelForNewSkill crea el modelo principal con el formulario de 'skli new'
+func NewRootModelForNewSkill(templates []scaffold.Template, skillsRoot string, initial scaffold.Options, template string) RootModel {
+	return RootModel{
+		activeScreen: newskill.NewNewSkillScreen(templates, skillsRoot, initial, template),
+		skillsRoot:   skillsRoot,
 	}
 }
 
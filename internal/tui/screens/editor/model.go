@@ -1,6 +1,10 @@
 package editor
 
 import (
+	"strings"
+
+	"skli/internal/editors"
+	"skli/internal/project"
 	"skli/internal/tui/screens/editor/delegates"
 	"skli/internal/tui/shared"
 
@@ -15,19 +19,70 @@ const (
 	StateInputCustom
 )
 
-// editorItem implementa list.DefaultItem para un editor
+// editorItem implementa list.DefaultItem para un destino. Sin destino es la opción de
+// ruta propia, salvo que agrupe todos los destinos detectados.
 type editorItem struct {
-	editor shared.Editor
+	target   editors.Target
+	detected bool
+	all      []editors.Target // Opción "todos los detectados"
 }
 
-func (i editorItem) Title() string { return i.editor.Name }
+func (i editorItem) Title() string {
+	switch {
+	case i.all != nil:
+		return "All detected editors"
+	case i.target == nil:
+		return "Custom"
+	}
+	return i.target.Name()
+}
 func (i editorItem) Description() string {
-	if i.editor.Path != "" {
-		return "(" + i.editor.Path + ")"
+	switch {
+	case i.all != nil:
+		names := make([]string, len(i.all))
+		for j, t := range i.all {
+			names[j] = t.Name()
+		}
+		return "(" + strings.Join(names, ", ") + ")"
+	case i.target == nil:
+		return "Custom path"
+	}
+	desc := "(" + i.target.ProjectPath() + ")"
+	if converter := editors.Converter(i.target); converter != "" {
+		desc = "(" + i.target.ProjectPath() + ", converted with " + converter + ")"
+	}
+	if i.detected {
+		desc += " detected in this project"
+	}
+	return desc
+}
+func (i editorItem) FilterValue() string {
+	if i.target == nil {
+		return i.Title()
+	}
+	return i.Title() + " " + i.target.ID()
+}
+
+// editorItems devuelve los destinos detectados en el proyecto, el resto de destinos y la
+// opción de ruta propia. Con withAll y varios detectados añade antes la opción de instalar en todos.
+func editorItems(withAll bool) []list.Item {
+	detected := editors.Detected(project.Root())
+	isDetected := make(map[string]bool, len(detected))
+	var items []list.Item
+	if withAll && len(detected) > 1 {
+		items = append(items, editorItem{all: detected})
+	}
+	for _, t := range detected {
+		isDetected[t.ID()] = true
+		items = append(items, editorItem{target: t, detected: true})
+	}
+	for _, t := range editors.All() {
+		if !isDetected[t.ID()] {
+			items = append(items, editorItem{target: t})
+		}
 	}
-	return "Custom path"
+	return append(items, editorItem{})
 }
-func (i editorItem) FilterValue() string { return i.editor.Name }
 
 // EditorScreen es el modelo para la pantalla de selección de editor
 type EditorScreen struct {
@@ -45,10 +100,7 @@ type EditorScreen struct {
 
 // NewEditorScreen crea una nueva pantalla de selección de editor
 func NewEditorScreen(skills []shared.Skill, tempDir, remoteURL, skillsRoot, commitHash string, configMode bool, remotes []string) EditorScreen {
-	items := make([]list.Item, len(shared.Editors))
-	for i, ed := range shared.Editors {
-		items[i] = editorItem{editor: ed}
-	}
+	items := editorItems(true)
 
 	delegate := delegates.NewEditorDelegate()
 	l := list.New(items, delegate, 60, 15)
@@ -81,26 +133,14 @@ func NewEditorScreen(skills []shared.Skill, tempDir, remoteURL, skillsRoot, comm
 
 // NewEditorScreenForConfig crea una pantalla de editor desde config
 func NewEditorScreenForConfig(currentPath string, remotes []string) EditorScreen {
-	items := make([]list.Item, len(shared.Editors))
-	cursor := 0
-	for i, ed := range shared.Editors {
-		items[i] = editorItem{editor: ed}
-		if ed.Path == currentPath && ed.Path != "" {
-			cursor = i
-		}
-	}
-	if cursor == 0 && currentPath != "" {
-		// Check if it matches any known editor
-		found := false
-		for _, ed := range shared.Editors {
-			if ed.Path == currentPath {
-				found = true
-				break
+	items := editorItems(false)
+	cursor := len(items) - 1 // Custom
+	if t, ok := editors.ForPath(currentPath); ok {
+		for i, item := range items {
+			if it := item.(editorItem); it.target != nil && it.target.ID() == t.ID() {
+				cursor = i
 			}
 		}
-		if !found {
-			cursor = len(shared.Editors) - 1 // Custom
-		}
 	}
 
 	delegate := delegates.NewEditorDelegate()
@@ -115,7 +155,7 @@ func NewEditorScreenForConfig(currentPath string, remotes []string) EditorScreen
 
 	ti := textinput.New()
 	ti.Placeholder = "/path/to/custom/folder"
-	if cursor == len(shared.Editors)-1 && currentPath != "" {
+	if cursor == len(items)-1 && currentPath != "" {
 		ti.SetValue(currentPath)
 	}
 	ti.CharLimit = 256
@@ -48,14 +48,16 @@ func (s EditorScreen) updateSelecting(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			item := selected.(editorItem)
 
-			if item.editor.Name == "Custom" {
+			if item.all != nil {
+				return s.proceedWithDestinations(shared.TargetDestinations(item.all))
+			}
+			if item.target == nil {
 				s.State = StateInputCustom
 				s.TextInput.Focus()
 				return s, textinput.Blink
 			}
 
-			destPath := item.editor.Path
-			return s.proceedWithSelection(destPath)
+			return s.proceedWithSelection(item.target.ProjectPath(), item.target.ID())
 		}
 	}
 
@@ -77,7 +79,7 @@ func (s EditorScreen) updateInputCustom(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if path == "" {
 				return s, nil
 			}
-			return s.proceedWithSelection(path)
+			return s.proceedWithSelection(path, "")
 		}
 	}
 
@@ -85,13 +87,18 @@ func (s EditorScreen) updateInputCustom(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return s, cmd
 }
 
-func (s EditorScreen) proceedWithSelection(destPath string) (tea.Model, tea.Cmd) {
+// proceedWithSelection continúa con la carpeta elegida; editor es el id del destino (vacío = ruta propia)
+func (s EditorScreen) proceedWithSelection(destPath, editor string) (tea.Model, tea.Cmd) {
 	if s.ConfigMode {
 		return s, tea.Batch(
 			shared.SaveConfigCmd(destPath, s.Remotes, true),
 		)
 	}
+	return s.proceedWithDestinations([]shared.Destination{{Path: destPath, Editor: editor}})
+}
 
+// proceedWithDestinations instala los skills seleccionados en los destinos
+func (s EditorScreen) proceedWithDestinations(dests []shared.Destination) (tea.Model, tea.Cmd) {
 	var selectedSkills []gitrepo.SkillInfo
 	for _, sk := range s.Skills {
 		if sk.Selected {
@@ -101,12 +108,12 @@ func (s EditorScreen) proceedWithSelection(destPath string) (tea.Model, tea.Cmd)
 
 	return s, func() tea.Msg {
 		return shared.NavigateToProgressMsg{
-			TempDir:         s.TempDir,
-			RemoteURL:       s.RemoteURL,
-			SkillsRoot:      s.SkillsRoot,
-			ConfigLocalPath: destPath,
-			CommitHash:      s.CommitHash,
-			Selected:        selectedSkills,
+			TempDir:      s.TempDir,
+			RemoteURL:    s.RemoteURL,
+			SkillsRoot:   s.SkillsRoot,
+			Destinations: dests,
+			CommitHash:   s.CommitHash,
+			Selected:     selectedSkills,
 		}
 	}
 }
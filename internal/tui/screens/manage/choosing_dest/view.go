@@ -0,0 +1,26 @@
+package choosing_dest
+
+import (
+	"fmt"
+	"skli/internal/tui/shared"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+func View(destList list.Model, destInput textinput.Model, typing bool, msg string) string {
+	var out string
+	if typing {
+		out = fmt.Sprintf("\n  %s\n\n  Enter the path of the skill in the target repository:\n\n  %s\n\n  %s",
+			destList.Title,
+			destInput.View(),
+			shared.HelpStyle.Render("enter confirm • esc back"),
+		)
+	} else {
+		out = "\n" + destList.View()
+	}
+	if msg != "" {
+		out += "\n\n  " + shared.InfoStyle.Render(msg)
+	}
+	return out
+}
@@ -5,21 +5,30 @@ import (
 	"os"
 	"path/filepath"
 
+	"skli/internal/config"
 	"skli/internal/db"
 	"skli/internal/gitrepo"
+	"skli/internal/project"
 	skillsvc "skli/internal/skills"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-type UploadResult struct {
-	SkillName string
-	PRURL     string
-	Err       error
+type UploadSkillsMsg struct {
+	Result     gitrepo.UploadResult
+	Registered int // Skills registrados en skli.lock tras la subida
+	Err        error
 }
 
-type UploadSkillsMsg struct {
-	Results []UploadResult
+// InspectTargetMsg trae los skills que ya tiene el repo destino antes de subir
+type InspectTargetMsg struct {
+	Layout gitrepo.RepoLayout
+	Err    error
+}
+
+type ContributeSkillMsg struct {
+	Result gitrepo.ContributeResult
+	Err    error
 }
 
 type DeleteSkillsMsg struct {
@@ -27,17 +36,40 @@ type DeleteSkillsMsg struct {
 	Err     error
 }
 
-func UploadSkillsCmd(selectedSkills []db.InstalledSkill, targetRemoteURL string) tea.Cmd {
+func InspectTargetCmd(targetRemoteURL string) tea.Cmd {
 	return func() tea.Msg {
-		results := make([]UploadResult, 0, len(selectedSkills))
-		for _, sk := range selectedSkills {
-			prURL, err := gitrepo.UploadSkill(sk, targetRemoteURL)
-			results = append(results, UploadResult{SkillName: sk.Name, PRURL: prURL, Err: err})
+		layout, err := gitrepo.InspectRepoLayout(targetRemoteURL)
+		return InspectTargetMsg{Layout: layout, Err: err}
+	}
+}
+
+func UploadSkillsCmd(selectedSkills []db.InstalledSkill, targetRemoteURL string, dests map[string]string, register bool) tea.Cmd {
+	return func() tea.Msg {
+		result, err := gitrepo.UploadSkills(selectedSkills, targetRemoteURL, dests, prOptions())
+		if err != nil || !register {
+			return UploadSkillsMsg{Result: result, Err: err}
+		}
+		registered, err := skillsvc.RegisterUploads(selectedSkills, targetRemoteURL, result)
+		if err != nil {
+			err = fmt.Errorf("PR created (%s) but the skills were not registered: %w", result.PRURL, err)
 		}
-		return UploadSkillsMsg{Results: results}
+		return UploadSkillsMsg{Result: result, Registered: len(registered), Err: err}
+	}
+}
+
+func ContributeSkillCmd(skill db.InstalledSkill) tea.Cmd {
+	return func() tea.Msg {
+		result, err := gitrepo.ContributeSkill(skill, prOptions())
+		return ContributeSkillMsg{Result: result, Err: err}
 	}
 }
 
+// prOptions lee de la configuración las opciones de las PRs creadas desde la TUI
+func prOptions() gitrepo.PROptions {
+	cfg, _ := config.LoadConfig()
+	return gitrepo.NewPROptions(cfg.PullRequests)
+}
+
 func DeleteSkillsCmd(selectedSkills []db.InstalledSkill) tea.Cmd {
 	return func() tea.Msg {
 		if len(selectedSkills) == 0 {
@@ -50,7 +82,7 @@ func DeleteSkillsCmd(selectedSkills []db.InstalledSkill) tea.Cmd {
 			if err := skillsvc.IsSafeDeletePath(sk.Path, skillParentDir); err != nil {
 				return DeleteSkillsMsg{Err: err}
 			}
-			if err := os.RemoveAll(sk.Path); err != nil {
+			if err := os.RemoveAll(project.Resolve(sk.Path)); err != nil {
 				return DeleteSkillsMsg{Err: err}
 			}
 			if err := db.DeleteInstalledSkill(sk.Path); err != nil {
@@ -2,8 +2,12 @@ package manage
 
 import (
 	"fmt"
+	"path/filepath"
+
 	"skli/internal/db"
+	"skli/internal/gitrepo"
 	"skli/internal/skills"
+	"skli/internal/tui/screens/manage/commands"
 	"skli/internal/tui/screens/manage/delegates"
 	"skli/internal/tui/shared"
 
@@ -21,6 +25,7 @@ const (
 	StateSelectingRemote
 	StateInputRemote
 	StateUploading
+	StateChoosingDest
 )
 
 type Mode int
@@ -48,6 +53,17 @@ type ManageScreen struct {
 	ConfigRemotes []string           // Remotes configurados
 	TargetRemote  string
 	skillsRoot    string
+
+	// Subida en curso: skills nuevos pendientes de elegir ruta en el repo destino
+	PendingUpload []db.InstalledSkill
+	PendingRemote string
+	Layout        gitrepo.RepoLayout
+	DestQueue     []db.InstalledSkill
+	Dests         map[string]string
+	DestList      list.Model
+	DestInput     textinput.Model
+	DestTyping    bool
+	Register      bool // Registrar en skli.lock los skills subidos, pendientes de la PR
 }
 
 // NewManageScreen crea una nueva pantalla de gestion
@@ -55,8 +71,12 @@ func NewManageScreen(remotes []string, mode Mode, skillsRoot string) (ManageScre
 	lock, _ := db.LoadLockFile()
 	localOnly, _ := skills.ScanLocalUnmanaged(lock.Skills, skillsRoot)
 	managedByPath := make(map[string]bool, len(lock.Skills))
+	modifiedByPath := make(map[string]bool)
 	for _, sk := range lock.Skills {
 		managedByPath[sk.Path] = true
+		if mode == ModeManage || mode == ModeList {
+			modifiedByPath[sk.Path] = skills.IsModified(sk)
+		}
 	}
 
 	var sourceSkills []db.InstalledSkill
@@ -71,14 +91,23 @@ func NewManageScreen(remotes []string, mode Mode, skillsRoot string) (ManageScre
 	items := make([]list.Item, len(sourceSkills))
 	for i, sk := range sourceSkills {
 		displaySkill := sk
+		modified := modifiedByPath[sk.Path]
 		if mode == ModeList {
 			label := "local"
 			if managedByPath[sk.Path] {
 				label = "installed"
 			}
+			if sk.IsPending() {
+				label = "pending PR"
+			}
+			if modified {
+				label += ", modified"
+			}
 			displaySkill.Description = fmt.Sprintf("[%s] %s", label, sk.Path)
+		} else if modified {
+			displaySkill.Description = "[modified] " + sk.Description
 		}
-		skills[i] = managedSkill{Skill: displaySkill}
+		skills[i] = managedSkill{Skill: displaySkill, Source: sk, Modified: modified}
 		items[i] = InstalledSkillItem{Skill: &skills[i]}
 	}
 
@@ -90,12 +119,14 @@ func NewManageScreen(remotes []string, mode Mode, skillsRoot string) (ManageScre
 	l.SetStatusBarItemName("skill", "skills")
 	l.SetFilteringEnabled(true)
 	l.SetShowFilter(true)
+	l.Filter = shared.TagFilter
 	l.FilterInput.Prompt = "Search: "
 	l.AdditionalShortHelpKeys = func() []key.Binding {
 		switch mode {
 		case ModeManage:
 			return []key.Binding{
 				key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "upload PR")),
+				key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "contribute")),
 				key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete")),
 			}
 		case ModeRemove:
@@ -107,9 +138,11 @@ func NewManageScreen(remotes []string, mode Mode, skillsRoot string) (ManageScre
 			return []key.Binding{
 				key.NewBinding(key.WithKeys("space"), key.WithHelp("space", "mark")),
 				key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "upload")),
+				key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "register")),
 			}
 		case ModeList:
 			return []key.Binding{
+				key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "contribute")),
 				key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
 			}
 		}
@@ -155,18 +188,26 @@ func (s ManageScreen) Init() tea.Cmd {
 // Item types and delegates
 
 type managedSkill struct {
-	Skill    db.InstalledSkill
+	Skill    db.InstalledSkill // Skill con la descripción adaptada a la vista
+	Source   db.InstalledSkill // Entrada original del lock o del escaneo local
 	Selected bool
+	Modified bool // Skill instalado con cambios locales respecto al lock
 }
 
 type InstalledSkillItem struct {
 	Skill *managedSkill
 }
 
-func (i InstalledSkillItem) Title() string       { return i.Skill.Skill.Name }
-func (i InstalledSkillItem) Description() string { return i.Skill.Skill.Description }
-func (i InstalledSkillItem) FilterValue() string { return i.Skill.Skill.Name }
-func (i InstalledSkillItem) IsSelected() bool    { return i.Skill.Selected }
+func (i InstalledSkillItem) Title() string {
+	return shared.SkillTitle(i.Skill.Skill.Name, i.Skill.Skill.Fields)
+}
+func (i InstalledSkillItem) Description() string {
+	return shared.SkillDescription(i.Skill.Skill.Description, i.Skill.Skill.Fields)
+}
+func (i InstalledSkillItem) FilterValue() string {
+	return shared.SkillFilterValue(i.Skill.Skill.Name, i.Skill.Skill.Fields)
+}
+func (i InstalledSkillItem) IsSelected() bool { return i.Skill.Selected }
 
 type remoteItem struct {
 	url         string
@@ -182,6 +223,20 @@ func (i remoteItem) Title() string {
 func (i remoteItem) Description() string { return "" }
 func (i remoteItem) FilterValue() string { return i.url }
 
+type destItem struct {
+	path string
+}
+
+func (i destItem) Title() string       { return i.path }
+func (i destItem) Description() string { return "" }
+func (i destItem) FilterValue() string { return i.path }
+
+type customPathItem struct{}
+
+func (i customPathItem) Title() string       { return "✏️  Custom path..." }
+func (i customPathItem) Description() string { return "Enter a path manually" }
+func (i customPathItem) FilterValue() string { return "custom path" }
+
 type customURLItem struct{}
 
 func (i customURLItem) Title() string       { return "✏️  Custom URL..." }
@@ -223,12 +278,90 @@ func (s ManageScreen) selectedSkills() []db.InstalledSkill {
 	out := make([]db.InstalledSkill, 0)
 	for _, sk := range s.Skills {
 		if sk.Selected {
-			out = append(out, sk.Skill)
+			out = append(out, sk.Source)
 		}
 	}
 	return out
 }
 
+// contributeCurrent lanza la contribución del skill seleccionado si es un skill instalado con cambios
+func (s ManageScreen) contributeCurrent() (ManageScreen, tea.Cmd) {
+	item, ok := s.List.SelectedItem().(InstalledSkillItem)
+	if !ok || item.Skill == nil {
+		return s, nil
+	}
+	if !item.Skill.Modified {
+		s.Msg = fmt.Sprintf("'%s' has no local changes to contribute", item.Skill.Source.Name)
+		return s, nil
+	}
+	sk := item.Skill.Source
+	s.SelectedSkill = &sk
+	s.State = StateUploading
+	s.Msg = fmt.Sprintf("Contributing '%s' to %s...", sk.Name, sk.RemoteRepo)
+	return s, commands.ContributeSkillCmd(sk)
+}
+
+// startUpload inspecciona el repo destino antes de subir para poder elegir la ruta de los skills nuevos
+func (s ManageScreen) startUpload(selected []db.InstalledSkill, remote string) (ManageScreen, tea.Cmd) {
+	s.PendingUpload = selected
+	s.PendingRemote = remote
+	s.Dests = make(map[string]string)
+	s.State = StateUploading
+	s.Msg = fmt.Sprintf("Inspecting %s...", remote)
+	return s, commands.InspectTargetCmd(remote)
+}
+
+// nextDestination pide la ruta del siguiente skill nuevo o, si no quedan, lanza la subida
+func (s ManageScreen) nextDestination() (ManageScreen, tea.Cmd) {
+	if len(s.DestQueue) == 0 {
+		s.State = StateUploading
+		s.Msg = fmt.Sprintf("Uploading %d skill(s) to %s...", len(s.PendingUpload), s.PendingRemote)
+		return s, commands.UploadSkillsCmd(s.PendingUpload, s.PendingRemote, s.Dests, s.Register)
+	}
+
+	skill := s.DestQueue[0]
+	suggestions := s.Layout.Suggest(filepath.Base(skill.Path))
+	items := make([]list.Item, 0, len(suggestions)+1)
+	for _, p := range suggestions {
+		items = append(items, destItem{path: p})
+	}
+	items = append(items, customPathItem{})
+
+	l := list.New(items, delegates.NewRemoteDelegate(), 60, 14)
+	l.Title = fmt.Sprintf("Destination for new skill '%s'", skill.Name)
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.Styles.Title = shared.TitleStyle
+
+	ti := textinput.New()
+	ti.Placeholder = "skills/" + filepath.Base(skill.Path)
+	if len(suggestions) > 0 {
+		ti.SetValue(suggestions[0])
+	}
+	ti.CharLimit = 256
+	ti.Width = 50
+
+	s.DestList = l
+	s.DestInput = ti
+	s.DestTyping = false
+	s.State = StateChoosingDest
+	s.Msg = ""
+	return s, nil
+}
+
+// chooseDestination valida la ruta elegida para el skill actual y pasa al siguiente
+func (s ManageScreen) chooseDestination(dest string) (ManageScreen, tea.Cmd) {
+	skill := s.DestQueue[0]
+	if err := s.Layout.CheckDestination(skill.Name, dest); err != nil {
+		s.Msg = fmt.Sprintf("Error: %v", err)
+		return s, nil
+	}
+	cleaned, _ := gitrepo.CleanDestination(dest)
+	s.Dests[skill.Name] = cleaned
+	s.DestQueue = s.DestQueue[1:]
+	return s.nextDestination()
+}
+
 func (s ManageScreen) toggleSelectedCurrent() ManageScreen {
 	item, ok := s.List.SelectedItem().(InstalledSkillItem)
 	if !ok || item.Skill == nil {
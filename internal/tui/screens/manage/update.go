@@ -26,6 +26,8 @@ func (s ManageScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return s.updateInputRemote(msg)
 	case StateUploading:
 		return s.updateUploading(msg)
+	case StateChoosingDest:
+		return s.updateChoosingDest(msg)
 	}
 	return s, nil
 }
@@ -50,6 +52,8 @@ func (s ManageScreen) updateList(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch s.Mode {
 		case ModeList:
 			switch msg.String() {
+			case "c":
+				return s.contributeCurrent()
 			case "esc", "q":
 				return s, tea.Quit
 			}
@@ -81,9 +85,15 @@ func (s ManageScreen) updateList(msg tea.Msg) (tea.Model, tea.Cmd) {
 					s.Msg = "Select at least one skill to upload"
 					return s, nil
 				}
-				s.State = StateUploading
-				s.Msg = fmt.Sprintf("Uploading %d skill(s) to %s...", len(selected), s.TargetRemote)
-				return s, commands.UploadSkillsCmd(selected, s.TargetRemote)
+				return s.startUpload(selected, s.TargetRemote)
+			case "r":
+				s.Register = !s.Register
+				if s.Register {
+					s.Msg = "Uploaded skills will be registered in skli.lock"
+				} else {
+					s.Msg = "Uploaded skills will not be registered"
+				}
+				return s, nil
 			case "esc":
 				s.State = StateSelectingRemote
 				return s, nil
@@ -95,7 +105,7 @@ func (s ManageScreen) updateList(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.String() {
 			case "enter", "d", "backspace":
 				if item, ok := s.List.SelectedItem().(InstalledSkillItem); ok && item.Skill != nil {
-					sk := item.Skill.Skill
+					sk := item.Skill.Source
 					s.ToDelete = &sk
 					s.State = StateConfirm
 					s.ConfirmCursor = 1
@@ -103,18 +113,20 @@ func (s ManageScreen) updateList(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			case "esc", "q":
 				return s, tea.Quit
+			case "c":
+				return s.contributeCurrent()
 			case "u":
 				if item, ok := s.List.SelectedItem().(InstalledSkillItem); ok && item.Skill != nil {
-					s.SelectedSkill = &item.Skill.Skill
+					s.SelectedSkill = &item.Skill.Source
 					var items []list.Item
 					seen := make(map[string]bool)
 
-					if item.Skill.Skill.RemoteRepo != "" {
+					if item.Skill.Source.RemoteRepo != "" {
 						items = append(items, remoteItem{
-							url:         item.Skill.Skill.RemoteRepo,
-							displayName: fmt.Sprintf("%s (Origin)", item.Skill.Skill.RemoteRepo),
+							url:         item.Skill.Source.RemoteRepo,
+							displayName: fmt.Sprintf("%s (Origin)", item.Skill.Source.RemoteRepo),
 						})
-						seen[item.Skill.Skill.RemoteRepo] = true
+						seen[item.Skill.Source.RemoteRepo] = true
 					}
 
 					for _, r := range s.ConfigRemotes {
@@ -176,9 +188,7 @@ func (s ManageScreen) updateSelectingRemote(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return s, nil
 				}
 
-				s.State = StateUploading
-				s.Msg = fmt.Sprintf("Starting upload of '%s' to %s...", s.SelectedSkill.Name, item.url)
-				return s, commands.UploadSkillsCmd([]db.InstalledSkill{*s.SelectedSkill}, item.url)
+				return s.startUpload([]db.InstalledSkill{*s.SelectedSkill}, item.url)
 			case customURLItem:
 				s.State = StateInputRemote
 				s.RemoteInput.Focus()
@@ -212,9 +222,7 @@ func (s ManageScreen) updateInputRemote(msg tea.Msg) (tea.Model, tea.Cmd) {
 					s.Msg = fmt.Sprintf("Target selected: %s", url)
 					return s, nil
 				}
-				s.State = StateUploading
-				s.Msg = fmt.Sprintf("Starting upload of '%s' to %s...", s.SelectedSkill.Name, url)
-				return s, commands.UploadSkillsCmd([]db.InstalledSkill{*s.SelectedSkill}, url)
+				return s.startUpload([]db.InstalledSkill{*s.SelectedSkill}, url)
 			}
 		}
 	}
@@ -267,23 +275,103 @@ func (s ManageScreen) updateConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return s, nil
 }
 
+func (s ManageScreen) updateChoosingDest(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		s.DestList.SetSize(msg.Width, msg.Height-4)
+		return s, nil
+
+	case tea.KeyMsg:
+		if s.DestTyping {
+			switch msg.String() {
+			case "esc":
+				s.DestTyping = false
+				s.Msg = ""
+				return s, nil
+			case "enter":
+				return s.chooseDestination(s.DestInput.Value())
+			}
+			var cmd tea.Cmd
+			s.DestInput, cmd = s.DestInput.Update(msg)
+			return s, cmd
+		}
+
+		switch msg.String() {
+		case "esc":
+			s.State = StateList
+			s.Msg = "Upload cancelled"
+			return s, nil
+		case "enter":
+			switch item := s.DestList.SelectedItem().(type) {
+			case destItem:
+				return s.chooseDestination(item.path)
+			case customPathItem:
+				s.DestTyping = true
+				s.DestInput.Focus()
+				return s, textinput.Blink
+			}
+			return s, nil
+		}
+	}
+	var cmd tea.Cmd
+	s.DestList, cmd = s.DestList.Update(msg)
+	return s, cmd
+}
+
 func (s ManageScreen) updateUploading(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case commands.InspectTargetMsg:
+		if msg.Err != nil {
+			s.Msg = fmt.Sprintf("Error: %v", msg.Err)
+			return s, nil
+		}
+		s.Layout = msg.Layout
+		s.DestQueue = nil
+		for _, sk := range s.PendingUpload {
+			if _, ok := s.Layout.Find(sk.Name); !ok {
+				s.DestQueue = append(s.DestQueue, sk)
+			}
+		}
+		return s.nextDestination()
+	case commands.ContributeSkillMsg:
+		if msg.Err != nil {
+			s.Msg = fmt.Sprintf("Error: %v", msg.Err)
+			return s, nil
+		}
+		s.Msg = fmt.Sprintf("✔ %s → %s (rebased onto %s)", s.SelectedSkill.Name, msg.Result.RepoPath, msg.Result.Base)
+		for _, w := range msg.Result.Warnings {
+			s.Msg += "\n⚠ " + w
+		}
+		if msg.Result.Fork != "" {
+			s.Msg += fmt.Sprintf("\nPushed to fork %s", msg.Result.Fork)
+		}
+		s.Msg += fmt.Sprintf("\nPR: %s", msg.Result.PRURL)
+		return s, nil
 	case commands.UploadSkillsMsg:
 		var lines []string
-		okCount := 0
-		for _, result := range msg.Results {
-			if result.Err != nil {
-				lines = append(lines, fmt.Sprintf("✘ %s: %v", result.SkillName, result.Err))
-				continue
+		okCount := len(msg.Result.Skills)
+		if msg.Err != nil {
+			lines = append(lines, fmt.Sprintf("Error: %v", msg.Err))
+		} else {
+			for _, sk := range msg.Result.Skills {
+				lines = append(lines, fmt.Sprintf("✔ %s → %s", sk.Name, sk.RepoPath))
+			}
+			for _, name := range msg.Result.Unchanged {
+				lines = append(lines, fmt.Sprintf("○ %s unchanged", name))
+			}
+			for _, w := range msg.Result.Warnings {
+				lines = append(lines, "⚠ "+w)
+			}
+			if msg.Result.Fork != "" {
+				lines = append(lines, fmt.Sprintf("Pushed to fork %s", msg.Result.Fork))
 			}
-			okCount++
-			lines = append(lines, fmt.Sprintf("✔ %s: %s", result.SkillName, result.PRURL))
+			lines = append(lines, fmt.Sprintf("PR: %s", msg.Result.PRURL))
 		}
 		s.Msg = strings.Join(lines, "\n")
 		if s.Mode == ModeUpload {
 			refreshed, _ := NewManageScreen(s.ConfigRemotes, s.Mode, s.skillsRoot)
 			refreshed.TargetRemote = s.TargetRemote
+			refreshed.Register = s.Register
 			refreshed.State = StateList
 			refreshed.Msg = s.Msg
 			if okCount > 0 {
@@ -6,7 +6,7 @@ import (
 )
 
 func View(msg string) string {
-	return fmt.Sprintf("\n  Uploading skill...\n\n  %s\n\n  %s",
+	return fmt.Sprintf("\n  Uploading skills...\n\n  %s\n\n  %s",
 		shared.InfoStyle.Render(msg),
 		shared.HelpStyle.Render("esc/enter: back"),
 	)
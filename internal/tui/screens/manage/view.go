@@ -1,6 +1,7 @@
 package manage
 
 import (
+	"skli/internal/tui/screens/manage/choosing_dest"
 	"skli/internal/tui/screens/manage/confirm"
 	"skli/internal/tui/screens/manage/input_remote"
 	"skli/internal/tui/screens/manage/list_view"
@@ -20,6 +21,8 @@ func (s ManageScreen) View() string {
 		return input_remote.View(s.RemoteInput)
 	case StateUploading:
 		return uploading.View(s.Msg)
+	case StateChoosingDest:
+		return choosing_dest.View(s.DestList, s.DestInput, s.DestTyping, s.Msg)
 	}
 	return ""
 }
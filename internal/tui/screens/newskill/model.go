@@ -0,0 +1,83 @@
+package newskill
+
+import (
+	"strings"
+
+	"skli/internal/scaffold"
+
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+type State int
+
+const (
+	StateEditing State = iota
+	StateCreating
+	StateDone
+)
+
+// Campos del formulario; el último es el selector de plantilla
+const (
+	fieldName = iota
+	fieldDescription
+	fieldTags
+	fieldTemplate
+)
+
+// NewSkillScreen es el formulario de 'skli new'
+type NewSkillScreen struct {
+	State        State
+	Inputs       []textinput.Model // Nombre, descripción y etiquetas
+	Focus        int
+	Templates    []scaffold.Template
+	Template     int // Plantilla seleccionada
+	SkillsRoot   string
+	ErrorMessage string
+	Path         string   // Skill creado
+	Warnings     []string // Avisos de validación del skill creado
+}
+
+// NewNewSkillScreen crea el formulario con los valores iniciales de la línea de comandos
+func NewNewSkillScreen(templates []scaffold.Template, skillsRoot string, initial scaffold.Options, template string) NewSkillScreen {
+	name := textinput.New()
+	name.Placeholder = "my-skill"
+	name.CharLimit = 64
+	name.Width = 50
+	name.SetValue(initial.Name)
+
+	description := textinput.New()
+	description.Placeholder = "What the skill does and when to use it"
+	description.CharLimit = 1024
+	description.Width = 50
+	description.SetValue(initial.Description)
+
+	tags := textinput.New()
+	tags.Placeholder = "go, testing"
+	tags.Width = 50
+	tags.SetValue(strings.Join(initial.Tags, ", "))
+
+	s := NewSkillScreen{
+		Inputs:     []textinput.Model{name, description, tags},
+		Templates:  templates,
+		SkillsRoot: skillsRoot,
+	}
+	for i, tpl := range templates {
+		if tpl.Name == template || (template == "" && tpl.Name == scaffold.DefaultTemplate) {
+			s.Template = i
+		}
+	}
+	if initial.Name != "" {
+		s.Focus = fieldDescription
+	}
+	s.Inputs[s.Focus].Focus()
+	return s
+}
+
+// options devuelve los datos introducidos en el formulario
+func (s NewSkillScreen) options() scaffold.Options {
+	return scaffold.Options{
+		Name:        s.Inputs[fieldName].Value(),
+		Description: s.Inputs[fieldDescription].Value(),
+		Tags:        scaffold.ParseTags(s.Inputs[fieldTags].Value()),
+	}
+}
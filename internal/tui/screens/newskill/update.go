@@ -0,0 +1,88 @@
+package newskill
+
+import (
+	"skli/internal/tui/shared"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (s NewSkillScreen) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (s NewSkillScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case shared.SkillCreatedMsg:
+		if msg.Err != nil && msg.Path == "" {
+			s.State = StateEditing
+			s.ErrorMessage = msg.Err.Error()
+			return s, nil
+		}
+		// Creado aunque no valide: se muestra el error junto a la ruta para corregirlo
+		s.State = StateDone
+		s.Path = msg.Path
+		s.Warnings = msg.Warnings
+		if msg.Err != nil {
+			s.ErrorMessage = msg.Err.Error()
+		}
+		return s, nil
+
+	case tea.KeyMsg:
+		switch s.State {
+		case StateCreating:
+			return s, nil
+		case StateDone:
+			return s, func() tea.Msg { return shared.QuitMsg{} }
+		}
+
+		switch msg.String() {
+		case "esc":
+			return s, func() tea.Msg { return shared.QuitMsg{} }
+		case "tab", "down":
+			return s.moveFocus(1)
+		case "shift+tab", "up":
+			return s.moveFocus(-1)
+		case "enter":
+			if s.Focus < fieldTemplate {
+				return s.moveFocus(1)
+			}
+			s.State = StateCreating
+			s.ErrorMessage = ""
+			tpl := s.Templates[s.Template]
+			return s, shared.CreateSkillCmd(tpl, s.SkillsRoot, s.options())
+		}
+
+		if s.Focus == fieldTemplate {
+			switch msg.String() {
+			case "left", "h":
+				s.Template = (s.Template + len(s.Templates) - 1) % len(s.Templates)
+			case "right", "l":
+				s.Template = (s.Template + 1) % len(s.Templates)
+			}
+			return s, nil
+		}
+	}
+
+	if s.State != StateEditing || s.Focus == fieldTemplate {
+		return s, nil
+	}
+	var cmd tea.Cmd
+	s.Inputs[s.Focus], cmd = s.Inputs[s.Focus].Update(msg)
+	return s, cmd
+}
+
+func (s NewSkillScreen) moveFocus(delta int) (tea.Model, tea.Cmd) {
+	next := s.Focus + delta
+	if next < fieldName || next > fieldTemplate {
+		return s, nil
+	}
+	if s.Focus < fieldTemplate {
+		s.Inputs[s.Focus].Blur()
+	}
+	s.Focus = next
+	if s.Focus < fieldTemplate {
+		return s, s.Inputs[s.Focus].Focus()
+	}
+	return s, nil
+}
@@ -0,0 +1,62 @@
+package newskill
+
+import (
+	"fmt"
+	"strings"
+
+	"skli/internal/tui/shared"
+)
+
+var labels = []string{"Name", "Description", "Tags"}
+
+func (s NewSkillScreen) View() string {
+	if s.State == StateDone {
+		return s.doneView()
+	}
+
+	var b strings.Builder
+	b.WriteString(shared.TitleStyle.Render("New skill") + "\n")
+	for i, input := range s.Inputs {
+		b.WriteString(fieldLabel(labels[i], s.Focus == i) + "\n")
+		b.WriteString("  " + input.View() + "\n\n")
+	}
+
+	b.WriteString(fieldLabel("Template", s.Focus == fieldTemplate) + "\n")
+	tpl := s.Templates[s.Template]
+	b.WriteString("  " + shared.SelectorOnStyle.Render("‹ "+tpl.Name+" ›") + " " + shared.DimStyle.Render(tpl.Description) + "\n")
+	if tpl.Source != "" {
+		b.WriteString("  " + shared.DimStyle.Render("from "+tpl.Source) + "\n")
+	}
+
+	if s.State == StateCreating {
+		b.WriteString("\n" + shared.InfoStyle.Render("Creating skill..."))
+	}
+	if s.ErrorMessage != "" {
+		b.WriteString(shared.ErrorStyle.Render("✘ "+s.ErrorMessage) + "\n")
+	}
+	b.WriteString(shared.HelpStyle.Render("\ntab/↑/↓ move • ←/→ template • enter next/create • esc quit"))
+	return b.String()
+}
+
+func fieldLabel(label string, focused bool) string {
+	if focused {
+		return shared.SelectedItemStyle.Render(shared.SelectorDot(true) + " " + label)
+	}
+	return shared.ItemStyle.Render(shared.SelectorDot(false) + " " + label)
+}
+
+func (s NewSkillScreen) doneView() string {
+	var b strings.Builder
+	if s.ErrorMessage != "" {
+		b.WriteString(shared.ErrorStyle.Render(fmt.Sprintf("✘ Skill created in ./%s/ but it does not validate:", s.Path)) + "\n")
+		b.WriteString(s.ErrorMessage + "\n")
+	} else {
+		b.WriteString(shared.SuccessStyle.Render(fmt.Sprintf("✔ Skill created in ./%s/", s.Path)) + "\n")
+	}
+	for _, w := range s.Warnings {
+		b.WriteString(shared.WarningStyle.Render("⚠ "+w) + "\n")
+	}
+	b.WriteString(shared.DimStyle.Render("\nEdit SKILL.md, then 'skli upload' to publish it.") + "\n")
+	b.WriteString(shared.HelpStyle.Render("Press any key to quit"))
+	return b.String()
+}
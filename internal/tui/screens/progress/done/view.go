@@ -5,12 +5,15 @@ import (
 	"skli/internal/tui/shared"
 )
 
-func View(configMode bool, configLocalPath string) string {
+func View(configMode bool, paths string, warnings []string) string {
 	var msg string
 	if configMode {
 		msg = shared.SuccessStyle.Render("✔ Configuration saved successfully!")
 	} else {
-		msg = shared.SuccessStyle.Render(fmt.Sprintf("✔ Skills installed successfully in ./%s/!", configLocalPath))
+		msg = shared.SuccessStyle.Render(fmt.Sprintf("✔ Skills installed successfully in %s!", paths))
+	}
+	for _, w := range warnings {
+		msg += "\n" + shared.WarningStyle.Render("⚠ "+w)
 	}
 	return msg + shared.HelpStyle.Render("\nPress any key to quit")
 }
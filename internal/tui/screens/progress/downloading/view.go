@@ -7,6 +7,6 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 )
 
-func View(s spinner.Model, configLocalPath string) string {
-	return fmt.Sprintf("%s Installing selected skills in %s...", s.View(), shared.InfoStyle.Render(configLocalPath))
+func View(s spinner.Model, paths string) string {
+	return fmt.Sprintf("%s Installing selected skills in %s...", s.View(), shared.InfoStyle.Render(paths))
 }
@@ -21,25 +21,28 @@ type ProgressScreen struct {
 	State           State
 	Spinner         spinner.Model
 	ConfigLocalPath string
+	Destinations    []shared.Destination
 	ConfigMode      bool
 	ErrorMessage    string
+	Warnings        []string // Skills sin soporte declarado para algún destino o con hallazgos de seguridad
 }
 
 // NewProgressScreenDownloading crea una pantalla de descarga con comando
-func NewProgressScreenDownloading(tempDir, remoteURL, skillsRoot, configLocalPath, commitHash string, selected []gitrepo.SkillInfo) (ProgressScreen, tea.Cmd) {
+func NewProgressScreenDownloading(tempDir, remoteURL, skillsRoot string, dests []shared.Destination, values map[string]string, commitHash string, selected []gitrepo.SkillInfo) (ProgressScreen, tea.Cmd) {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = shared.SpinnerStyle
 
 	screen := ProgressScreen{
-		State:           StateDownloading,
-		Spinner:         s,
-		ConfigLocalPath: configLocalPath,
+		State:        StateDownloading,
+		Spinner:      s,
+		Destinations: dests,
+		Warnings:     append(shared.EditorWarnings(selected, dests), shared.SecurityWarnings(selected)...),
 	}
 
 	return screen, tea.Batch(
 		s.Tick,
-		shared.DownloadSkillsCmd(tempDir, remoteURL, skillsRoot, configLocalPath, commitHash, selected),
+		shared.DownloadSkillsCmd(tempDir, remoteURL, skillsRoot, dests, values, commitHash, selected),
 	)
 }
 
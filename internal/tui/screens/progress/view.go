@@ -4,14 +4,15 @@ import (
 	"skli/internal/tui/screens/progress/done"
 	"skli/internal/tui/screens/progress/downloading"
 	"skli/internal/tui/screens/progress/error_view"
+	"skli/internal/tui/shared"
 )
 
 func (s ProgressScreen) View() string {
 	switch s.State {
 	case StateDownloading:
-		return downloading.View(s.Spinner, s.ConfigLocalPath)
+		return downloading.View(s.Spinner, shared.DestinationPaths(s.Destinations))
 	case StateDone:
-		return done.View(s.ConfigMode, s.ConfigLocalPath)
+		return done.View(s.ConfigMode, shared.DestinationPaths(s.Destinations), s.Warnings)
 	case StateError:
 		return error_view.View(s.ErrorMessage)
 	}
@@ -29,6 +29,7 @@ func (s ScanningScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				RemoteURL:  msg.RemoteURL,
 				SkillsRoot: msg.Result.SkillsPath,
 				CommitHash: msg.Result.CommitHash,
+				Warning:    msg.Warning,
 			}
 		}
 
@@ -1,6 +1,8 @@
 package skills
 
 import (
+	"fmt"
+
 	"skli/internal/gitrepo"
 	"skli/internal/tui/screens/skills/delegates"
 	"skli/internal/tui/shared"
@@ -13,9 +15,19 @@ type skillItem struct {
 	skill *shared.Skill // Referencia al skill original para mantener el estado de selección
 }
 
-func (i skillItem) Title() string       { return i.skill.Info.Name }
-func (i skillItem) Description() string { return i.skill.Info.Description }
-func (i skillItem) FilterValue() string { return i.skill.Info.Name }
+func (i skillItem) Title() string {
+	title := shared.SkillTitle(i.skill.Info.Name, i.skill.Info.Fields)
+	if n := len(i.skill.Info.Findings); n > 0 {
+		title += fmt.Sprintf(" ⚠ %d", n)
+	}
+	return title
+}
+func (i skillItem) Description() string {
+	return shared.SkillDescription(i.skill.Info.Description, i.skill.Info.Fields)
+}
+func (i skillItem) FilterValue() string {
+	return shared.SkillFilterValue(i.skill.Info.Name, i.skill.Info.Fields)
+}
 func (i skillItem) Toggle() {
 	i.skill.Selected = !i.skill.Selected
 }
@@ -30,6 +42,9 @@ type SkillsScreen struct {
 	SkillsRoot      string
 	CommitHash      string
 	ConfigLocalPath string
+	Confirming      bool   // Esperando que el usuario acepte los hallazgos de seguridad (política confirm)
+	ErrorMessage    string // Skills seleccionados que la política no deja instalar
+	Warning         string // Firma del commit no verificada
 }
 
 // NewSkillsScreen crea una nueva pantalla de selección de skills
@@ -47,6 +62,7 @@ func NewSkillsScreen(infos []gitrepo.SkillInfo, tempDir, remoteURL, skillsRoot,
 	l.SetShowStatusBar(true)
 	l.SetStatusBarItemName("skill", "skills")
 	l.SetFilteringEnabled(true)
+	l.Filter = shared.TagFilter
 	l.SetShowFilter(true)
 	l.FilterInput.Prompt = "Search: "
 	l.Styles.Title = shared.TitleStyle
@@ -1,12 +1,19 @@
 package skills
 
 import (
+	"errors"
+	"strings"
+
 	"skli/internal/gitrepo"
+	"skli/internal/security"
 	"skli/internal/tui/shared"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// findingsHeight son las líneas que ocupa el detalle de hallazgos bajo la lista
+const findingsHeight = 7
+
 func (s SkillsScreen) Init() tea.Cmd {
 	return nil
 }
@@ -14,41 +21,38 @@ func (s SkillsScreen) Init() tea.Cmd {
 func (s SkillsScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		s.List.SetSize(msg.Width, msg.Height-4)
+		s.List.SetSize(msg.Width, msg.Height-4-findingsHeight)
 		return s, nil
 
 	case tea.KeyMsg:
+		if s.Confirming {
+			switch msg.String() {
+			case "y", "Y":
+				s.Confirming = false
+				return s, s.install(s.selected())
+			case "n", "N", "esc":
+				s.Confirming = false
+			}
+			return s, nil
+		}
+
 		switch msg.String() {
 		case "enter":
-			var selected []gitrepo.SkillInfo
-			for _, sk := range s.Skills {
-				if sk.Selected {
-					selected = append(selected, sk.Info)
-				}
+			selected := s.selected()
+			if len(selected) == 0 {
+				break
+			}
+			s.ErrorMessage = ""
+			blocked, unconfirmed := checkPolicy(selected)
+			if len(blocked) > 0 {
+				s.ErrorMessage = "blocked by security policy: " + strings.Join(blocked, ", ") + " (deselect to continue)"
+				return s, nil
 			}
-			if len(selected) > 0 {
-				if s.ConfigLocalPath == "" {
-					return s, func() tea.Msg {
-						return shared.NavigateToEditorMsg{
-							Skills:     s.Skills,
-							TempDir:    s.TempDir,
-							RemoteURL:  s.RemoteURL,
-							SkillsRoot: s.SkillsRoot,
-							CommitHash: s.CommitHash,
-						}
-					}
-				}
-				return s, func() tea.Msg {
-					return shared.NavigateToProgressMsg{
-						TempDir:         s.TempDir,
-						RemoteURL:       s.RemoteURL,
-						SkillsRoot:      s.SkillsRoot,
-						ConfigLocalPath: s.ConfigLocalPath,
-						CommitHash:      s.CommitHash,
-						Selected:        selected,
-					}
-				}
+			if len(unconfirmed) > 0 {
+				s.Confirming = true
+				return s, nil
 			}
+			return s, s.install(selected)
 		}
 	}
 
@@ -56,3 +60,53 @@ func (s SkillsScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	s.List, cmd = s.List.Update(msg)
 	return s, cmd
 }
+
+// selected devuelve los skills marcados
+func (s SkillsScreen) selected() []gitrepo.SkillInfo {
+	var selected []gitrepo.SkillInfo
+	for _, sk := range s.Skills {
+		if sk.Selected {
+			selected = append(selected, sk.Info)
+		}
+	}
+	return selected
+}
+
+// checkPolicy devuelve los skills que la política de seguridad bloquea y los que pide confirmar
+func checkPolicy(selected []gitrepo.SkillInfo) (blocked, unconfirmed []string) {
+	for _, sk := range selected {
+		err := security.Enforce(sk.Findings, false)
+		switch {
+		case errors.Is(err, security.ErrBlocked):
+			blocked = append(blocked, sk.Name)
+		case errors.Is(err, security.ErrUnconfirmed):
+			unconfirmed = append(unconfirmed, sk.Name)
+		}
+	}
+	return blocked, unconfirmed
+}
+
+// install pasa a elegir editor o, con una ruta configurada, instala directamente
+func (s SkillsScreen) install(selected []gitrepo.SkillInfo) tea.Cmd {
+	if s.ConfigLocalPath == "" {
+		return func() tea.Msg {
+			return shared.NavigateToEditorMsg{
+				Skills:     s.Skills,
+				TempDir:    s.TempDir,
+				RemoteURL:  s.RemoteURL,
+				SkillsRoot: s.SkillsRoot,
+				CommitHash: s.CommitHash,
+			}
+		}
+	}
+	return func() tea.Msg {
+		return shared.NavigateToProgressMsg{
+			TempDir:      s.TempDir,
+			RemoteURL:    s.RemoteURL,
+			SkillsRoot:   s.SkillsRoot,
+			Destinations: []shared.Destination{{Path: s.ConfigLocalPath}},
+			CommitHash:   s.CommitHash,
+			Selected:     selected,
+		}
+	}
+}
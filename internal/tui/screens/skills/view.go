@@ -1,6 +1,40 @@
 package skills
 
+import (
+	"fmt"
+	"strings"
+
+	"skli/internal/security"
+	"skli/internal/tui/shared"
+)
+
 func (s SkillsScreen) View() string {
 	view := s.List.View()
+	if item, ok := s.List.SelectedItem().(skillItem); ok && len(item.skill.Info.Findings) > 0 {
+		view += "\n" + renderFindings(item.skill.Info.Findings)
+	}
+	if s.Warning != "" {
+		view += "\n" + shared.WarningStyle.Render("⚠ "+s.Warning)
+	}
+	if s.ErrorMessage != "" {
+		view += "\n" + shared.ErrorStyle.Render("✘ "+s.ErrorMessage)
+	}
+	if s.Confirming {
+		view += "\n" + shared.WarningStyle.Render(fmt.Sprintf("Some selected skills have security findings (policy: %s). Install anyway? (y/n)", security.CurrentPolicy()))
+	}
 	return view
 }
+
+// renderFindings muestra los primeros hallazgos del skill resaltado
+func renderFindings(findings []security.Finding) string {
+	const shown = findingsHeight - 2
+	lines := []string{shared.WarningStyle.Render("⚠ " + security.Summary(findings))}
+	for i, f := range findings {
+		if i == shown {
+			lines = append(lines, shared.DimStyle.Render(fmt.Sprintf("    … and %d more", len(findings)-shown)))
+			break
+		}
+		lines = append(lines, shared.DimStyle.Render("    "+f.String()))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,46 @@
+package variables
+
+import (
+	"skli/internal/skillmeta"
+	"skli/internal/tui/shared"
+
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+// VariablesScreen pide los valores de las variables de los skills antes de instalarlos
+type VariablesScreen struct {
+	Variables    []skillmeta.Variable
+	Inputs       []textinput.Model
+	Focus        int
+	Install      shared.NavigateToProgressMsg // Instalación que continúa con los valores
+	ErrorMessage string
+}
+
+// NewVariablesScreen crea el formulario con los valores por defecto de cada variable
+func NewVariablesScreen(install shared.NavigateToProgressMsg, vars []skillmeta.Variable) VariablesScreen {
+	inputs := make([]textinput.Model, len(vars))
+	for i, v := range vars {
+		ti := textinput.New()
+		ti.Placeholder = v.Description
+		ti.CharLimit = 512
+		ti.Width = 50
+		ti.SetValue(v.Default)
+		inputs[i] = ti
+	}
+	if len(inputs) > 0 {
+		inputs[0].Focus()
+	}
+	return VariablesScreen{Variables: vars, Inputs: inputs, Install: install}
+}
+
+// values devuelve los valores introducidos junto con los que ya traía la instalación
+func (s VariablesScreen) values() map[string]string {
+	values := make(map[string]string, len(s.Install.Values)+len(s.Inputs))
+	for name, value := range s.Install.Values {
+		values[name] = value
+	}
+	for i, v := range s.Variables {
+		values[v.Name] = s.Inputs[i].Value()
+	}
+	return values
+}
@@ -0,0 +1,64 @@
+package variables
+
+import (
+	"fmt"
+	"strings"
+
+	"skli/internal/tui/shared"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (s VariablesScreen) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (s VariablesScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			return s, func() tea.Msg { return shared.QuitMsg{} }
+		case "tab", "down":
+			return s.moveFocus(1)
+		case "shift+tab", "up":
+			return s.moveFocus(-1)
+		case "enter":
+			if s.Focus < len(s.Inputs)-1 {
+				return s.moveFocus(1)
+			}
+			return s.submit()
+		}
+	}
+
+	var cmd tea.Cmd
+	s.Inputs[s.Focus], cmd = s.Inputs[s.Focus].Update(msg)
+	return s, cmd
+}
+
+// submit continúa con la instalación si todas las variables obligatorias tienen valor
+func (s VariablesScreen) submit() (tea.Model, tea.Cmd) {
+	for i, v := range s.Variables {
+		if v.Required && strings.TrimSpace(s.Inputs[i].Value()) == "" {
+			s.ErrorMessage = fmt.Sprintf("%s is required", v.Name)
+			s.Inputs[s.Focus].Blur()
+			s.Focus = i
+			return s, s.Inputs[i].Focus()
+		}
+	}
+
+	install := s.Install
+	install.Values = s.values()
+	install.Prompted = true
+	return s, func() tea.Msg { return install }
+}
+
+func (s VariablesScreen) moveFocus(delta int) (tea.Model, tea.Cmd) {
+	next := s.Focus + delta
+	if next < 0 || next >= len(s.Inputs) {
+		return s, nil
+	}
+	s.Inputs[s.Focus].Blur()
+	s.Focus = next
+	return s, s.Inputs[s.Focus].Focus()
+}
@@ -0,0 +1,33 @@
+package variables
+
+import (
+	"strings"
+
+	"skli/internal/tui/shared"
+)
+
+func (s VariablesScreen) View() string {
+	var b strings.Builder
+	b.WriteString(shared.TitleStyle.Render("Skill variables") + "\n")
+	for i, v := range s.Variables {
+		label := v.Name
+		if v.Required {
+			label += " *"
+		}
+		if s.Focus == i {
+			b.WriteString(shared.SelectedItemStyle.Render(shared.SelectorDot(true)+" "+label) + "\n")
+		} else {
+			b.WriteString(shared.ItemStyle.Render(shared.SelectorDot(false)+" "+label) + "\n")
+		}
+		if v.Description != "" {
+			b.WriteString("  " + shared.DimStyle.Render(v.Description) + "\n")
+		}
+		b.WriteString("  " + s.Inputs[i].View() + "\n\n")
+	}
+
+	if s.ErrorMessage != "" {
+		b.WriteString(shared.ErrorStyle.Render("✘ "+s.ErrorMessage) + "\n")
+	}
+	b.WriteString(shared.HelpStyle.Render("\ntab/↑/↓ move • enter next/install • esc quit"))
+	return b.String()
+}
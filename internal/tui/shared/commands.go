@@ -1,58 +1,168 @@
 package shared
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 
 	"skli/internal/config"
+	"skli/internal/convert"
 	"skli/internal/db"
+	"skli/internal/editors"
 	"skli/internal/gitrepo"
+	"skli/internal/integrity"
+	"skli/internal/project"
+	"skli/internal/provenance"
+	"skli/internal/scaffold"
+	"skli/internal/skillvars"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-// ScanRepoCmd escanea un repositorio remoto
+// ScanRepoCmd escanea un repositorio remoto, analiza la seguridad de sus skills y verifica la firma del commit
 func ScanRepoCmd(url, defaultSkillsPath string) tea.Cmd {
 	return func() tea.Msg {
 		res, err := gitrepo.CloneAndScan(url, defaultSkillsPath)
-		return ScanResultMsg{Result: res, RemoteURL: url, Err: err}
+		if err != nil {
+			return ScanResultMsg{RemoteURL: url, Err: err}
+		}
+		msg := ScanResultMsg{Result: res, RemoteURL: url}
+		msg.Err = gitrepo.ScanSecurity(&msg.Result, url)
+		if msg.Err == nil {
+			msg.Warning, msg.Err = verifySource(&msg.Result, url)
+		}
+		if msg.Err != nil {
+			os.RemoveAll(res.TempDir)
+		}
+		return msg
+	}
+}
+
+// verifySource comprueba la firma del commit descargado y anota el firmante en cada skill.
+// Sin modo estricto una firma no verificada se devuelve como aviso.
+func verifySource(res *gitrepo.ScanResult, url string) (string, error) {
+	signer, err := provenance.Verify(res.TempDir, url, res.CommitHash, res.SkillsPath)
+	if err != nil {
+		if provenance.Strict() {
+			return "", err
+		}
+		return err.Error(), nil
+	}
+	for i := range res.Skills {
+		res.Skills[i].Signer = signer
 	}
+	return "", nil
 }
 
-// DownloadSkillsCmd descarga e instala skills seleccionadas
-func DownloadSkillsCmd(tempDir, remoteURL, skillsPath, localPath, commitHash string, selected []gitrepo.SkillInfo) tea.Cmd {
+// DownloadSkillsCmd descarga e instala skills seleccionadas en cada destino.
+// values son los valores de las variables que declaran los skills.
+func DownloadSkillsCmd(tempDir, remoteURL, skillsPath string, dests []Destination, values map[string]string, commitHash string, selected []gitrepo.SkillInfo) tea.Cmd {
 	return func() tea.Msg {
-		err := gitrepo.InstallSkills(tempDir, skillsPath, localPath, selected)
-		if err != nil {
-			os.RemoveAll(tempDir)
-			return DownloadResultMsg{Err: err}
+		defer os.RemoveAll(tempDir)
+		resolved := make([]map[string]string, len(selected))
+		for i, skill := range selected {
+			var err error
+			if resolved[i], err = skillvars.Resolve(skill.Variables, values); err != nil {
+				return DownloadResultMsg{Err: fmt.Errorf("%s: %w", skill.Name, err)}
+			}
+			// Las variables se sustituyen en la copia descargada antes de copiarla o convertirla,
+			// en el mismo orden que sync y verify
+			if err := skillvars.Render(gitrepo.SkillSource(tempDir, skillsPath, skill), resolved[i]); err != nil {
+				return DownloadResultMsg{Err: fmt.Errorf("error rendering %s: %w", skill.Name, err)}
+			}
+		}
+		for _, dest := range dests {
+			if err := installSkills(tempDir, remoteURL, skillsPath, dest, resolved, commitHash, selected); err != nil {
+				return DownloadResultMsg{Err: err}
+			}
+		}
+		return DownloadResultMsg{}
+	}
+}
+
+// installSkills copia los skills (ya renderizados) en la carpeta del destino y los registra en el lock
+func installSkills(tempDir, remoteURL, skillsPath string, dest Destination, values []map[string]string, commitHash string, selected []gitrepo.SkillInfo) error {
+	localPath := dest.Path
+	if localPath == "" {
+		localPath = gitrepo.DefaultSkillsPath
+	}
+	// Los destinos con conversor reciben el skill en su formato nativo en vez de la carpeta
+	target := TargetFor(dest.Editor, localPath)
+	converter := editors.Converter(target)
+	editor := ""
+	if target != nil {
+		editor = target.ID()
+	}
+
+	var err error
+	if converter == "" {
+		err = gitrepo.InstallSkills(tempDir, skillsPath, project.Resolve(localPath), selected)
+	} else {
+		err = exportSkills(converter, tempDir, skillsPath, localPath, selected)
+	}
+	if err != nil {
+		return err
+	}
+
+	paths := make([]string, len(selected))
+	for i, skill := range selected {
+		folderName := gitrepo.GetSkillFolderName(skill)
+		paths[i] = filepath.Join(localPath, folderName)
+		if converter != "" {
+			paths[i], _ = convert.ArtifactPath(converter, localPath, folderName)
 		}
+	}
 
-		for _, skill := range selected {
-			folderName := gitrepo.GetSkillFolderName(skill)
-			localSkillPath := filepath.Join(localPath, folderName)
-			instSkill := db.InstalledSkill{
+	return db.Update(func(lock *db.LockFile) error {
+		for i, skill := range selected {
+			manifest, err := integrity.Compute(project.Resolve(paths[i]))
+			if err != nil {
+				return err
+			}
+			lock.Upsert(db.InstalledSkill{
 				Name:        skill.Name,
 				Description: skill.Description,
-				Path:        localSkillPath,
+				Path:        paths[i],
 				RemoteRepo:  remoteURL,
 				RemoteRoot:  skillsPath,
 				RemotePath:  skill.Path,
 				CommitHash:  commitHash,
 				TreeHash:    skill.TreeHash,
-			}
-			db.SaveInstalledSkill(instSkill)
+				Files:       manifest,
+				Fields:      skill.Fields,
+				Values:      values[i],
+				Editor:      editor,
+				Converter:   converter,
+				Signer:      skill.Signer,
+			})
 		}
+		return nil
+	})
+}
 
-		os.RemoveAll(tempDir)
-		return DownloadResultMsg{Err: nil}
+// exportSkills genera con el conversor el artefacto de cada skill seleccionado en localPath
+func exportSkills(converter, tempDir, skillsPath, localPath string, selected []gitrepo.SkillInfo) error {
+	for _, skill := range selected {
+		src := gitrepo.SkillSource(tempDir, skillsPath, skill)
+		folderName := gitrepo.GetSkillFolderName(skill)
+		dest, err := convert.ArtifactPath(converter, project.Resolve(localPath), folderName)
+		if err != nil {
+			return err
+		}
+		if err := convert.Export(converter, src, dest); err != nil {
+			return fmt.Errorf("error installing %s: %w", skill.Name, err)
+		}
 	}
+	return nil
 }
 
-// SaveConfigCmd guarda la configuración
+// SaveConfigCmd guarda la ruta local y los remotes conservando el resto de la configuración
 func SaveConfigCmd(localPath string, remotes []string, navigateBack bool) tea.Cmd {
 	return func() tea.Msg {
-		_ = config.SaveConfig(config.Config{LocalPath: localPath, Remotes: remotes})
+		cfg, _ := config.LoadConfig()
+		cfg.LocalPath = localPath
+		cfg.Remotes = remotes
+		_ = config.SaveConfig(cfg)
 		return ConfigSavedMsg{
 			LocalPath:    localPath,
 			Remotes:      remotes,
@@ -61,11 +171,23 @@ func SaveConfigCmd(localPath string, remotes []string, navigateBack bool) tea.Cm
 	}
 }
 
+// CreateSkillCmd genera un skill nuevo a partir de una plantilla
+func CreateSkillCmd(tpl scaffold.Template, skillsRoot string, opts scaffold.Options) tea.Cmd {
+	return func() tea.Msg {
+		path, issues, err := scaffold.Create(tpl, skillsRoot, opts)
+		warnings := make([]string, 0, len(issues))
+		for _, issue := range issues {
+			warnings = append(warnings, issue.String())
+		}
+		return SkillCreatedMsg{Path: path, Warnings: warnings, Err: err}
+	}
+}
+
 // DeleteSkillCmd elimina un skill del lock file y del sistema de archivos
 func DeleteSkillCmd(skill db.InstalledSkill) tea.Cmd {
 	return func() tea.Msg {
 		// Eliminar del sistema de archivos
-		err := os.RemoveAll(skill.Path)
+		err := os.RemoveAll(project.Resolve(skill.Path))
 		if err != nil {
 			return NavigateToErrorMsg{Err: err}
 		}
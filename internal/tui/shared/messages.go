@@ -10,6 +10,7 @@ type NavigateToSkillsMsg struct {
 	RemoteURL  string
 	SkillsRoot string
 	CommitHash string
+	Warning    string // Firma del commit no verificada (sin modo estricto se puede instalar)
 }
 type NavigateToEditorMsg struct {
 	Skills     []Skill
@@ -19,12 +20,14 @@ type NavigateToEditorMsg struct {
 	CommitHash string
 }
 type NavigateToProgressMsg struct {
-	TempDir         string
-	RemoteURL       string
-	SkillsRoot      string
-	ConfigLocalPath string
-	CommitHash      string
-	Selected        []gitrepo.SkillInfo
+	TempDir      string
+	RemoteURL    string
+	SkillsRoot   string
+	Destinations []Destination
+	Values       map[string]string // Valores de las variables de los skills
+	Prompted     bool              // Ya se pidieron las variables (o no hacía falta)
+	CommitHash   string
+	Selected     []gitrepo.SkillInfo
 }
 type NavigateToConfigMsg struct{}
 type NavigateToManageRemotesMsg struct{}
@@ -52,9 +55,16 @@ type ConfigSavedMsg struct {
 type ScanResultMsg struct {
 	Result    gitrepo.ScanResult
 	RemoteURL string
+	Warning   string
 	Err       error
 }
 
 type DownloadResultMsg struct {
 	Err error
 }
+
+type SkillCreatedMsg struct {
+	Path     string
+	Warnings []string
+	Err      error
+}
@@ -0,0 +1,86 @@
+package shared
+
+import (
+	"strings"
+
+	"skli/internal/skillmeta"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// SkillTitle añade al nombre la versión y la marca de obsoleto
+func SkillTitle(name string, f skillmeta.Fields) string {
+	if f.Version != "" {
+		name += " v" + strings.TrimPrefix(f.Version, "v")
+	}
+	if f.Deprecated != "" {
+		name += " (deprecated)"
+	}
+	return name
+}
+
+// SkillDescription antepone las etiquetas a la descripción y añade el autor y la licencia
+func SkillDescription(desc string, f skillmeta.Fields) string {
+	var parts []string
+	if len(f.Tags) > 0 {
+		parts = append(parts, "#"+strings.Join(f.Tags, " #"))
+	}
+	if desc != "" {
+		parts = append(parts, desc)
+	}
+	if f.Author != "" {
+		parts = append(parts, "by "+f.Author)
+	}
+	if f.License != "" {
+		parts = append(parts, f.License)
+	}
+	if f.Deprecated != "" && f.Deprecated != "deprecated" {
+		parts = append(parts, "deprecated: "+f.Deprecated)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// SkillFilterValue es el texto de búsqueda de un skill: el nombre y sus etiquetas con "#"
+func SkillFilterValue(name string, f skillmeta.Fields) string {
+	if len(f.Tags) == 0 {
+		return name
+	}
+	return name + " #" + strings.Join(f.Tags, " #")
+}
+
+// TagFilter filtra por etiquetas cuando la búsqueda empieza por "#" (ej: "#go #cli" exige ambas,
+// aceptando prefijos mientras se escribe); el resto de búsquedas usan el filtro difuso por defecto.
+// Los textos de búsqueda deben construirse con SkillFilterValue.
+func TagFilter(term string, targets []string) []list.Rank {
+	term = strings.TrimSpace(term)
+	if !strings.HasPrefix(term, "#") {
+		return list.DefaultFilter(term, targets)
+	}
+
+	wanted := strings.Fields(strings.ToLower(term))
+	var ranks []list.Rank
+	for i, target := range targets {
+		_, tags, _ := strings.Cut(strings.ToLower(target), " #")
+		have := strings.Fields("#" + tags)
+		if len(tags) > 0 && matchesTags(have, wanted) {
+			ranks = append(ranks, list.Rank{Index: i})
+		}
+	}
+	return ranks
+}
+
+func matchesTags(have, wanted []string) bool {
+	for _, w := range wanted {
+		found := false
+		for _, h := range have {
+			if strings.HasPrefix(h, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
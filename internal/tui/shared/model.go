@@ -1,21 +1,78 @@
 package shared
 
-import "skli/internal/gitrepo"
+import (
+	"fmt"
+	"strings"
 
-// Editor representa un editor soportado
-type Editor struct {
-	Name string
-	Path string
+	"skli/internal/editors"
+	"skli/internal/gitrepo"
+	"skli/internal/security"
+)
+
+// Destination es una carpeta en la que instalar skills y el id de su destino (vacío = el que
+// instala en Path, si lo hay)
+type Destination struct {
+	Path   string
+	Editor string
+}
+
+// TargetDestinations devuelve la carpeta de cada destino
+func TargetDestinations(targets []editors.Target) []Destination {
+	dests := make([]Destination, len(targets))
+	for i, t := range targets {
+		dests[i] = Destination{Path: t.ProjectPath(), Editor: t.ID()}
+	}
+	return dests
+}
+
+// DestinationPaths devuelve las carpetas de los destinos para mostrarlas
+func DestinationPaths(dests []Destination) string {
+	paths := make([]string, len(dests))
+	for i, d := range dests {
+		paths[i] = "./" + d.Path + "/"
+	}
+	return strings.Join(paths, ", ")
+}
+
+// TargetFor devuelve el destino de id o, sin id, el que instala en localPath (nil si es una ruta propia)
+func TargetFor(id, localPath string) editors.Target {
+	if id != "" {
+		if t, err := editors.Get(id); err == nil {
+			return t
+		}
+	}
+	if t, ok := editors.ForPath(localPath); ok {
+		return t
+	}
+	return nil
+}
+
+// EditorWarnings avisa de los skills cuya lista de editores no incluye alguno de los destinos
+func EditorWarnings(selected []gitrepo.SkillInfo, dests []Destination) []string {
+	var warnings []string
+	for _, dest := range dests {
+		target := TargetFor(dest.Editor, dest.Path)
+		if target == nil {
+			continue
+		}
+		for _, sk := range selected {
+			if !editors.Compatible(sk.Fields, target) {
+				warnings = append(warnings, fmt.Sprintf("%s declares support for %s, not %s", sk.Name, strings.Join(sk.Editors, ", "), target.Name()))
+			}
+		}
+	}
+	return warnings
 }
 
-// Editors lista de editores soportados
-var Editors = []Editor{
-	{Name: "Windsurf", Path: ".windsurf/skills"},
-	{Name: "Antigravity", Path: ".antigravity/skills"},
-	{Name: "Cursor", Path: ".cursor/skills"},
-	{Name: "VSCode", Path: ".vscode/skills"},
-	{Name: "OpenCode", Path: ".opencode/skills"},
-	{Name: "Custom", Path: ""},
+// SecurityWarnings recuerda los hallazgos de seguridad de los skills que se instalan
+func SecurityWarnings(selected []gitrepo.SkillInfo) []string {
+	var warnings []string
+	for _, sk := range selected {
+		if len(sk.Findings) > 0 {
+			warnings = append(warnings, fmt.Sprintf("%s was installed with %s", sk.Name, security.Summary(sk.Findings)))
+		}
+	}
+	return warnings
 }
 
 // Skill representa una habilidad encontrada en el repositorio
@@ -17,6 +17,7 @@ var (
 	ColorTextDim    = lipgloss.Color("#7D8596")
 	ColorDanger     = lipgloss.Color("#FF6B6B")
 	ColorSuccess    = lipgloss.Color("#7CFCB2")
+	ColorWarning    = lipgloss.Color("#F4C95D")
 
 	TitleStyle = lipgloss.NewStyle().
 			Bold(true).
@@ -61,6 +62,8 @@ var (
 			Bold(true).
 			MarginTop(1)
 
+	WarningStyle = lipgloss.NewStyle().Foreground(ColorWarning)
+
 	DimStyle = lipgloss.NewStyle().Foreground(ColorTextDim)
 
 	InfoStyle = lipgloss.NewStyle().Foreground(ColorAccent)
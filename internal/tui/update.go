@@ -1,6 +1,10 @@
 package tui
 
 import (
+	"skli/internal/editors"
+	"skli/internal/gitrepo"
+	"skli/internal/skillmeta"
+	"skli/internal/skillvars"
 	"skli/internal/tui/screens/config"
 	"skli/internal/tui/screens/editor"
 	"skli/internal/tui/screens/manage"
@@ -8,6 +12,7 @@ import (
 	"skli/internal/tui/screens/remote"
 	"skli/internal/tui/screens/scanning"
 	"skli/internal/tui/screens/skills"
+	"skli/internal/tui/screens/variables"
 	"skli/internal/tui/shared"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -53,10 +58,20 @@ func (m RootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.activeScreen.Init()
 
 	case shared.NavigateToSkillsMsg:
-		m.activeScreen = skills.NewSkillsScreen(msg.Skills, msg.TempDir, msg.RemoteURL, msg.SkillsRoot, msg.CommitHash, m.configLocalPath)
+		// Con --editor no se usa la ruta configurada: la pantalla de editores instala en los destinos fijados
+		localPath := m.configLocalPath
+		if len(m.editors) > 0 {
+			localPath = ""
+		}
+		screen := skills.NewSkillsScreen(msg.Skills, msg.TempDir, msg.RemoteURL, msg.SkillsRoot, msg.CommitHash, localPath)
+		screen.Warning = msg.Warning
+		m.activeScreen = screen
 		return m, m.activeScreen.Init()
 
 	case shared.NavigateToEditorMsg:
+		if len(msg.Skills) > 0 && len(m.editors) > 0 {
+			return m, installToEditors(msg, m.editors)
+		}
 		if len(msg.Skills) > 0 {
 			// Desde skills selection
 			m.activeScreen = editor.NewEditorScreen(msg.Skills, msg.TempDir, msg.RemoteURL, msg.SkillsRoot, msg.CommitHash, false, m.remotes)
@@ -75,9 +90,19 @@ func (m RootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.activeScreen.Init()
 
 	case shared.NavigateToProgressMsg:
-		screen, cmd := progress.NewProgressScreenDownloading(msg.TempDir, msg.RemoteURL, msg.SkillsRoot, msg.ConfigLocalPath, msg.CommitHash, msg.Selected)
+		msg.Values = withValues(m.values, msg.Values)
+		if !msg.Prompted {
+			// Pedir las variables de los skills que no se dieron con --set
+			if vars := unsetVariables(msg.Selected, m.values); len(vars) > 0 {
+				m.activeScreen = variables.NewVariablesScreen(msg, vars)
+				return m, m.activeScreen.Init()
+			}
+		}
+		screen, cmd := progress.NewProgressScreenDownloading(msg.TempDir, msg.RemoteURL, msg.SkillsRoot, msg.Destinations, msg.Values, msg.CommitHash, msg.Selected)
 		m.activeScreen = screen
-		m.configLocalPath = msg.ConfigLocalPath
+		if len(msg.Destinations) > 0 {
+			m.configLocalPath = msg.Destinations[0].Path
+		}
 		return m, cmd
 
 	case shared.NavigateToDoneMsg:
@@ -111,3 +136,61 @@ func (m RootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	m.activeScreen, cmd = m.activeScreen.Update(msg)
 	return m, cmd
 }
+
+// installToEditors instala los skills seleccionados en los destinos fijados con --editor
+func installToEditors(msg shared.NavigateToEditorMsg, ids []string) tea.Cmd {
+	targets := make([]editors.Target, 0, len(ids))
+	for _, id := range ids {
+		t, err := editors.Get(id)
+		if err != nil {
+			return func() tea.Msg { return shared.NavigateToErrorMsg{Err: err} }
+		}
+		targets = append(targets, t)
+	}
+	var selected []gitrepo.SkillInfo
+	for _, sk := range msg.Skills {
+		if sk.Selected {
+			selected = append(selected, sk.Info)
+		}
+	}
+	return func() tea.Msg {
+		return shared.NavigateToProgressMsg{
+			TempDir:      msg.TempDir,
+			RemoteURL:    msg.RemoteURL,
+			SkillsRoot:   msg.SkillsRoot,
+			Destinations: shared.TargetDestinations(targets),
+			CommitHash:   msg.CommitHash,
+			Selected:     selected,
+		}
+	}
+}
+
+// withValues combina los valores de --set con los introducidos en el formulario (tienen prioridad)
+func withValues(set, entered map[string]string) map[string]string {
+	if len(set) == 0 {
+		return entered
+	}
+	values := make(map[string]string, len(set)+len(entered))
+	for name, value := range set {
+		values[name] = value
+	}
+	for name, value := range entered {
+		values[name] = value
+	}
+	return values
+}
+
+// unsetVariables devuelve las variables de los skills seleccionados sin valor en set
+func unsetVariables(selected []gitrepo.SkillInfo, set map[string]string) []skillmeta.Variable {
+	fields := make([]skillmeta.Fields, len(selected))
+	for i, sk := range selected {
+		fields[i] = sk.Fields
+	}
+	var out []skillmeta.Variable
+	for _, v := range skillvars.Declared(fields...) {
+		if _, ok := set[v.Name]; !ok {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,94 @@
+package validate
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Enlaces e imágenes de Markdown: [texto](destino "título"), ![alt](<destino>)
+	linkPattern       = regexp.MustCompile(`!?\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+[^)]*)?\)`)
+	inlineCodePattern = regexp.MustCompile("`[^`]*`")
+	schemePattern     = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:`)
+	drivePattern      = regexp.MustCompile(`^[A-Za-z]:[\\/]`)
+
+	// Rutas a directorios de usuario, que solo existen en la máquina del autor
+	homePathPattern = regexp.MustCompile(`(?:^|[\s"'(=:])(/Users/[^/\s]+/|/home/[^/\s]+/|[A-Za-z]:\\Users\\)`)
+)
+
+// checkText revisa un fichero de texto del skill: permisos de ejecución, rutas absolutas
+// y, en los .md, que los enlaces relativos apunten a ficheros incluidos en el skill.
+func checkText(dir, rel string, mode fs.FileMode, content []byte) []Issue {
+	var issues []Issue
+	if mode&0111 != 0 && !bytes.HasPrefix(content, []byte("#!")) {
+		issues = append(issues, Issue{Severity: SeverityWarning, Rule: "executable-file", Path: rel,
+			Message: "file is executable but is not a script with a shebang (#!)"})
+	}
+
+	markdown := strings.EqualFold(filepath.Ext(rel), ".md")
+	inFence := false
+	for i, line := range strings.Split(string(content), "\n") {
+		if m := homePathPattern.FindStringSubmatch(line); m != nil {
+			issues = append(issues, Issue{Severity: SeverityWarning, Rule: "absolute-path", Path: rel, Line: i + 1,
+				Message: fmt.Sprintf("absolute path to a user directory (%s) will not exist on other machines", m[1])})
+		}
+		if !markdown {
+			continue
+		}
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		for _, m := range linkPattern.FindAllStringSubmatch(inlineCodePattern.ReplaceAllString(line, ""), -1) {
+			if issue, ok := checkLink(dir, rel, m[1]); ok {
+				issue.Line = i + 1
+				issues = append(issues, issue)
+			}
+		}
+	}
+	return issues
+}
+
+// checkLink comprueba un destino de enlace de Markdown; las URLs y anclas se ignoran
+func checkLink(dir, rel, target string) (Issue, bool) {
+	if strings.HasPrefix(target, "#") || strings.HasPrefix(target, "//") {
+		return Issue{}, false
+	}
+	if strings.HasPrefix(target, "/") || drivePattern.MatchString(target) || strings.HasPrefix(strings.ToLower(target), "file:") {
+		return Issue{Severity: SeverityError, Rule: "absolute-path", Path: rel,
+			Message: fmt.Sprintf("link to absolute path %s, use a path relative to the skill", target)}, true
+	}
+	if schemePattern.MatchString(target) {
+		return Issue{}, false // http:, mailto:...
+	}
+
+	target, _, _ = strings.Cut(target, "#")
+	target, _, _ = strings.Cut(target, "?")
+	if decoded, err := url.PathUnescape(target); err == nil {
+		target = decoded
+	}
+	if target == "" {
+		return Issue{}, false
+	}
+
+	resolved := path.Join(path.Dir(rel), target)
+	if resolved == ".." || strings.HasPrefix(resolved, "../") {
+		return Issue{Severity: SeverityError, Rule: "broken-link", Path: rel,
+			Message: fmt.Sprintf("link to %s points outside the skill", target)}, true
+	}
+	if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(resolved))); err != nil {
+		return Issue{Severity: SeverityError, Rule: "broken-link", Path: rel,
+			Message: fmt.Sprintf("link to missing file %s", target)}, true
+	}
+	return Issue{}, false
+}
@@ -0,0 +1,222 @@
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"skli/internal/skillmeta"
+)
+
+// Rule describe una comprobación; el ID es el valor de Issue.Rule
+type Rule struct {
+	ID          string
+	Description string
+}
+
+// Rules son todas las comprobaciones de Skill y Lint
+var Rules = []Rule{
+	{"skill-file", "The skill folder has a SKILL.md file"},
+	{"frontmatter", "SKILL.md starts with a valid YAML frontmatter block"},
+	{"frontmatter-name", "The skill has a lowercase name that matches its folder"},
+	{"frontmatter-description", "The skill has a description of reasonable length"},
+	{"duplicate-name", "Skill names are unique within the repository"},
+	{"broken-link", "Relative links point to files bundled with the skill"},
+	{"absolute-path", "Links and paths do not depend on the author's machine"},
+	{"file-size", "Bundled files are smaller than the size limit"},
+	{"binary-file", "Bundled files are text, except images"},
+	{"executable-file", "Only scripts with a shebang are executable"},
+	{"editor-junk", "No editor or OS leftovers are bundled"},
+}
+
+// SkillReport son los problemas de un skill encontrado por Lint
+type SkillReport struct {
+	Name   string  `json:"name"`
+	Path   string  `json:"path"` // Carpeta del skill
+	Issues []Issue `json:"issues"`
+}
+
+// Report es el resultado de Lint
+type Report struct {
+	Skills []SkillReport `json:"skills"`
+}
+
+// Count devuelve el número de errores y de avisos
+func (r Report) Count() (errors, warnings int) {
+	for _, sk := range r.Skills {
+		errors += len(Errors(sk.Issues))
+		warnings += len(Warnings(sk.Issues))
+	}
+	return errors, warnings
+}
+
+// Lint valida el skill de dir o, si dir no tiene SKILL.md, todos los skills que contenga,
+// comprobando además que no repitan nombre. Las rutas del informe son las de disco.
+func Lint(dir string) (Report, error) {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return Report{}, fmt.Errorf("invalid directory: %s", dir)
+	}
+
+	var skillDirs []string
+	err = filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && (d.Name() == ".git" || d.Name() == "node_modules") {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && d.Name() == "SKILL.md" {
+			skillDirs = append(skillDirs, filepath.Dir(p))
+		}
+		return nil
+	})
+	if err != nil {
+		return Report{}, fmt.Errorf("error searching skills: %w", err)
+	}
+	if len(skillDirs) == 0 {
+		return Report{}, fmt.Errorf("no skills (SKILL.md files) found in %s", dir)
+	}
+
+	var report Report
+	byName := make(map[string][]int)
+	for _, skillDir := range skillDirs {
+		issues, err := Skill(skillDir)
+		if err != nil {
+			return Report{}, err
+		}
+		name := filepath.Base(skillDir)
+		if meta, err := skillmeta.ParseFile(filepath.Join(skillDir, "SKILL.md")); err == nil && meta.Name != "" {
+			name = meta.Name
+		}
+		byName[name] = append(byName[name], len(report.Skills))
+		report.Skills = append(report.Skills, SkillReport{Name: name, Path: skillDir, Issues: issues})
+	}
+
+	for name, indexes := range byName {
+		if len(indexes) < 2 {
+			continue
+		}
+		for _, i := range indexes {
+			var others []string
+			for _, j := range indexes {
+				if j != i {
+					rel, _ := filepath.Rel(dir, report.Skills[j].Path)
+					others = append(others, filepath.ToSlash(rel))
+				}
+			}
+			report.Skills[i].Issues = append(report.Skills[i].Issues, Issue{Severity: SeverityError, Rule: "duplicate-name", Path: "SKILL.md",
+				Message: fmt.Sprintf("name '%s' is also used by %s", name, strings.Join(others, ", "))})
+		}
+	}
+	return report, nil
+}
+
+// WriteJSON escribe el informe en JSON junto con el recuento de errores y avisos
+func (r Report) WriteJSON(w io.Writer) error {
+	errs, warnings := r.Count()
+	for i := range r.Skills {
+		if r.Skills[i].Issues == nil {
+			r.Skills[i].Issues = []Issue{}
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Skills   []SkillReport `json:"skills"`
+		Errors   int           `json:"errors"`
+		Warnings int           `json:"warnings"`
+	}{r.Skills, errs, warnings})
+}
+
+// Estructuras mínimas de SARIF 2.1.0 para publicar los resultados en code scanning
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		Version        string      `json:"version,omitempty"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+	sarifRule struct {
+		ID               string       `json:"id"`
+		ShortDescription sarifMessage `json:"shortDescription"`
+	}
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+	sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+	}
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifact `json:"artifactLocation"`
+		Region           *sarifRegion  `json:"region,omitempty"`
+	}
+	sarifArtifact struct {
+		URI string `json:"uri"`
+	}
+	sarifRegion struct {
+		StartLine int `json:"startLine"`
+	}
+)
+
+// WriteSARIF escribe el informe en SARIF 2.1.0; las rutas de los skills deben ser relativas
+// a la raíz del repositorio para que los resultados se asocien a sus ficheros.
+func (r Report) WriteSARIF(w io.Writer, toolVersion string) error {
+	driver := sarifDriver{Name: "skli", Version: toolVersion, InformationURI: "https://github.com/Bafoj/Skli"}
+	for _, rule := range Rules {
+		driver.Rules = append(driver.Rules, sarifRule{ID: rule.ID, ShortDescription: sarifMessage{Text: rule.Description}})
+	}
+
+	results := []sarifResult{}
+	for _, sk := range r.Skills {
+		for _, issue := range sk.Issues {
+			uri := filepath.ToSlash(sk.Path)
+			if issue.Path != "" {
+				uri += "/" + issue.Path
+			}
+			loc := sarifPhysicalLocation{ArtifactLocation: sarifArtifact{URI: uri}}
+			if issue.Line > 0 {
+				loc.Region = &sarifRegion{StartLine: issue.Line}
+			}
+			results = append(results, sarifResult{
+				RuleID:    issue.Rule,
+				Level:     string(issue.Severity),
+				Message:   sarifMessage{Text: issue.Message},
+				Locations: []sarifLocation{{PhysicalLocation: loc}},
+			})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Locations[0].PhysicalLocation.ArtifactLocation.URI < results[j].Locations[0].PhysicalLocation.ArtifactLocation.URI
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
@@ -0,0 +1,243 @@
+package validate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"skli/internal/skillmeta"
+)
+
+// Severity indica si un problema bloquea la subida o solo se avisa
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Límites de los skills subidos
+const (
+	MaxFileSize          = 1 << 20 // 1 MiB por fichero
+	MaxNameLength        = 64
+	MaxDescriptionLength = 1024
+)
+
+// Issue es un problema encontrado en un skill
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Rule     string   `json:"rule"`           // Identificador estable de la comprobación (ej: "binary-file")
+	Path     string   `json:"path,omitempty"` // Fichero relativo al skill con "/"; vacío si afecta al skill entero
+	Line     int      `json:"line,omitempty"` // Línea del fichero (desde 1); 0 si no aplica
+	Message  string   `json:"message"`
+}
+
+func (i Issue) String() string {
+	if i.Path == "" {
+		return fmt.Sprintf("%s: %s", i.Severity, i.Message)
+	}
+	if i.Line > 0 {
+		return fmt.Sprintf("%s: %s:%d: %s", i.Severity, i.Path, i.Line, i.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", i.Severity, i.Path, i.Message)
+}
+
+// Error agrupa los errores de validación que impiden subir un skill
+type Error struct {
+	Skill  string
+	Issues []Issue
+}
+
+func (e *Error) Error() string {
+	lines := []string{fmt.Sprintf("skill '%s' failed validation:", e.Skill)}
+	for _, issue := range e.Issues {
+		lines = append(lines, "  "+issue.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Check valida el skill y devuelve un *Error si tiene errores; los avisos se devuelven aparte
+func Check(name, dir string) ([]Issue, error) {
+	issues, err := Skill(dir)
+	if err != nil {
+		return nil, err
+	}
+	if errs := Errors(issues); len(errs) > 0 {
+		return Warnings(issues), &Error{Skill: name, Issues: errs}
+	}
+	return issues, nil
+}
+
+// Errors filtra los problemas que bloquean la subida
+func Errors(issues []Issue) []Issue {
+	return filter(issues, SeverityError)
+}
+
+// Warnings filtra los avisos
+func Warnings(issues []Issue) []Issue {
+	return filter(issues, SeverityWarning)
+}
+
+func filter(issues []Issue, severity Severity) []Issue {
+	var out []Issue
+	for _, issue := range issues {
+		if issue.Severity == severity {
+			out = append(out, issue)
+		}
+	}
+	return out
+}
+
+var namePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ValidName indica si name sigue el formato de nombre de skill: minúsculas, dígitos y guiones
+func ValidName(name string) bool {
+	return len(name) <= MaxNameLength && namePattern.MatchString(name)
+}
+
+// Skill comprueba el frontmatter de SKILL.md y los ficheros del skill
+func Skill(dir string) ([]Issue, error) {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("invalid skill directory: %s", dir)
+	}
+
+	issues := checkFrontmatter(dir)
+	fileIssues, err := checkFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	return append(issues, fileIssues...), nil
+}
+
+func checkFrontmatter(dir string) []Issue {
+	skillFile := filepath.Join(dir, "SKILL.md")
+	if !skillmeta.HasFrontmatter(skillFile) {
+		if _, err := os.Stat(skillFile); err != nil {
+			return []Issue{{Severity: SeverityError, Rule: "skill-file", Path: "SKILL.md", Message: "missing SKILL.md"}}
+		}
+		return []Issue{{Severity: SeverityError, Rule: "frontmatter", Path: "SKILL.md", Message: "missing frontmatter (--- block at the top)"}}
+	}
+
+	meta, err := skillmeta.ParseFile(skillFile)
+	if err != nil {
+		line := 0
+		var perr *skillmeta.ParseError
+		if errors.As(err, &perr) {
+			perr.File = "" // El issue ya apunta a SKILL.md
+			line = perr.Line
+		}
+		return []Issue{{Severity: SeverityError, Rule: "frontmatter", Path: "SKILL.md", Line: line, Message: err.Error()}}
+	}
+
+	var issues []Issue
+	switch {
+	case meta.Name == "":
+		issues = append(issues, Issue{Severity: SeverityError, Rule: "frontmatter-name", Path: "SKILL.md", Message: "missing required field 'name'"})
+	case !ValidName(meta.Name):
+		issues = append(issues, Issue{Severity: SeverityWarning, Rule: "frontmatter-name", Path: "SKILL.md",
+			Message: fmt.Sprintf("name '%s' should be lowercase letters, digits and hyphens (max %d)", meta.Name, MaxNameLength)})
+	case meta.Name != filepath.Base(dir):
+		issues = append(issues, Issue{Severity: SeverityWarning, Rule: "frontmatter-name", Path: "SKILL.md",
+			Message: fmt.Sprintf("name '%s' does not match folder '%s'", meta.Name, filepath.Base(dir))})
+	}
+	switch {
+	case meta.Description == "":
+		issues = append(issues, Issue{Severity: SeverityError, Rule: "frontmatter-description", Path: "SKILL.md", Message: "missing required field 'description'"})
+	case len(meta.Description) > MaxDescriptionLength:
+		issues = append(issues, Issue{Severity: SeverityWarning, Rule: "frontmatter-description", Path: "SKILL.md",
+			Message: fmt.Sprintf("description is longer than %d characters", MaxDescriptionLength)})
+	}
+	return issues
+}
+
+// Restos de editores, sistemas operativos y herramientas que no deben acabar en el repo
+var (
+	junkNames    = []string{".DS_Store", "Thumbs.db", "desktop.ini", ".idea", ".vscode", "__pycache__", "node_modules", ".git"}
+	junkSuffixes = []string{".swp", ".swo", "~", ".orig", ".rej", ".bak", ".tmp", ".pyc"}
+)
+
+// Binarios habituales en skills (imágenes de ejemplo) que se aceptan si no superan el tamaño máximo
+var allowedBinaryExts = map[string]bool{".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".webp": true, ".ico": true}
+
+func isJunk(name string) bool {
+	for _, junk := range junkNames {
+		if name == junk {
+			return true
+		}
+	}
+	for _, suffix := range junkSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return strings.HasPrefix(name, ".#") || (len(name) > 1 && strings.HasPrefix(name, "#") && strings.HasSuffix(name, "#"))
+}
+
+func checkFiles(dir string) ([]Issue, error) {
+	var issues []Issue
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == dir {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, p)
+		rel = filepath.ToSlash(rel)
+
+		if isJunk(d.Name()) {
+			issues = append(issues, Issue{Severity: SeverityError, Rule: "editor-junk", Path: rel, Message: "editor or OS leftover, remove it"})
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > MaxFileSize {
+			issues = append(issues, Issue{Severity: SeverityError, Rule: "file-size", Path: rel,
+				Message: fmt.Sprintf("file is %s, larger than %s", formatSize(info.Size()), formatSize(MaxFileSize))})
+			return nil
+		}
+		if allowedBinaryExts[strings.ToLower(filepath.Ext(p))] {
+			return nil
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		// Misma heurística que git: un byte nulo en los primeros 8000 bytes
+		if bytes.IndexByte(content[:min(len(content), 8000)], 0) >= 0 {
+			issues = append(issues, Issue{Severity: SeverityError, Rule: "binary-file", Path: rel, Message: "binary file"})
+			return nil
+		}
+		issues = append(issues, checkText(dir, rel, info.Mode(), content)...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading skill files: %w", err)
+	}
+	return issues, nil
+}
+
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
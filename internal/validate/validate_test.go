@@ -0,0 +1,253 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func writeSkill(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "demo")
+	for name, content := range files {
+		full := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func rules(issues []Issue) string {
+	out := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		out = append(out, string(issue.Severity)+":"+issue.Rule+":"+issue.Path)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestSkillValid(t *testing.T) {
+	dir := writeSkill(t, map[string]string{
+		"SKILL.md":       "---\nname: demo\ndescription: does things\n---\nbody\n",
+		"scripts/run.sh": "echo hi\n",
+		"img/logo.png":   "\x89PNG\x00\x00",
+	})
+	issues, err := Skill(dir)
+	if err != nil {
+		t.Fatalf("Skill: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("expected no issues, got %s", rules(issues))
+	}
+}
+
+func TestSkillFrontmatter(t *testing.T) {
+	issues, _ := Skill(writeSkill(t, map[string]string{"SKILL.md": "# no frontmatter\n"}))
+	if rules(issues) != "error:frontmatter:SKILL.md" {
+		t.Fatalf("unexpected issues: %s", rules(issues))
+	}
+
+	issues, _ = Skill(writeSkill(t, map[string]string{"SKILL.md": "---\nname: Other Name\n---\n"}))
+	if rules(issues) != "warning:frontmatter-name:SKILL.md,error:frontmatter-description:SKILL.md" {
+		t.Fatalf("unexpected issues: %s", rules(issues))
+	}
+
+	issues, _ = Skill(writeSkill(t, map[string]string{"README.md": "x"}))
+	if rules(issues) != "error:skill-file:SKILL.md" {
+		t.Fatalf("unexpected issues: %s", rules(issues))
+	}
+
+	// Misma regla que skillmeta: se ignoran las líneas en blanco antes del frontmatter
+	issues, _ = Skill(writeSkill(t, map[string]string{"SKILL.md": "\n\n---\nname: demo\ndescription: does things\n---\n"}))
+	if len(issues) != 0 {
+		t.Fatalf("leading blank lines must be accepted, got %s", rules(issues))
+	}
+
+	issues, _ = Skill(writeSkill(t, map[string]string{"SKILL.md": "---\nname: demo\ndescription: does things\n"}))
+	if rules(issues) != "error:frontmatter:SKILL.md" || !strings.Contains(issues[0].Message, "not closed") {
+		t.Fatalf("expected an unclosed frontmatter error, got %+v", issues)
+	}
+
+	issues, _ = Skill(writeSkill(t, map[string]string{"SKILL.md": "---\nname: demo\ndescription: [unclosed\n---\n"}))
+	if rules(issues) != "error:frontmatter:SKILL.md" || !strings.HasPrefix(issues[0].Message, "line ") {
+		t.Fatalf("expected a YAML error with its line, got %+v", issues)
+	}
+}
+
+func TestSkillFiles(t *testing.T) {
+	dir := writeSkill(t, map[string]string{
+		"SKILL.md":            "---\nname: demo\ndescription: d\n---\n",
+		".DS_Store":           "x",
+		"notes.md~":           "x",
+		".idea/workspace.xml": "x",
+		"bin/tool":            "ELF\x00\x01",
+		"data/big.txt":        strings.Repeat("a", MaxFileSize+1),
+	})
+	issues, err := Skill(dir)
+	if err != nil {
+		t.Fatalf("Skill: %v", err)
+	}
+	got := rules(issues)
+	for _, want := range []string{
+		"error:editor-junk:.DS_Store",
+		"error:editor-junk:notes.md~",
+		"error:editor-junk:.idea",
+		"error:binary-file:bin/tool",
+		"error:file-size:data/big.txt",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %s in %s", want, got)
+		}
+	}
+	if strings.Contains(got, "workspace.xml") {
+		t.Errorf("junk directories must be reported once: %s", got)
+	}
+}
+
+func TestCheckSplitsErrorsAndWarnings(t *testing.T) {
+	dir := writeSkill(t, map[string]string{"SKILL.md": "---\nname: Demo\n---\n"})
+	warnings, err := Check("demo", dir)
+	var verr *Error
+	if !errors.As(err, &verr) || !strings.Contains(err.Error(), "missing required field 'description'") {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if len(verr.Issues) != 1 || len(warnings) != 1 {
+		t.Fatalf("unexpected split: errors=%v warnings=%v", verr, warnings)
+	}
+}
+
+func TestSkillLinksAndPaths(t *testing.T) {
+	dir := writeSkill(t, map[string]string{
+		"SKILL.md": "---\nname: demo\ndescription: d\n---\n" +
+			"See [guide](references/guide.md#setup), [site](https://example.com) and [top](#demo).\n" +
+			"Missing: [api](references/api%20v2.md) ![logo](img/logo.png)\n" +
+			"Outside: [other](../other/SKILL.md) and [abs](/etc/passwd)\n" +
+			"```\n[not a link](nowhere.md)\n```\n" +
+			"Inline `[code](nowhere.md)` is ignored.\n",
+		"references/guide.md": "Back to [skill](../SKILL.md). Config in /Users/jane/.config/tool\n",
+	})
+	issues, err := Skill(dir)
+	if err != nil {
+		t.Fatalf("Skill: %v", err)
+	}
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.Rule+":"+issue.Path+":"+strconv.Itoa(issue.Line))
+	}
+	want := "broken-link:SKILL.md:6,broken-link:SKILL.md:6,broken-link:SKILL.md:7,absolute-path:SKILL.md:7,absolute-path:references/guide.md:1"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("unexpected issues:\n got %s\nwant %s\n%v", strings.Join(got, ","), want, issues)
+	}
+}
+
+func TestSkillExecutableFiles(t *testing.T) {
+	dir := writeSkill(t, map[string]string{
+		"SKILL.md":       "---\nname: demo\ndescription: d\n---\n",
+		"scripts/run.sh": "#!/bin/sh\necho hi\n",
+		"notes.txt":      "plain text\n",
+	})
+	os.Chmod(filepath.Join(dir, "scripts", "run.sh"), 0755)
+	os.Chmod(filepath.Join(dir, "notes.txt"), 0755)
+
+	issues, _ := Skill(dir)
+	if rules(issues) != "warning:executable-file:notes.txt" {
+		t.Fatalf("unexpected issues: %s", rules(issues))
+	}
+}
+
+func TestLintRepo(t *testing.T) {
+	root := t.TempDir()
+	for path, content := range map[string]string{
+		"skills/a/SKILL.md":       "---\nname: shared\ndescription: d\n---\n",
+		"skills/b/SKILL.md":       "---\nname: shared\ndescription: d\n---\n",
+		"skills/c/SKILL.md":       "---\ndescription: [unclosed\n---\n",
+		"skills/ok/SKILL.md":      "---\nname: ok\ndescription: d\n---\n",
+		".git/x/SKILL.md":         "ignored",
+		"node_modules/y/SKILL.md": "ignored",
+	} {
+		full := filepath.Join(root, path)
+		os.MkdirAll(filepath.Dir(full), 0755)
+		os.WriteFile(full, []byte(content), 0644)
+	}
+
+	report, err := Lint(root)
+	if err != nil {
+		t.Fatalf("Lint: %v", err)
+	}
+	if len(report.Skills) != 4 {
+		t.Fatalf("expected 4 skills, got %+v", report.Skills)
+	}
+	byFolder := make(map[string]string)
+	for _, sk := range report.Skills {
+		byFolder[filepath.Base(sk.Path)] = rules(sk.Issues)
+	}
+	if !strings.Contains(byFolder["a"], "error:duplicate-name") || !strings.Contains(byFolder["b"], "error:duplicate-name") {
+		t.Fatalf("duplicate names must be reported on both skills: %v", byFolder)
+	}
+	if byFolder["c"] != "error:frontmatter:SKILL.md" || byFolder["ok"] != "" {
+		t.Fatalf("unexpected issues: %v", byFolder)
+	}
+	if errs, warnings := report.Count(); errs != 3 || warnings != 2 {
+		t.Fatalf("unexpected count: %d errors, %d warnings", errs, warnings)
+	}
+
+	if _, err := Lint(t.TempDir()); err == nil {
+		t.Fatal("expected an error when there are no skills")
+	}
+}
+
+func TestReportSARIF(t *testing.T) {
+	report := Report{Skills: []SkillReport{{Name: "demo", Path: "skills/demo", Issues: []Issue{
+		{Severity: SeverityError, Rule: "broken-link", Path: "SKILL.md", Line: 7, Message: "link to missing file x.md"},
+		{Severity: SeverityWarning, Rule: "executable-file", Path: "notes.txt", Message: "executable"},
+	}}}}
+
+	var buf bytes.Buffer
+	if err := report.WriteSARIF(&buf, "1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	var log struct {
+		Version string
+		Runs    []struct {
+			Tool struct {
+				Driver struct{ Rules []struct{ ID string } }
+			}
+			Results []struct {
+				RuleID    string
+				Level     string
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct{ URI string }
+						Region           *struct{ StartLine int }
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Tool.Driver.Rules) != len(Rules) {
+		t.Fatalf("unexpected SARIF log: %s", buf.String())
+	}
+	results := log.Runs[0].Results
+	if len(results) != 2 || results[0].RuleID != "broken-link" || results[0].Level != "error" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	loc := results[0].Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "skills/demo/SKILL.md" || loc.Region == nil || loc.Region.StartLine != 7 {
+		t.Fatalf("unexpected location: %+v", loc)
+	}
+	if results[1].Locations[0].PhysicalLocation.Region != nil {
+		t.Fatal("issues without a line must not have a region")
+	}
+}